@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"regexp"
 	"strings"
 )
 
@@ -108,6 +109,103 @@ func ScanForPattern(pattern []byte) bufio.SplitFunc {
 	}
 }
 
+// ScanRegex is a split function for a Scanner that uses matches of re as
+// separators and returns the text between them.
+// Matches are only accepted once more data follows them, so separators that
+// could extend across buffer boundaries are not cut short.
+func ScanRegex(re *regexp.Regexp) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		for _, loc := range re.FindAllIndex(data, -1) {
+			// Empty matches would never advance the scanner
+			if loc[1] == loc[0] {
+				continue
+			}
+			if loc[1] < len(data) || atEOF {
+				return loc[1], data[:loc[0]], nil
+			}
+			break
+		}
+
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
+
+// ScanAnyDelimiter is a split function for a Scanner that splits on the
+// earliest occurrence of any of the given delimiters. When several delimiters
+// match at the same position, the longest one wins.
+func ScanAnyDelimiter(delims ...[]byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		// A delimiter at the very end may be the prefix of a longer one
+		if i, n := indexAny(data, delims); i >= 0 && (atEOF || i+n < len(data)) {
+			return i + n, data[:i], nil
+		}
+
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
+
+// ScanWithDelimiters is a split function for a Scanner that splits on any of
+// the given delimiters and returns the delimiters themselves as separate tokens.
+// This makes it suitable as the basis of a simple lexer.
+func ScanWithDelimiters(delims ...[]byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		i, n := indexAny(data, delims)
+		if !atEOF && i >= 0 && i+n == len(data) {
+			// A delimiter at the very end may be the prefix of a longer one
+			return 0, nil, nil
+		}
+
+		switch {
+		case i == 0:
+			// Delimiter at the start: emit it as its own token
+			return n, data[:n], nil
+		case i > 0:
+			return i, data[:i], nil
+		}
+
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
+
+// indexAny returns the index and length of the earliest, longest delimiter in data.
+func indexAny(data []byte, delims [][]byte) (int, int) {
+	best, size := -1, 0
+	for _, d := range delims {
+		if len(d) == 0 {
+			continue
+		}
+		i := bytes.Index(data, d)
+		if i < 0 {
+			continue
+		}
+		if best < 0 || i < best || (i == best && len(d) > size) {
+			best, size = i, len(d)
+		}
+	}
+	return best, size
+}
+
 // CreateScanner creates a new bufio.Scanner with a larger buffer for handling
 // long lines or binary data.
 func CreateScanner(r io.Reader, maxCapacity int) *bufio.Scanner {
@@ -0,0 +1,156 @@
+package bufioExt
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding identifies the text encoding detected from a byte order mark.
+type Encoding int
+
+const (
+	EncodingUnknown Encoding = iota // No BOM found; data is passed through unchanged
+	EncodingUTF8
+	EncodingUTF16LE
+	EncodingUTF16BE
+)
+
+// String returns the conventional name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingUTF16LE:
+		return "UTF-16LE"
+	case EncodingUTF16BE:
+		return "UTF-16BE"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// TextReader wraps a reader, strips any leading byte order mark and
+// transparently decodes UTF-16 input to UTF-8.
+type TextReader struct {
+	r        *bufio.Reader
+	encoding Encoding
+	pending  []byte
+	err      error
+}
+
+// NewTextReader creates a TextReader that sniffs the BOM from r.
+// Input without a BOM is assumed to be UTF-8 and returned unchanged.
+func NewTextReader(r io.Reader) (*TextReader, error) {
+	br := bufio.NewReader(r)
+	tr := &TextReader{r: br}
+
+	head, err := br.Peek(3)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		tr.encoding = EncodingUTF8
+		br.Discard(len(bomUTF8))
+	case bytes.HasPrefix(head, bomUTF16LE):
+		tr.encoding = EncodingUTF16LE
+		br.Discard(len(bomUTF16LE))
+	case bytes.HasPrefix(head, bomUTF16BE):
+		tr.encoding = EncodingUTF16BE
+		br.Discard(len(bomUTF16BE))
+	}
+
+	return tr, nil
+}
+
+// Encoding returns the encoding detected from the BOM.
+func (tr *TextReader) Encoding() Encoding {
+	return tr.encoding
+}
+
+// Read implements io.Reader, returning UTF-8 data.
+func (tr *TextReader) Read(p []byte) (int, error) {
+	if tr.encoding != EncodingUTF16LE && tr.encoding != EncodingUTF16BE {
+		return tr.r.Read(p)
+	}
+
+	for len(tr.pending) == 0 {
+		if tr.err != nil {
+			return 0, tr.err
+		}
+		tr.fill()
+	}
+
+	n := copy(p, tr.pending)
+	tr.pending = tr.pending[n:]
+	return n, nil
+}
+
+// fill decodes the next chunk of UTF-16 code units into pending.
+func (tr *TextReader) fill() {
+	var order func([]byte) uint16
+	if tr.encoding == EncodingUTF16LE {
+		order = func(b []byte) uint16 { return uint16(b[0]) | uint16(b[1])<<8 }
+	} else {
+		order = func(b []byte) uint16 { return uint16(b[0])<<8 | uint16(b[1]) }
+	}
+
+	buf := make([]byte, 4096)
+	n, err := io.ReadAtLeast(tr.r, buf, 2)
+	if err == io.ErrUnexpectedEOF {
+		// A dangling odd byte cannot form a code unit
+		tr.pending = utf8.AppendRune(tr.pending, utf8.RuneError)
+		tr.err = io.EOF
+		return
+	}
+	if err != nil {
+		tr.err = err
+		return
+	}
+
+	// Keep an odd trailing byte for the next round
+	if n%2 == 1 {
+		tr.r.UnreadByte()
+		n--
+	}
+
+	units := make([]uint16, 0, n/2+1)
+	for i := 0; i < n; i += 2 {
+		units = append(units, order(buf[i:i+2]))
+	}
+
+	// Don't split a surrogate pair across chunks
+	if last := units[len(units)-1]; utf16.IsSurrogate(rune(last)) && last < 0xDC00 {
+		if next, err := tr.r.Peek(2); err == nil {
+			units = append(units, order(next))
+			tr.r.Discard(2)
+		}
+	}
+
+	for _, r := range utf16.Decode(units) {
+		tr.pending = utf8.AppendRune(tr.pending, r)
+	}
+}
+
+// DecodeText detects the BOM in data and returns its contents as a UTF-8 string.
+func DecodeText(data []byte) (string, Encoding, error) {
+	tr, err := NewTextReader(bytes.NewReader(data))
+	if err != nil {
+		return "", EncodingUnknown, err
+	}
+	out, err := io.ReadAll(tr)
+	if err != nil {
+		return "", tr.Encoding(), err
+	}
+	return string(out), tr.Encoding(), nil
+}
@@ -0,0 +1,105 @@
+package bufioExt
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ContextReader wraps an io.ReadCloser so that blocked reads are aborted when
+// the context is canceled. Cancellation closes the underlying reader, which is
+// the only portable way to interrupt a read on pipes, terminals and sockets.
+type ContextReader struct {
+	ctx  context.Context
+	rc   io.ReadCloser
+	stop func() bool
+	once sync.Once
+	err  error
+}
+
+// NewContextReader creates a ContextReader that closes rc once ctx is done.
+func NewContextReader(ctx context.Context, rc io.ReadCloser) *ContextReader {
+	cr := &ContextReader{ctx: ctx, rc: rc}
+	cr.stop = context.AfterFunc(ctx, func() {
+		cr.close()
+	})
+	return cr
+}
+
+// Read implements io.Reader. Once the context is done it returns the context's error.
+func (cr *ContextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := cr.rc.Read(p)
+	if err != nil {
+		// Report cancellation rather than the error caused by closing rc
+		if ctxErr := cr.ctx.Err(); ctxErr != nil {
+			return n, ctxErr
+		}
+	}
+	return n, err
+}
+
+// Close closes the underlying reader and releases the context watcher.
+func (cr *ContextReader) Close() error {
+	cr.stop()
+	return cr.close()
+}
+
+func (cr *ContextReader) close() error {
+	cr.once.Do(func() {
+		cr.err = cr.rc.Close()
+	})
+	return cr.err
+}
+
+// ReadLineContext reads a single line from r, returning early with the
+// context's error if ctx is done first. The returned line has its line ending removed.
+// Pass a *bufio.Reader when reading several lines so buffered data is not lost
+// between calls.
+//
+// If r also implements io.Closer it is closed on cancellation to unblock the
+// pending read; otherwise the read keeps running in the background and r
+// must not be used again. A *bufio.Reader is never an io.Closer, so to have
+// cancellation unblock it, build it over a ContextReader:
+//
+//	br := bufio.NewReader(bufioExt.NewContextReader(ctx, conn))
+//	line, err := bufioExt.ReadLineContext(ctx, br)
+func ReadLineContext(ctx context.Context, r io.Reader) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	type result struct {
+		line string
+		err  error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		line, err := br.ReadString('\n')
+		done <- result{strings.TrimRight(line, "\r\n"), err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err == io.EOF && res.line != "" {
+			return res.line, nil
+		}
+		return res.line, res.err
+	case <-ctx.Done():
+		if c, ok := r.(io.Closer); ok {
+			c.Close()
+		}
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,163 @@
+package bufioExt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ErrLineTooLong is returned when a line exceeds the LineReader's maximum length.
+var ErrLineTooLong = errors.New("bufio: line too long")
+
+// Line holds a single line of text along with its position in the input.
+type Line struct {
+	Text   string // Line contents without the trailing "\n" or "\r\n"
+	Number int    // 1-based line number
+	Offset int64  // Byte offset of the first byte of the line
+}
+
+// LineError describes a failure while reading a specific line.
+type LineError struct {
+	Number int
+	Offset int64
+	Err    error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d (offset %d): %v", e.Number, e.Offset, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineReader reads lines one at a time while tracking line numbers and byte offsets.
+type LineReader struct {
+	r       *bufio.Reader
+	maxLen  int
+	number  int
+	offset  int64
+	err     error
+	current Line
+}
+
+// NewLineReader creates a LineReader over r. A maxLen of zero or less
+// disables the line length limit.
+func NewLineReader(r io.Reader, maxLen int) *LineReader {
+	return &LineReader{r: bufio.NewReader(r), maxLen: maxLen}
+}
+
+// Next advances to the next line, returning false when input is exhausted
+// or an error occurred. Use Line to get the current line and Err to check for errors.
+func (lr *LineReader) Next() bool {
+	if lr.err != nil {
+		return false
+	}
+
+	line, err := lr.readLine()
+	if err != nil {
+		lr.err = err
+		return false
+	}
+	lr.current = line
+	return true
+}
+
+// Line returns the most recent line read by Next.
+func (lr *LineReader) Line() Line {
+	return lr.current
+}
+
+// Err returns the first non-EOF error encountered by the LineReader.
+func (lr *LineReader) Err() error {
+	if lr.err == io.EOF {
+		return nil
+	}
+	return lr.err
+}
+
+// Lines returns an iterator over the remaining lines. Iteration stops at
+// the first error, which is then available from Err.
+func (lr *LineReader) Lines() iter.Seq[Line] {
+	return func(yield func(Line) bool) {
+		for lr.Next() {
+			if !yield(lr.current) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the remaining lines paired with any read error.
+// The final pair carries the error if reading failed before EOF.
+func (lr *LineReader) All() iter.Seq2[Line, error] {
+	return func(yield func(Line, error) bool) {
+		for lr.Next() {
+			if !yield(lr.current, nil) {
+				return
+			}
+		}
+		if err := lr.Err(); err != nil {
+			yield(Line{}, err)
+		}
+	}
+}
+
+// readLine reads a single line, enforcing the configured length limit
+// without buffering more than maxLen bytes of an oversized line.
+func (lr *LineReader) readLine() (Line, error) {
+	start := lr.offset
+	var buf []byte
+	read := 0
+
+	for {
+		chunk, err := lr.r.ReadSlice('\n')
+		read += len(chunk)
+		buf = append(buf, chunk...)
+
+		n := contentLen(buf)
+		// A '\r' ending a partial line may be the start of "\r\n", so it
+		// only counts once the next byte shows it is not.
+		if err == bufio.ErrBufferFull && buf[len(buf)-1] == '\r' {
+			n--
+		}
+		if lr.maxLen > 0 && n > lr.maxLen {
+			lr.offset += int64(read)
+			return Line{}, &LineError{Number: lr.number + 1, Offset: start,
+				Err: fmt.Errorf("%w: exceeds %d bytes", ErrLineTooLong, lr.maxLen)}
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil && err != io.EOF {
+			lr.offset += int64(read)
+			return Line{}, &LineError{Number: lr.number + 1, Offset: start, Err: err}
+		}
+		if err == io.EOF && read == 0 {
+			return Line{}, io.EOF
+		}
+		break
+	}
+
+	lr.number++
+	lr.offset += int64(read)
+	return Line{Text: string(trimEOL(buf)), Number: lr.number, Offset: start}, nil
+}
+
+// contentLen returns the length of a line excluding its line terminator.
+func contentLen(b []byte) int {
+	return len(trimEOL(b))
+}
+
+func trimEOL(b []byte) []byte {
+	if n := len(b); n > 0 && b[n-1] == '\n' {
+		b = b[:n-1]
+		if n := len(b); n > 0 && b[n-1] == '\r' {
+			b = b[:n-1]
+		}
+	}
+	return b
+}
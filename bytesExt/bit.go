@@ -0,0 +1,192 @@
+package bytesExt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// BitSet is a set of non-negative integers stored as a growable bit array.
+// The zero value is an empty set ready to use.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet returns an empty set with room for n bits before it grows.
+func NewBitSet(n int) *BitSet {
+	return &BitSet{words: make([]uint64, 0, (n+63)/64)}
+}
+
+func checkBit(i int) {
+	if i < 0 {
+		panic(fmt.Sprintf("bytesExt: negative bit index %d", i))
+	}
+}
+
+// Set adds bit i, growing the set as needed.
+func (s *BitSet) Set(i int) {
+	checkBit(i)
+	w := i / 64
+	if w >= len(s.words) {
+		s.words = append(s.words, make([]uint64, w+1-len(s.words))...)
+	}
+	s.words[w] |= 1 << (i % 64)
+}
+
+// Clear removes bit i.
+func (s *BitSet) Clear(i int) {
+	checkBit(i)
+	if w := i / 64; w < len(s.words) {
+		s.words[w] &^= 1 << (i % 64)
+	}
+}
+
+// Test reports whether bit i is set.
+func (s *BitSet) Test(i int) bool {
+	checkBit(i)
+	w := i / 64
+	return w < len(s.words) && s.words[w]&(1<<(i%64)) != 0
+}
+
+// Len returns the number of bits the set can hold without growing.
+func (s *BitSet) Len() int {
+	return len(s.words) * 64
+}
+
+// Count returns the number of set bits.
+func (s *BitSet) Count() int {
+	n := 0
+	for _, w := range s.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// NextSet returns the first set bit at or after i, and false if there is
+// none. Iterate with:
+//
+//	for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) { ... }
+func (s *BitSet) NextSet(i int) (int, bool) {
+	checkBit(i)
+	w := i / 64
+	if w >= len(s.words) {
+		return 0, false
+	}
+	word := s.words[w] >> (i % 64)
+	if word != 0 {
+		return i + bits.TrailingZeros64(word), true
+	}
+	for w++; w < len(s.words); w++ {
+		if s.words[w] != 0 {
+			return w*64 + bits.TrailingZeros64(s.words[w]), true
+		}
+	}
+	return 0, false
+}
+
+// Clone returns a copy of the set.
+func (s *BitSet) Clone() *BitSet {
+	return &BitSet{words: append([]uint64(nil), s.words...)}
+}
+
+// Equal reports whether both sets hold the same bits.
+func (s *BitSet) Equal(other *BitSet) bool {
+	a, b := s.words, other.words
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	for i, w := range a {
+		if i < len(b) {
+			if w != b[i] {
+				return false
+			}
+		} else if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// And keeps only the bits also set in other.
+func (s *BitSet) And(other *BitSet) {
+	for i := range s.words {
+		if i < len(other.words) {
+			s.words[i] &= other.words[i]
+		} else {
+			s.words[i] = 0
+		}
+	}
+}
+
+// Or adds the bits set in other.
+func (s *BitSet) Or(other *BitSet) {
+	s.fit(len(other.words))
+	for i, w := range other.words {
+		s.words[i] |= w
+	}
+}
+
+// Xor toggles the bits set in other.
+func (s *BitSet) Xor(other *BitSet) {
+	s.fit(len(other.words))
+	for i, w := range other.words {
+		s.words[i] ^= w
+	}
+}
+
+// AndNot removes the bits set in other.
+func (s *BitSet) AndNot(other *BitSet) {
+	for i := range min(len(s.words), len(other.words)) {
+		s.words[i] &^= other.words[i]
+	}
+}
+
+// fit grows the set to at least n words.
+func (s *BitSet) fit(n int) {
+	if n > len(s.words) {
+		s.words = append(s.words, make([]uint64, n-len(s.words))...)
+	}
+}
+
+// String formats the set as its set bits, like {1 5 64}.
+func (s *BitSet) String() string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) {
+		if sb.Len() > 1 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(strconv.Itoa(i))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// MarshalBinary encodes the set as little-endian 64-bit words, dropping
+// trailing empty words.
+func (s *BitSet) MarshalBinary() ([]byte, error) {
+	n := len(s.words)
+	for n > 0 && s.words[n-1] == 0 {
+		n--
+	}
+	b := make([]byte, 0, n*8)
+	for _, w := range s.words[:n] {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary replaces the set with one encoded by MarshalBinary.
+func (s *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return fmt.Errorf("could not decode bit set: length %d is not a multiple of 8", len(data))
+	}
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+	s.words = words
+	return nil
+}
@@ -0,0 +1,355 @@
+package bytesExt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var (
+	// ErrVarintOverflow is returned for a varint longer than 64 bits.
+	ErrVarintOverflow = errors.New("varint overflows 64 bits")
+	// ErrStringTooLong is returned when a string does not fit its length prefix.
+	ErrStringTooLong = errors.New("string too long for length prefix")
+)
+
+// Reader decodes binary data from a byte slice, advancing an offset as it
+// goes. The first failure is kept and makes every later read return zero,
+// so a run of reads can be checked once with Err.
+type Reader struct {
+	buf   []byte
+	off   int
+	order binary.ByteOrder
+	err   error
+}
+
+// NewReader returns a Reader over b that decodes multi-byte values in order.
+func NewReader(b []byte, order binary.ByteOrder) *Reader {
+	return &Reader{buf: b, order: order}
+}
+
+// Err returns the first error encountered, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Offset returns the number of bytes consumed.
+func (r *Reader) Offset() int {
+	return r.off
+}
+
+// Remaining returns the number of unread bytes.
+func (r *Reader) Remaining() int {
+	return len(r.buf) - r.off
+}
+
+// next consumes n bytes, or records an error and returns nil if there are
+// fewer left or an earlier read failed.
+func (r *Reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > r.Remaining() {
+		r.err = fmt.Errorf("%w: need %d at offset %d, have %d", ErrInsufficientBytes, n, r.off, r.Remaining())
+		return nil
+	}
+	b := r.buf[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// Skip advances past n bytes.
+func (r *Reader) Skip(n int) {
+	r.next(n)
+}
+
+// Bytes returns the next n bytes. The result aliases the underlying slice.
+func (r *Reader) Bytes(n int) []byte {
+	return r.next(n)
+}
+
+// Read implements io.Reader, copying from the unread bytes.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.Remaining() == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n := copy(p, r.buf[r.off:])
+	r.off += n
+	return n, nil
+}
+
+// Uint8 reads one byte.
+func (r *Reader) Uint8() uint8 {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// Uint16 reads a 2-byte unsigned integer.
+func (r *Reader) Uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return r.order.Uint16(b)
+	}
+	return 0
+}
+
+// Uint32 reads a 4-byte unsigned integer.
+func (r *Reader) Uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return r.order.Uint32(b)
+	}
+	return 0
+}
+
+// Uint64 reads an 8-byte unsigned integer.
+func (r *Reader) Uint64() uint64 {
+	if b := r.next(8); b != nil {
+		return r.order.Uint64(b)
+	}
+	return 0
+}
+
+// Int8 reads one byte as a signed integer.
+func (r *Reader) Int8() int8 {
+	return int8(r.Uint8())
+}
+
+// Int16 reads a 2-byte signed integer.
+func (r *Reader) Int16() int16 {
+	return int16(r.Uint16())
+}
+
+// Int32 reads a 4-byte signed integer.
+func (r *Reader) Int32() int32 {
+	return int32(r.Uint32())
+}
+
+// Int64 reads an 8-byte signed integer.
+func (r *Reader) Int64() int64 {
+	return int64(r.Uint64())
+}
+
+// Float32 reads a 4-byte IEEE 754 float.
+func (r *Reader) Float32() float32 {
+	return math.Float32frombits(r.Uint32())
+}
+
+// Float64 reads an 8-byte IEEE 754 float.
+func (r *Reader) Float64() float64 {
+	return math.Float64frombits(r.Uint64())
+}
+
+// Uvarint reads an unsigned varint as written by binary.PutUvarint.
+func (r *Reader) Uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf[r.off:])
+	switch {
+	case n == 0:
+		r.err = fmt.Errorf("%w: truncated varint at offset %d", ErrInsufficientBytes, r.off)
+		return 0
+	case n < 0:
+		r.err = fmt.Errorf("%w at offset %d", ErrVarintOverflow, r.off)
+		return 0
+	}
+	r.off += n
+	return v
+}
+
+// Varint reads a zig-zag encoded signed varint as written by binary.PutVarint.
+func (r *Reader) Varint() int64 {
+	u := r.Uvarint()
+	v := int64(u >> 1)
+	if u&1 != 0 {
+		v = ^v
+	}
+	return v
+}
+
+// PrefixedBytes reads a uvarint length followed by that many bytes. The
+// result aliases the underlying slice.
+func (r *Reader) PrefixedBytes() []byte {
+	start := r.off
+	n := r.Uvarint()
+	if r.err == nil && n > uint64(r.Remaining()) {
+		r.err = fmt.Errorf("%w: need %d at offset %d, have %d", ErrInsufficientBytes, n, start, r.Remaining())
+	}
+	return r.next(int(n))
+}
+
+// PrefixedString reads a string with a uvarint length prefix.
+func (r *Reader) PrefixedString() string {
+	return string(r.PrefixedBytes())
+}
+
+// String16 reads a string with a 2-byte length prefix.
+func (r *Reader) String16() string {
+	return string(r.next(int(r.Uint16())))
+}
+
+// String32 reads a string with a 4-byte length prefix.
+func (r *Reader) String32() string {
+	n := r.Uint32()
+	if r.err == nil && uint64(n) > uint64(r.Remaining()) {
+		r.err = fmt.Errorf("%w: need %d at offset %d, have %d", ErrInsufficientBytes, n, r.off, r.Remaining())
+	}
+	return string(r.next(int(n)))
+}
+
+// Writer encodes binary data into a growing byte slice. Like Reader it keeps
+// the first error, which only length-prefixed strings that do not fit their
+// prefix can cause.
+type Writer struct {
+	buf   []byte
+	order binary.ByteOrder
+	err   error
+}
+
+// NewWriter returns a Writer that encodes multi-byte values in order.
+func NewWriter(order binary.ByteOrder) *Writer {
+	return &Writer{order: order}
+}
+
+// Err returns the first error encountered, if any.
+func (w *Writer) Err() error {
+	return w.err
+}
+
+// Len returns the number of bytes written, which is also the offset of the
+// next write.
+func (w *Writer) Len() int {
+	return len(w.buf)
+}
+
+// Bytes returns the encoded data. It aliases the Writer's buffer until the
+// next write.
+func (w *Writer) Bytes() []byte {
+	return w.buf
+}
+
+// Reset discards the written data and any error, keeping the buffer.
+func (w *Writer) Reset() {
+	w.buf = w.buf[:0]
+	w.err = nil
+}
+
+// Write implements io.Writer, appending p.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// Uint8 writes one byte.
+func (w *Writer) Uint8(v uint8) {
+	w.buf = append(w.buf, v)
+}
+
+// Uint16 writes a 2-byte unsigned integer.
+func (w *Writer) Uint16(v uint16) {
+	w.order.PutUint16(w.grow(2), v)
+}
+
+// Uint32 writes a 4-byte unsigned integer.
+func (w *Writer) Uint32(v uint32) {
+	w.order.PutUint32(w.grow(4), v)
+}
+
+// Uint64 writes an 8-byte unsigned integer.
+func (w *Writer) Uint64(v uint64) {
+	w.order.PutUint64(w.grow(8), v)
+}
+
+// Int8 writes a signed byte.
+func (w *Writer) Int8(v int8) {
+	w.Uint8(uint8(v))
+}
+
+// Int16 writes a 2-byte signed integer.
+func (w *Writer) Int16(v int16) {
+	w.Uint16(uint16(v))
+}
+
+// Int32 writes a 4-byte signed integer.
+func (w *Writer) Int32(v int32) {
+	w.Uint32(uint32(v))
+}
+
+// Int64 writes an 8-byte signed integer.
+func (w *Writer) Int64(v int64) {
+	w.Uint64(uint64(v))
+}
+
+// Float32 writes a 4-byte IEEE 754 float.
+func (w *Writer) Float32(v float32) {
+	w.Uint32(math.Float32bits(v))
+}
+
+// Float64 writes an 8-byte IEEE 754 float.
+func (w *Writer) Float64(v float64) {
+	w.Uint64(math.Float64bits(v))
+}
+
+// Uvarint writes an unsigned varint.
+func (w *Writer) Uvarint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+// Varint writes a zig-zag encoded signed varint.
+func (w *Writer) Varint(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+// PrefixedBytes writes b preceded by its length as a uvarint.
+func (w *Writer) PrefixedBytes(b []byte) {
+	w.Uvarint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// PrefixedString writes s preceded by its length as a uvarint.
+func (w *Writer) PrefixedString(s string) {
+	w.Uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// String16 writes s preceded by a 2-byte length, failing if s is longer
+// than 65535 bytes.
+func (w *Writer) String16(s string) {
+	if len(s) > math.MaxUint16 {
+		w.setErr(fmt.Errorf("%w: %d bytes at offset %d", ErrStringTooLong, len(s), len(w.buf)))
+		return
+	}
+	w.Uint16(uint16(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// String32 writes s preceded by a 4-byte length.
+func (w *Writer) String32(s string) {
+	if uint64(len(s)) > math.MaxUint32 {
+		w.setErr(fmt.Errorf("%w: %d bytes at offset %d", ErrStringTooLong, len(s), len(w.buf)))
+		return
+	}
+	w.Uint32(uint32(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// grow extends the buffer by n bytes and returns them.
+func (w *Writer) grow(n int) []byte {
+	w.buf = append(w.buf, make([]byte, n)...)
+	return w.buf[len(w.buf)-n:]
+}
+
+func (w *Writer) setErr(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
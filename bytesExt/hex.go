@@ -0,0 +1,122 @@
+package bytesExt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDiffRegions is the number of differing regions DiffBytes shows.
+const DefaultDiffRegions = 3
+
+const hexRowSize = 16
+
+// HexDump formats b like xxd: each row holds 16 bytes as an offset, the
+// bytes in hex in pairs, and the printable ASCII characters, with dots for
+// the rest.
+func HexDump(b []byte) string {
+	var sb strings.Builder
+	for start := 0; start < len(b); start += hexRowSize {
+		writeHexRow(&sb, b, start, nil)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// writeHexRow writes the row of data starting at start. Bytes past the end
+// of data are left blank so rows from slices of different lengths line up.
+// If diff is non-nil, a second line marks the bytes for which it is true.
+func writeHexRow(sb *strings.Builder, data []byte, start int, diff func(int) bool) {
+	fmt.Fprintf(sb, "%08x: ", start)
+	var marks strings.Builder
+	for i := range hexRowSize {
+		cell, mark := "  ", "  "
+		if start+i < len(data) {
+			cell = fmt.Sprintf("%02x", data[start+i])
+		}
+		if diff != nil && diff(start+i) {
+			mark = "^^"
+		}
+		sb.WriteString(cell)
+		marks.WriteString(mark)
+		if i%2 == 1 && i < hexRowSize-1 {
+			sb.WriteByte(' ')
+			marks.WriteByte(' ')
+		}
+	}
+	sb.WriteString("  ")
+	for i := start; i < start+hexRowSize && i < len(data); i++ {
+		if c := data[i]; c >= 0x20 && c < 0x7f {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	if diff != nil {
+		sb.WriteString("\n  ")
+		sb.WriteString(strings.Repeat(" ", len("00000000: ")))
+		sb.WriteString(strings.TrimRight(marks.String(), " "))
+	}
+}
+
+// DiffBytes describes how b differs from a, showing the first
+// DefaultDiffRegions differing regions. It returns "" if they are equal.
+func DiffBytes(a, b []byte) string {
+	return DiffBytesN(a, b, DefaultDiffRegions)
+}
+
+// DiffBytesN describes how b differs from a as hex dump rows of each side
+// with the differing bytes marked, showing at most n differing regions.
+// Bytes past the end of the shorter slice count as different. It returns ""
+// if a and b are equal.
+func DiffBytesN(a, b []byte, n int) string {
+	differs := func(i int) bool {
+		if i >= len(a) || i >= len(b) {
+			return i < max(len(a), len(b))
+		}
+		return a[i] != b[i]
+	}
+
+	// Collect regions of differing bytes, merging ones in adjacent rows so
+	// each row is shown once.
+	type region struct{ start, end int }
+	var regions []region
+	for i := 0; i < max(len(a), len(b)); i++ {
+		if !differs(i) {
+			continue
+		}
+		if k := len(regions) - 1; k >= 0 && i/hexRowSize <= regions[k].end/hexRowSize+1 {
+			regions[k].end = i
+			continue
+		}
+		regions = append(regions, region{i, i})
+	}
+	if len(regions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	if len(a) != len(b) {
+		fmt.Fprintf(&sb, "length %d != %d\n", len(a), len(b))
+	}
+	for k, r := range regions {
+		if k == n {
+			fmt.Fprintf(&sb, "... %d more differing regions\n", len(regions)-n)
+			break
+		}
+		count := 0
+		for i := r.start; i <= r.end; i++ {
+			if differs(i) {
+				count++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ 0x%x-0x%x: %d bytes differ\n", r.start, r.end, count)
+		for row := r.start / hexRowSize * hexRowSize; row <= r.end; row += hexRowSize {
+			sb.WriteString("- ")
+			writeHexRow(&sb, a, row, nil)
+			sb.WriteString("\n+ ")
+			writeHexRow(&sb, b, row, differs)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
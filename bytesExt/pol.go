@@ -0,0 +1,292 @@
+package bytesExt
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"runtime"
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// Default size range of a BufferPool. Requests above the maximum are
+// allocated directly and never pooled.
+const (
+	DefaultPoolMinSize = 512
+	DefaultPoolMaxSize = 16 << 20
+)
+
+// PoolOption configures a BufferPool.
+type PoolOption func(*BufferPool)
+
+// PoolSizeRange sets the smallest and largest pooled capacities, which are
+// rounded up to powers of two.
+func PoolSizeRange(minSize, maxSize int) PoolOption {
+	return func(p *BufferPool) {
+		p.minSize = roundUpPow2(minSize)
+		p.maxSize = roundUpPow2(max(minSize, maxSize))
+	}
+}
+
+// PoolDebug enables misuse detection: putting a buffer twice, or a
+// bytes.Buffer the pool did not hand out, panics; buffers are poisoned on
+// Put so writes after Put are caught by the next Get; and Outstanding and
+// Leaks report buffers that were never returned. A slice that append moved
+// to a new array no longer counts as the one Get returned, so it shows up as
+// a leak; pass a larger size hint. Debug mode slows every call, so enable it
+// in tests rather than production.
+func PoolDebug() PoolOption {
+	return func(p *BufferPool) {
+		p.debug = true
+	}
+}
+
+// BufferPool recycles byte slices and bytes.Buffers in power-of-two size
+// classes, so callers get a buffer big enough for their size hint without
+// pooled small buffers being grown or large ones wasted on small requests.
+// It is safe for concurrent use.
+type BufferPool struct {
+	minSize int
+	maxSize int
+	slices  []sync.Pool // []byte by size class, stored as *[]byte
+	buffers []sync.Pool // *bytes.Buffer by size class
+
+	debug bool
+	mu    sync.Mutex
+	out   map[any]string // outstanding buffers and where they were taken
+}
+
+// DefaultBufferPool is the pool used by the package-level helpers.
+var DefaultBufferPool = NewBufferPool()
+
+// NewBufferPool returns a pool configured by opts.
+func NewBufferPool(opts ...PoolOption) *BufferPool {
+	p := &BufferPool{minSize: DefaultPoolMinSize, maxSize: DefaultPoolMaxSize}
+	for _, opt := range opts {
+		opt(p)
+	}
+	classes := bits.Len(uint(p.maxSize)) - bits.Len(uint(p.minSize)) + 1
+	p.slices = make([]sync.Pool, classes)
+	p.buffers = make([]sync.Pool, classes)
+	if p.debug {
+		p.out = make(map[any]string)
+	}
+	return p
+}
+
+// roundUpPow2 returns the smallest power of two at least n, and 1 for n < 1.
+func roundUpPow2(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(n-1))
+}
+
+// getClass returns the class whose buffers hold at least size bytes, or -1
+// if size is beyond the pooled range.
+func (p *BufferPool) getClass(size int) int {
+	if size > p.maxSize {
+		return -1
+	}
+	size = max(size, p.minSize)
+	return bits.Len(uint(roundUpPow2(size))) - bits.Len(uint(p.minSize))
+}
+
+// putClass returns the class a buffer of the given capacity can serve, or -1
+// if it is too small or too large to keep.
+func (p *BufferPool) putClass(capacity int) int {
+	if capacity < p.minSize || capacity > p.maxSize {
+		return -1
+	}
+	return bits.Len(uint(capacity)) - bits.Len(uint(p.minSize))
+}
+
+func (p *BufferPool) classSize(class int) int {
+	return p.minSize << class
+}
+
+// Get returns an empty slice with capacity of at least sizeHint.
+func (p *BufferPool) Get(sizeHint int) []byte {
+	class := p.getClass(sizeHint)
+	if class < 0 {
+		return make([]byte, 0, sizeHint)
+	}
+	var b []byte
+	if v := p.slices[class].Get(); v != nil {
+		b = (*v.(*[]byte))[:0]
+	} else {
+		b = make([]byte, 0, p.classSize(class))
+	}
+	p.track(unsafe.SliceData(b[:cap(b)]), b)
+	return b
+}
+
+// Put returns b to the pool. The caller must not use b, or any slice
+// sharing its memory, afterwards.
+func (p *BufferPool) Put(b []byte) {
+	class := p.putClass(cap(b))
+	if p.debug && cap(b) > 0 {
+		p.untrackSlice(b, class >= 0)
+	}
+	if class < 0 {
+		return
+	}
+	b = b[:0]
+	p.slices[class].Put(&b)
+}
+
+// GetBuffer returns an empty bytes.Buffer with capacity of at least sizeHint.
+func (p *BufferPool) GetBuffer(sizeHint int) *bytes.Buffer {
+	class := p.getClass(sizeHint)
+	if class < 0 {
+		return bytes.NewBuffer(make([]byte, 0, sizeHint))
+	}
+	var buf *bytes.Buffer
+	if v := p.buffers[class].Get(); v != nil {
+		buf = v.(*bytes.Buffer)
+	} else {
+		buf = bytes.NewBuffer(make([]byte, 0, p.classSize(class)))
+	}
+	p.track(buf, buf.Bytes())
+	return buf
+}
+
+// PutBuffer resets buf and returns it to the pool. The caller must not use
+// buf, or slices obtained from its Bytes method, afterwards.
+func (p *BufferPool) PutBuffer(buf *bytes.Buffer) {
+	buf.Reset()
+	b := buf.Bytes()
+	class := p.putClass(cap(b))
+	if p.debug {
+		if !p.release(buf) {
+			panic("bytesExt: PutBuffer of a buffer that is not outstanding (double Put or not from this pool)")
+		}
+		if class >= 0 {
+			poisonBytes(b)
+		}
+	}
+	if class < 0 {
+		return
+	}
+	p.buffers[class].Put(buf)
+}
+
+const poison = 0xDE
+
+// track checks in debug mode that b was not written since it was pooled and
+// records key as outstanding.
+func (p *BufferPool) track(key any, b []byte) {
+	if !p.debug {
+		return
+	}
+	for i, c := range b[:cap(b)] {
+		if c != poison && c != 0 {
+			panic(fmt.Sprintf("bytesExt: pooled buffer modified after Put (offset %d)", i))
+		}
+	}
+	p.mu.Lock()
+	p.out[key] = callerStack()
+	p.mu.Unlock()
+}
+
+// release removes key from the outstanding buffers, reporting whether it was
+// there.
+func (p *BufferPool) release(key any) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.out[key]
+	delete(p.out, key)
+	return ok
+}
+
+// untrackSlice checks a slice returned in debug mode. An unknown slice is a
+// double Put if it is still entirely poison and otherwise a grown or donated
+// slice, which is accepted.
+func (p *BufferPool) untrackSlice(b []byte, pooled bool) {
+	full := b[:cap(b)]
+	if !p.release(unsafe.SliceData(full)) && isPoisoned(full) {
+		panic("bytesExt: Put of a buffer that is not outstanding (double Put)")
+	}
+	if pooled {
+		poisonBytes(full)
+	}
+}
+
+func poisonBytes(b []byte) {
+	b = b[:cap(b)]
+	for i := range b {
+		b[i] = poison
+	}
+}
+
+func isPoisoned(b []byte) bool {
+	for _, c := range b {
+		if c != poison {
+			return false
+		}
+	}
+	return true
+}
+
+func callerStack() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var sb bytes.Buffer
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// Outstanding returns the number of buffers taken and not yet returned. It
+// is only tracked in debug mode and is 0 otherwise.
+func (p *BufferPool) Outstanding() int {
+	if !p.debug {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.out)
+}
+
+// Leaks returns the call stacks that took the outstanding buffers, for
+// reporting at the end of a test. It is only tracked in debug mode.
+func (p *BufferPool) Leaks() []string {
+	if !p.debug {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stacks := make([]string, 0, len(p.out))
+	for _, stack := range p.out {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	return stacks
+}
+
+// GetBuffer returns a bytes.Buffer from DefaultBufferPool.
+func GetBuffer(sizeHint int) *bytes.Buffer {
+	return DefaultBufferPool.GetBuffer(sizeHint)
+}
+
+// PutBuffer returns a bytes.Buffer to DefaultBufferPool.
+func PutBuffer(buf *bytes.Buffer) {
+	DefaultBufferPool.PutBuffer(buf)
+}
+
+// GetBytes returns a byte slice from DefaultBufferPool.
+func GetBytes(sizeHint int) []byte {
+	return DefaultBufferPool.Get(sizeHint)
+}
+
+// PutBytes returns a byte slice to DefaultBufferPool.
+func PutBytes(b []byte) {
+	DefaultBufferPool.Put(b)
+}
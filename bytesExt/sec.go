@@ -0,0 +1,29 @@
+package bytesExt
+
+import (
+	"crypto/subtle"
+	"runtime"
+)
+
+// EqualConstantTime reports whether a and b are equal, taking time that
+// depends only on their lengths, not their contents. Use it to compare
+// secrets such as tokens and MACs; the length itself is not hidden.
+func EqualConstantTime(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualConstantTimeString is EqualConstantTime for strings.
+func EqualConstantTimeString(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// Zero overwrites b with zeros, for wiping keys and passwords once they are
+// no longer needed. The write is kept from being optimised away as dead, but
+// this is best effort: copies the runtime or earlier code made, such as
+// from append growth or string conversions, are not reached.
+//
+//go:noinline
+func Zero(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
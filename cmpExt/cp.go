@@ -4,7 +4,6 @@ package cmpExt
 import (
 	"math"
 	"reflect"
-	"sort"
 )
 
 // Equal performs a deep equality check between two values.
@@ -13,70 +12,88 @@ func Equal(x, y interface{}) bool {
 }
 
 // FloatEqual compares two float64 values with a specified tolerance.
+//
+// Deprecated: use EqualWithOptions with FloatTolerance, which also applies
+// the tolerance to floats inside structs, slices and maps.
 func FloatEqual(a, b, tolerance float64) bool {
 	return math.Abs(a-b) <= tolerance
 }
 
 // IntSliceEqual checks if two int slices contain the same elements,
 // regardless of their order.
+//
+// Deprecated: use ElementsMatch, which also reports the mismatched elements.
 func IntSliceEqual(a, b []int) bool {
-	if len(a) != len(b) {
-		return false
-	}
-
-	// Create copies to avoid modifying the originals
-	aCopy := make([]int, len(a))
-	bCopy := make([]int, len(b))
-
-	copy(aCopy, a)
-	copy(bCopy, b)
-
-	// Sort both slices
-	sort.Ints(aCopy)
-	sort.Ints(bCopy)
-
-	// Compare sorted slices
-	for i := range aCopy {
-		if aCopy[i] != bCopy[i] {
-			return false
-		}
-	}
-	return true
+	return ElementsMatch(a, b).Match()
 }
 
 // StringSliceEqual checks if two string slices contain the same elements,
 // regardless of their order.
+//
+// Deprecated: use ElementsMatch, which also reports the mismatched elements.
 func StringSliceEqual(a, b []string) bool {
-	if len(a) != len(b) {
-		return false
-	}
+	return ElementsMatch(a, b).Match()
+}
 
-	// Create copies to avoid modifying the originals
-	aCopy := make([]string, len(a))
-	bCopy := make([]string, len(b))
+// MatchResult describes how two slices differ when compared as multisets.
+type MatchResult[T any] struct {
+	Missing []T // Elements of a not present (often enough) in b
+	Extra   []T // Elements of b not present (often enough) in a
+}
 
-	copy(aCopy, a)
-	copy(bCopy, b)
+// Match reports whether both slices contain the same elements.
+func (r MatchResult[T]) Match() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0
+}
 
-	// Sort both slices
-	sort.Strings(aCopy)
-	sort.Strings(bCopy)
+// ElementsMatch compares two slices as multisets, ignoring order but
+// respecting duplicates, and returns the elements missing from or extra in b.
+func ElementsMatch[T comparable](a, b []T) MatchResult[T] {
+	return ElementsMatchFunc(a, b, func(v T) T { return v })
+}
 
-	// Compare sorted slices
-	for i := range aCopy {
-		if aCopy[i] != bCopy[i] {
-			return false
+// ElementsMatchFunc is like ElementsMatch but identifies elements by the key
+// returned from keyFn, so it works for slices of non-comparable types.
+func ElementsMatchFunc[T any, K comparable](a, b []T, keyFn func(T) K) MatchResult[T] {
+	counts := make(map[K]int, len(a))
+	for _, v := range a {
+		counts[keyFn(v)]++
+	}
+
+	var result MatchResult[T]
+	for _, v := range b {
+		k := keyFn(v)
+		if counts[k] > 0 {
+			counts[k]--
+			continue
 		}
+		result.Extra = append(result.Extra, v)
 	}
-	return true
+
+	// Whatever is left in counts was not matched by b
+	for _, v := range a {
+		k := keyFn(v)
+		if counts[k] > 0 {
+			counts[k]--
+			result.Missing = append(result.Missing, v)
+		}
+	}
+
+	return result
 }
 
 // EqualWithComparator compares two values using a custom comparison function.
+//
+// Deprecated: use EqualWithOptions with a Comparator for the type, which is
+// also used for values of that type nested inside x and y.
 func EqualWithComparator(a, b interface{}, comparator func(a, b interface{}) bool) bool {
 	return comparator(a, b)
 }
 
 // StructFieldEqual compares two structs based on specific field names.
+//
+// Deprecated: use EqualWithOptions with IgnoreFields naming the fields to
+// leave out, which also reports a mismatch in fields added later.
 func StructFieldEqual(a, b interface{}, fieldNames ...string) bool {
 	aVal := reflect.ValueOf(a)
 	bVal := reflect.ValueOf(b)
@@ -114,6 +131,9 @@ func StructFieldEqual(a, b interface{}, fieldNames ...string) bool {
 }
 
 // MapEqual checks if two maps contain the same key-value pairs.
+//
+// Deprecated: use EqualWithOptions with EquateEmpty, which works for maps
+// of any type and, like MapEqual, treats a nil map as equal to an empty one.
 func MapEqual(a, b map[string]interface{}) bool {
 	if len(a) != len(b) {
 		return false
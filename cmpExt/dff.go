@@ -0,0 +1,325 @@
+package cmpExt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// Difference describes a single mismatch found by Diff.
+type Difference struct {
+	Path     string      // JSON-path-like location, e.g. "$.Users[2].Name"
+	Expected interface{} // Value from x
+	Actual   interface{} // Value from y
+	Message  string      // Short explanation, e.g. "values differ" or "missing map key"
+}
+
+// String formats the difference with its expected and actual values.
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s\n\texpected: %#v\n\tactual:   %#v", d.Path, d.Message, d.Expected, d.Actual)
+}
+
+// Differences is the list of mismatches returned by Diff.
+type Differences []Difference
+
+// Equal reports whether no differences were found.
+func (d Differences) Equal() bool {
+	return len(d) == 0
+}
+
+// String returns a multi-line report of all differences.
+func (d Differences) String() string {
+	if len(d) == 0 {
+		return "no differences"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d difference(s):\n", len(d))
+	for _, diff := range d {
+		b.WriteString(diff.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Diff compares x (expected) and y (actual) recursively through structs,
+// maps, slices, arrays, pointers and interfaces, and returns every difference found.
+func Diff(x, y interface{}) Differences {
+	return DiffWithOptions(x, y)
+}
+
+// comparer walks two values in parallel, recording differences.
+type comparer struct {
+	opts        options
+	diffs       []Difference
+	stopAtFirst bool
+	visited     map[visit]bool
+}
+
+// visit identifies a pair of references being compared, used to detect cycles.
+type visit struct {
+	x, y uintptr
+	typ  reflect.Type
+}
+
+// enter records that the references x and y are being compared and reports
+// whether they already were, in which case they are assumed equal.
+func (c *comparer) enter(x, y reflect.Value) bool {
+	if x.Pointer() == 0 || y.Pointer() == 0 {
+		return false
+	}
+	v := visit{x.Pointer(), y.Pointer(), x.Type()}
+	if c.visited[v] {
+		return true
+	}
+	c.visited[v] = true
+	return false
+}
+
+// done reports whether the walk can stop early.
+func (c *comparer) done() bool {
+	return c.stopAtFirst && len(c.diffs) > 0
+}
+
+func (c *comparer) report(path string, x, y reflect.Value, msg string) {
+	c.diffs = append(c.diffs, Difference{
+		Path:     path,
+		Expected: valueOf(x),
+		Actual:   valueOf(y),
+		Message:  msg,
+	})
+}
+
+func (c *comparer) compare(path string, x, y reflect.Value) {
+	if c.done() {
+		return
+	}
+
+	if !x.IsValid() || !y.IsValid() {
+		if x.IsValid() != y.IsValid() {
+			c.report(path, x, y, "one value is nil")
+		}
+		return
+	}
+
+	if x.Type() != y.Type() {
+		c.report(path, x, y, fmt.Sprintf("type mismatch: %s vs %s", x.Type(), y.Type()))
+		return
+	}
+
+	if eq, ok := c.opts.comparators[x.Type()]; ok && x.CanInterface() {
+		if !eq(x, y) {
+			c.report(path, x, y, "custom comparator reported a difference")
+		}
+		return
+	}
+
+	if x.Type() == timeType && x.CanInterface() {
+		d := x.Interface().(time.Time).Sub(y.Interface().(time.Time))
+		if d < -c.opts.timeTolerance || d > c.opts.timeTolerance {
+			c.report(path, x, y, "times differ")
+		}
+		return
+	}
+
+	switch x.Kind() {
+	case reflect.Ptr:
+		if x.IsNil() || y.IsNil() {
+			if x.IsNil() != y.IsNil() {
+				c.report(path, x, y, "one pointer is nil")
+			}
+			return
+		}
+		if x.Pointer() == y.Pointer() || c.enter(x, y) {
+			return
+		}
+		c.compare(path, x.Elem(), y.Elem())
+
+	case reflect.Interface:
+		if x.IsNil() || y.IsNil() {
+			if x.IsNil() != y.IsNil() {
+				c.report(path, x, y, "one interface is nil")
+			}
+			return
+		}
+		c.compare(path, x.Elem(), y.Elem())
+
+	case reflect.Struct:
+		c.compareStruct(path, x, y)
+
+	case reflect.Slice:
+		if x.IsNil() != y.IsNil() && !(c.opts.equateEmpty && x.Len() == 0 && y.Len() == 0) {
+			c.report(path, x, y, "nil slice vs empty slice")
+			return
+		}
+		if x.Pointer() == y.Pointer() && x.Len() == y.Len() {
+			return
+		}
+		if c.enter(x, y) {
+			return
+		}
+		c.compareSequence(path, x, y)
+
+	case reflect.Array:
+		c.compareSequence(path, x, y)
+
+	case reflect.Map:
+		if x.IsNil() != y.IsNil() && !(c.opts.equateEmpty && x.Len() == 0 && y.Len() == 0) {
+			c.report(path, x, y, "nil map vs empty map")
+			return
+		}
+		if c.enter(x, y) {
+			return
+		}
+		c.compareMap(path, x, y)
+
+	case reflect.Func:
+		if !x.IsNil() || !y.IsNil() {
+			c.report(path, x, y, "functions are only equal if both are nil")
+		}
+
+	case reflect.Chan, reflect.UnsafePointer:
+		if x.Pointer() != y.Pointer() {
+			c.report(path, x, y, "values differ")
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if x.Float() != y.Float() && !(math.Abs(x.Float()-y.Float()) <= c.opts.floatTolerance) {
+			c.report(path, x, y, "values differ")
+		}
+
+	default:
+		if !basicEqual(x, y) {
+			c.report(path, x, y, "values differ")
+		}
+	}
+}
+
+func (c *comparer) compareStruct(path string, x, y reflect.Value) {
+	t := x.Type()
+	if c.opts.unexported == UnexportedForceExport && !x.CanAddr() {
+		x, y = addressable(x), addressable(y)
+	}
+
+	for i := 0; i < x.NumField(); i++ {
+		if c.opts.ignoreField(t, i) {
+			continue
+		}
+
+		f := t.Field(i)
+		fieldPath := path + "." + f.Name
+		fx, fy := x.Field(i), y.Field(i)
+
+		if !f.IsExported() {
+			switch c.opts.unexported {
+			case UnexportedPanic:
+				panic(fmt.Sprintf("cmpExt: unexported field %s in %s at %s", f.Name, t, fieldPath))
+			case UnexportedForceExport:
+				fx, fy = forceExport(fx), forceExport(fy)
+			}
+		}
+
+		c.compare(fieldPath, fx, fy)
+	}
+}
+
+// addressable returns an addressable copy of v.
+func addressable(v reflect.Value) reflect.Value {
+	if !v.CanInterface() {
+		return v
+	}
+	a := reflect.New(v.Type()).Elem()
+	a.Set(v)
+	return a
+}
+
+// forceExport returns a view of an addressable unexported field that can be
+// used with Interface.
+func forceExport(v reflect.Value) reflect.Value {
+	if !v.CanAddr() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+func (c *comparer) compareSequence(path string, x, y reflect.Value) {
+	n := x.Len()
+	if y.Len() < n {
+		n = y.Len()
+	}
+
+	for i := 0; i < n; i++ {
+		c.compare(fmt.Sprintf("%s[%d]", path, i), x.Index(i), y.Index(i))
+	}
+	for i := n; i < x.Len(); i++ {
+		c.report(fmt.Sprintf("%s[%d]", path, i), x.Index(i), reflect.Value{}, "missing element")
+	}
+	for i := n; i < y.Len(); i++ {
+		c.report(fmt.Sprintf("%s[%d]", path, i), reflect.Value{}, y.Index(i), "extra element")
+	}
+}
+
+func (c *comparer) compareMap(path string, x, y reflect.Value) {
+	keys := x.MapKeys()
+	for _, k := range y.MapKeys() {
+		if !x.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+
+	// Sort keys so reports are deterministic
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(valueOf(keys[i])) < fmt.Sprint(valueOf(keys[j]))
+	})
+
+	for _, k := range keys {
+		keyPath := fmt.Sprintf("%s[%#v]", path, valueOf(k))
+		xv, yv := x.MapIndex(k), y.MapIndex(k)
+		switch {
+		case !yv.IsValid():
+			c.report(keyPath, xv, yv, "missing map key")
+		case !xv.IsValid():
+			c.report(keyPath, xv, yv, "extra map key")
+		default:
+			c.compare(keyPath, xv, yv)
+		}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// basicEqual compares scalar values without calling Interface, so it also
+// works on values read from unexported fields.
+func basicEqual(x, y reflect.Value) bool {
+	switch x.Kind() {
+	case reflect.Bool:
+		return x.Bool() == y.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() == y.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return x.Uint() == y.Uint()
+	case reflect.Float32, reflect.Float64:
+		return x.Float() == y.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return x.Complex() == y.Complex()
+	case reflect.String:
+		return x.String() == y.String()
+	}
+	return false
+}
+
+// valueOf returns v as an interface{}, falling back to its formatted form
+// for values that cannot be exposed (e.g. unexported fields).
+func valueOf(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,127 @@
+package cmpExt
+
+import (
+	"reflect"
+	"time"
+)
+
+// Option configures EqualWithOptions and DiffWithOptions.
+type Option func(*options)
+
+type options struct {
+	ignoreFields   map[string]bool
+	unexported     UnexportedPolicy
+	floatTolerance float64
+	timeTolerance  time.Duration
+	equateEmpty    bool
+	comparators    map[reflect.Type]func(x, y reflect.Value) bool
+}
+
+// IgnoreFields skips struct fields with the given names. A name may be a
+// bare field name ("UpdatedAt") or qualified by its struct type ("User.UpdatedAt").
+func IgnoreFields(names ...string) Option {
+	return func(o *options) {
+		if o.ignoreFields == nil {
+			o.ignoreFields = make(map[string]bool)
+		}
+		for _, name := range names {
+			o.ignoreFields[name] = true
+		}
+	}
+}
+
+// UnexportedPolicy controls how unexported struct fields are compared.
+type UnexportedPolicy int
+
+const (
+	// UnexportedCompare compares unexported fields structurally through reflection.
+	// Custom comparators and time tolerance do not apply to them.
+	UnexportedCompare UnexportedPolicy = iota
+	// UnexportedSkip ignores unexported fields entirely.
+	UnexportedSkip
+	// UnexportedPanic panics when an unexported field is encountered, for
+	// callers that want to be sure they only compare public API state.
+	UnexportedPanic
+	// UnexportedForceExport exposes unexported fields through unsafe so they
+	// are treated exactly like exported ones, including custom comparators.
+	UnexportedForceExport
+)
+
+// UnexportedFields sets the policy for unexported struct fields.
+func UnexportedFields(policy UnexportedPolicy) Option {
+	return func(o *options) {
+		o.unexported = policy
+	}
+}
+
+// IgnoreUnexported skips all unexported struct fields.
+func IgnoreUnexported() Option {
+	return UnexportedFields(UnexportedSkip)
+}
+
+// FloatTolerance treats floating-point values as equal when they differ by at most tolerance.
+func FloatTolerance(tolerance float64) Option {
+	return func(o *options) {
+		o.floatTolerance = tolerance
+	}
+}
+
+// TimeTolerance treats time.Time values as equal when they differ by at most tolerance.
+func TimeTolerance(tolerance time.Duration) Option {
+	return func(o *options) {
+		o.timeTolerance = tolerance
+	}
+}
+
+// EquateEmpty treats nil and empty slices, and nil and empty maps, as equal.
+func EquateEmpty() Option {
+	return func(o *options) {
+		o.equateEmpty = true
+	}
+}
+
+// Comparator registers a custom equality function used for every value of type T.
+func Comparator[T any](equal func(x, y T) bool) Option {
+	return func(o *options) {
+		if o.comparators == nil {
+			o.comparators = make(map[reflect.Type]func(x, y reflect.Value) bool)
+		}
+		o.comparators[reflect.TypeOf((*T)(nil)).Elem()] = func(x, y reflect.Value) bool {
+			return equal(x.Interface().(T), y.Interface().(T))
+		}
+	}
+}
+
+// EqualWithOptions performs a deep equality check between x and y,
+// customized by the given options. Cyclic data structures are handled:
+// a pair of pointers already under comparison is assumed equal.
+func EqualWithOptions(x, y interface{}, opts ...Option) bool {
+	c := newComparer(opts)
+	c.stopAtFirst = true
+	c.compare("$", reflect.ValueOf(x), reflect.ValueOf(y))
+	return len(c.diffs) == 0
+}
+
+// DiffWithOptions is like Diff but customized by the given options.
+func DiffWithOptions(x, y interface{}, opts ...Option) Differences {
+	c := newComparer(opts)
+	c.compare("$", reflect.ValueOf(x), reflect.ValueOf(y))
+	return c.diffs
+}
+
+func newComparer(opts []Option) *comparer {
+	c := &comparer{visited: make(map[visit]bool)}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	return c
+}
+
+// ignoreField reports whether field i of struct type t should be skipped.
+func (o *options) ignoreField(t reflect.Type, i int) bool {
+	f := t.Field(i)
+	if !f.IsExported() && o.unexported == UnexportedSkip {
+		return true
+	}
+	return o.ignoreFields[f.Name] || o.ignoreFields[t.Name()+"."+f.Name]
+}
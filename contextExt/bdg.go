@@ -0,0 +1,39 @@
+package cntExt
+
+import (
+	"context"
+	"time"
+)
+
+// WithFraction returns a child of parent whose deadline is the given fraction
+// of the time parent has left, so a caller making several calls in turn can
+// give each a share. Fractions outside (0, 1] are clamped. A parent without a
+// deadline gives a child without one.
+func WithFraction(parent context.Context, fraction float64) (context.Context, context.CancelFunc) {
+	remaining, err := RemainingTime(parent)
+	if err != nil {
+		return context.WithCancel(parent)
+	}
+	fraction = min(max(fraction, 0), 1)
+	return context.WithTimeout(parent, time.Duration(float64(remaining)*fraction))
+}
+
+// WithBudget returns a child of parent with a timeout of at most maxTimeout
+// that also ends reserve before parent's deadline, leaving the caller time to
+// clean up or report the failure. If parent has no deadline the child gets
+// maxTimeout, and no deadline if maxTimeout is not positive. If less than
+// reserve is left, the child is already expired.
+func WithBudget(parent context.Context, maxTimeout, reserve time.Duration) (context.Context, context.CancelFunc) {
+	remaining, err := RemainingTime(parent)
+	if err != nil {
+		if maxTimeout <= 0 {
+			return context.WithCancel(parent)
+		}
+		return context.WithTimeout(parent, maxTimeout)
+	}
+	budget := remaining - reserve
+	if maxTimeout > 0 {
+		budget = min(budget, maxTimeout)
+	}
+	return context.WithTimeout(parent, max(budget, 0))
+}
@@ -4,6 +4,7 @@ package cntExt
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -22,8 +23,42 @@ func WithTimeoutIfNone(parent context.Context, timeout time.Duration) (context.C
 	return context.WithTimeout(parent, timeout)
 }
 
+// ParentDoneError is the cause of a context returned by MergeContexts,
+// identifying the parent that ended it.
+type ParentDoneError struct {
+	Index int   // position of the parent in the MergeContexts arguments
+	Err   error // the parent's Err
+	Cause error // the parent's cause, from context.Cause
+}
+
+func (e *ParentDoneError) Error() string {
+	return fmt.Sprintf("parent context %d done: %v", e.Index, e.Cause)
+}
+
+func (e *ParentDoneError) Unwrap() error {
+	return e.Cause
+}
+
+// mergedContext reports the triggering parent's error from Err, so a parent
+// deadline still reads as context.DeadlineExceeded.
+type mergedContext struct {
+	context.Context
+}
+
+func (c mergedContext) Err() error {
+	err := c.Context.Err()
+	var pe *ParentDoneError
+	if err != nil && errors.As(context.Cause(c.Context), &pe) {
+		return pe.Err
+	}
+	return err
+}
+
 // MergeContexts creates a new context that inherits cancellation from multiple contexts
-// The cancellation of any parent context will cancel the resulting context
+// The cancellation of any parent context will cancel the resulting context, and
+// context.Cause reports which one as a *ParentDoneError. The earliest parent deadline
+// becomes the merged deadline, and values come from the first parent.
+// Nothing waits on the parents once the merged context is done.
 func MergeContexts(parents ...context.Context) (context.Context, context.CancelFunc) {
 	if len(parents) == 0 {
 		return context.Background(), func() {}
@@ -33,21 +68,47 @@ func MergeContexts(parents ...context.Context) (context.Context, context.CancelF
 		return parents[0], func() {}
 	}
 
-	// Use the first context as the base
-	ctx, cancel := context.WithCancel(parents[0])
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(parents[0]))
+	stopDeadline := context.CancelFunc(func() {})
+	if deadline, index, ok := earliestDeadline(parents); ok {
+		ctx, stopDeadline = context.WithDeadlineCause(ctx, deadline, &ParentDoneError{
+			Index: index,
+			Err:   context.DeadlineExceeded,
+			Cause: context.DeadlineExceeded,
+		})
+	}
 
-	// Monitor cancellations of all parent contexts
-	for _, parent := range parents {
-		go func(p context.Context) {
-			<-p.Done()
-			cancel()
-		}(parent)
+	stops := make([]func() bool, len(parents))
+	for i, parent := range parents {
+		stops[i] = context.AfterFunc(parent, func() {
+			cancel(&ParentDoneError{Index: i, Err: parent.Err(), Cause: context.Cause(parent)})
+		})
 	}
+	context.AfterFunc(ctx, func() {
+		for _, stop := range stops {
+			stop()
+		}
+		stopDeadline()
+	})
+
+	return mergedContext{ctx}, func() { cancel(context.Canceled) }
+}
 
-	return ctx, cancel
+// earliestDeadline returns the earliest deadline among ctxs and its index.
+func earliestDeadline(ctxs []context.Context) (time.Time, int, bool) {
+	var earliest time.Time
+	index, found := -1, false
+	for i, ctx := range ctxs {
+		if d, ok := ctx.Deadline(); ok && (!found || d.Before(earliest)) {
+			earliest, index, found = d, i, true
+		}
+	}
+	return earliest, index, found
 }
 
 // GetStringValue retrieves a string value from the context or returns an error if not found
+//
+// Deprecated: use a Key[string] with Value, which checks the type at compile time.
 func GetStringValue(ctx context.Context, key interface{}) (string, error) {
 	value := ctx.Value(key)
 	if value == nil {
@@ -63,6 +124,8 @@ func GetStringValue(ctx context.Context, key interface{}) (string, error) {
 }
 
 // GetStringValueWithDefault retrieves a string value from the context or returns the default value
+//
+// Deprecated: use a Key[string] with ValueOr.
 func GetStringValueWithDefault(ctx context.Context, key interface{}, defaultValue string) string {
 	value, err := GetStringValue(ctx, key)
 	if err != nil {
@@ -118,3 +181,16 @@ func WithCancel(parent context.Context, onCancel func()) (context.Context, conte
 
 	return ctx, cancelFunc
 }
+
+// Detach returns a context that keeps the values of parent, such as a request
+// ID or logger, but is never canceled and has no deadline. Use it for work that
+// must outlive the request that started it.
+func Detach(parent context.Context) context.Context {
+	return context.WithoutCancel(parent)
+}
+
+// DetachWithTimeout returns a detached context, as from Detach, that is
+// canceled after timeout, so background work keeps a bound of its own.
+func DetachWithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(Detach(parent), timeout)
+}
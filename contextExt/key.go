@@ -0,0 +1,51 @@
+package cntExt
+
+import "context"
+
+// Key is a typed context key. Each key made by NewKey is distinct, so
+// packages cannot collide even if they use the same name, and values read
+// back through it have type T without an assertion at the call site.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new key for values of type T. The name is only used to
+// describe the key.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the key's name.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// WithValue returns a copy of ctx in which key is associated with v.
+func WithValue[T any](ctx context.Context, key *Key[T], v T) context.Context {
+	return context.WithValue(ctx, key, v)
+}
+
+// Value returns the value associated with key in ctx and whether there is
+// one. A nil value stored for an interface type reads back as absent.
+func Value[T any](ctx context.Context, key *Key[T]) (T, bool) {
+	v, ok := ctx.Value(key).(T)
+	return v, ok
+}
+
+// ValueOr returns the value associated with key in ctx, or def if there is none.
+func ValueOr[T any](ctx context.Context, key *Key[T], def T) T {
+	if v, ok := Value(ctx, key); ok {
+		return v
+	}
+	return def
+}
+
+// MustValue returns the value associated with key in ctx and panics if there
+// is none.
+func MustValue[T any](ctx context.Context, key *Key[T]) T {
+	v, ok := Value(ctx, key)
+	if !ok {
+		panic("cntExt: " + ErrValueNotFound.Error() + ": " + key.name)
+	}
+	return v
+}
@@ -0,0 +1,59 @@
+package cntExt
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/C0d3-5t3w/myT00L5/syscallExt"
+)
+
+// WithSignals returns a copy of parent that is canceled when one of sigs
+// arrives, SIGINT and SIGTERM if none are given. SignalFrom reports which
+// signal it was. A second signal before stop is called exits the process
+// immediately with status 1, so a stuck cleanup can still be interrupted.
+// Calling stop cancels the context if it is still running and stops
+// listening, restoring the default signal behavior.
+//
+//	ctx, stop := cntExt.WithSignals(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer stop()
+func WithSignals(parent context.Context, sigs ...os.Signal) (ctx context.Context, stop context.CancelFunc) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	s := syscallExt.NewSignals(parent)
+	s.ShutdownOn(sigs...)
+	s.ShutdownTimeout(time.Duration(math.MaxInt64))
+
+	// Hold shutdown open until stop so the manager keeps listening for the
+	// second signal while the caller cleans up.
+	released := make(chan struct{})
+	var once sync.Once
+	s.OnShutdown("WithSignals", func(context.Context) error {
+		<-released
+		return nil
+	})
+	s.Start()
+
+	return s.Context(), func() {
+		once.Do(func() {
+			close(released)
+			s.Shutdown()
+		})
+	}
+}
+
+// SignalFrom returns the signal that canceled a context from WithSignals, or
+// a context derived from it, and false if it was not canceled by a signal.
+func SignalFrom(ctx context.Context) (os.Signal, bool) {
+	var se *syscallExt.SignalError
+	if errors.As(context.Cause(ctx), &se) {
+		return se.Signal, true
+	}
+	return nil, false
+}
@@ -118,6 +118,10 @@ func GenerateRandomBytes(size int) ([]byte, error) {
 
 // GenerateRandomString returns a URL-safe, base64 encoded
 // securely generated random string of the specified length
+//
+// Deprecated: the result holds only 6 bits of entropy per character, not the
+// 8 per byte the length suggests. Use GenerateToken, which takes the number
+// of random bytes.
 func GenerateRandomString(length int) (string, error) {
 	bytes, err := GenerateRandomBytes(length)
 	if err != nil {
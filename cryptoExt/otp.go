@@ -0,0 +1,323 @@
+package cryptoExt
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"image"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/C0d3-5t3w/myT00L5/imageExt"
+)
+
+// OTPAlgorithm is the HMAC hash behind one-time passwords. Most
+// authenticator apps only support SHA1.
+type OTPAlgorithm int
+
+const (
+	OTPSHA1 OTPAlgorithm = iota
+	OTPSHA256
+	OTPSHA512
+)
+
+func (a OTPAlgorithm) String() string {
+	switch a {
+	case OTPSHA256:
+		return "SHA256"
+	case OTPSHA512:
+		return "SHA512"
+	}
+	return "SHA1"
+}
+
+func (a OTPAlgorithm) hash() func() hash.Hash {
+	switch a {
+	case OTPSHA256:
+		return sha256.New
+	case OTPSHA512:
+		return sha512.New
+	}
+	return sha1.New
+}
+
+// Defaults for one-time passwords, as most authenticator apps expect.
+const (
+	DefaultOTPDigits = 6
+	DefaultOTPPeriod = 30 * time.Second
+)
+
+var otpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateOTPSecret returns a random 160-bit secret in unpadded base32, the
+// form authenticator apps accept.
+func GenerateOTPSecret() (string, error) {
+	secret, err := GenerateRandomBytes(20)
+	if err != nil {
+		return "", err
+	}
+	return otpEncoding.EncodeToString(secret), nil
+}
+
+// decodeOTPSecret decodes a base32 secret, ignoring case, spaces and
+// padding.
+func decodeOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := otpEncoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode OTP secret: %v", err)
+	}
+	return key, nil
+}
+
+// HOTPCode computes the RFC 4226 one-time password for counter.
+func HOTPCode(secret string, counter uint64, digits int, alg OTPAlgorithm) (string, error) {
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	if err := checkOTPDigits(digits); err != nil {
+		return "", err
+	}
+	return hotp(key, counter, digits, alg), nil
+}
+
+// checkOTPDigits rejects code lengths hotp cannot produce.
+func checkOTPDigits(digits int) error {
+	if digits < 1 || digits > 10 {
+		return errors.New("OTP digits must be between 1 and 10")
+	}
+	return nil
+}
+
+func hotp(key []byte, counter uint64, digits int, alg OTPAlgorithm) string {
+	mac := hmac.New(alg.hash(), key)
+	binary.Write(mac, binary.BigEndian, counter)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation.
+	offset := sum[len(sum)-1] & 0xf
+	code := uint64(binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff)
+	mod := uint64(1)
+	for range digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
+
+// TOTP is an RFC 6238 time-based one-time password generator and validator.
+// Zero fields take the defaults.
+type TOTP struct {
+	Secret    string // base32
+	Issuer    string // shown by authenticator apps, such as the app's name
+	Account   string // shown by authenticator apps, such as the user's email
+	Algorithm OTPAlgorithm
+	Digits    int           // 1 to 10
+	Period    time.Duration // whole seconds
+	// Skew is how many periods before and after the current one are also
+	// accepted, to allow for clock drift. Negative accepts only the
+	// current period.
+	Skew int
+}
+
+// NewTOTP returns a TOTP with a new secret and default settings, accepting
+// codes one period either side of the current one.
+func NewTOTP(issuer, account string) (*TOTP, error) {
+	secret, err := GenerateOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	return &TOTP{Secret: secret, Issuer: issuer, Account: account, Skew: 1}, nil
+}
+
+func (t *TOTP) digits() int {
+	if t.Digits == 0 {
+		return DefaultOTPDigits
+	}
+	return t.Digits
+}
+
+func (t *TOTP) period() time.Duration {
+	if t.Period == 0 {
+		return DefaultOTPPeriod
+	}
+	return t.Period
+}
+
+// check rejects settings that cannot make codes.
+func (t *TOTP) check() error {
+	if p := t.period(); p < time.Second || p%time.Second != 0 {
+		return errors.New("TOTP period must be a whole number of seconds")
+	}
+	return checkOTPDigits(t.digits())
+}
+
+// Step returns the time step at, the counter the code for at is made from.
+func (t *TOTP) Step(at time.Time) uint64 {
+	// Guarded so an invalid period, which Code and Validate reject, cannot
+	// divide by zero.
+	return uint64(at.Unix()) / max(uint64(t.period()/time.Second), 1)
+}
+
+// Code returns the code for the time at.
+func (t *TOTP) Code(at time.Time) (string, error) {
+	if err := t.check(); err != nil {
+		return "", err
+	}
+	return HOTPCode(t.Secret, t.Step(at), t.digits(), t.Algorithm)
+}
+
+// Validate reports whether code is valid at the time at.
+func (t *TOTP) Validate(code string, at time.Time) bool {
+	_, ok := t.ValidateStep(code, at)
+	return ok
+}
+
+// ValidateStep is like Validate but also returns the step code matched.
+// Storing it and rejecting codes for steps already used stops a code from
+// being replayed while it is still valid.
+func (t *TOTP) ValidateStep(code string, at time.Time) (uint64, bool) {
+	if t.check() != nil {
+		return 0, false
+	}
+	key, err := decodeOTPSecret(t.Secret)
+	if err != nil || len(code) != t.digits() {
+		return 0, false
+	}
+	current := t.Step(at)
+	skew := uint64(max(t.Skew, 0))
+	for step := current - min(skew, current); step <= current+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step, t.digits(), t.Algorithm)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// URI returns the otpauth:// URI that authenticator apps import, usually
+// through a QR code.
+func (t *TOTP) URI() string {
+	params := otpParams(t.Secret, t.Issuer, t.Algorithm, t.digits())
+	params.Set("period", strconv.Itoa(int(t.period()/time.Second)))
+	return otpURI("totp", t.Issuer, t.Account, params)
+}
+
+// QRCode returns the URI as a QR code image with modules scale pixels wide,
+// for the user to scan.
+func (t *TOTP) QRCode(scale int) (image.Image, error) {
+	return otpQRCode(t.URI(), scale)
+}
+
+// HOTP is an RFC 4226 counter-based one-time password generator and
+// validator. Zero fields take the defaults.
+type HOTP struct {
+	Secret    string // base32
+	Issuer    string
+	Account   string
+	Algorithm OTPAlgorithm
+	Digits    int // 1 to 10
+	// Counter is the next counter expected. Validate advances it, and it
+	// must be stored after each successful validation.
+	Counter uint64
+	// LookAhead is how many counters past Counter are also accepted, for
+	// codes generated but never used.
+	LookAhead int
+}
+
+// NewHOTP returns an HOTP with a new secret and default settings, looking up
+// to ten counters ahead.
+func NewHOTP(issuer, account string) (*HOTP, error) {
+	secret, err := GenerateOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	return &HOTP{Secret: secret, Issuer: issuer, Account: account, LookAhead: 10}, nil
+}
+
+func (h *HOTP) digits() int {
+	if h.Digits == 0 {
+		return DefaultOTPDigits
+	}
+	return h.Digits
+}
+
+// Code returns the code for counter.
+func (h *HOTP) Code(counter uint64) (string, error) {
+	return HOTPCode(h.Secret, counter, h.digits(), h.Algorithm)
+}
+
+// Validate reports whether code matches Counter or a counter within
+// LookAhead of it, and if so moves Counter past the match.
+func (h *HOTP) Validate(code string) bool {
+	if checkOTPDigits(h.digits()) != nil {
+		return false
+	}
+	key, err := decodeOTPSecret(h.Secret)
+	if err != nil || len(code) != h.digits() {
+		return false
+	}
+	for i := 0; i <= max(h.LookAhead, 0); i++ {
+		counter := h.Counter + uint64(i)
+		if subtle.ConstantTimeCompare([]byte(hotp(key, counter, h.digits(), h.Algorithm)), []byte(code)) == 1 {
+			h.Counter = counter + 1
+			return true
+		}
+	}
+	return false
+}
+
+// URI returns the otpauth:// URI that authenticator apps import.
+func (h *HOTP) URI() string {
+	params := otpParams(h.Secret, h.Issuer, h.Algorithm, h.digits())
+	params.Set("counter", strconv.FormatUint(h.Counter, 10))
+	return otpURI("hotp", h.Issuer, h.Account, params)
+}
+
+// QRCode returns the URI as a QR code image with modules scale pixels wide.
+func (h *HOTP) QRCode(scale int) (image.Image, error) {
+	return otpQRCode(h.URI(), scale)
+}
+
+func otpParams(secret, issuer string, alg OTPAlgorithm, digits int) url.Values {
+	params := url.Values{}
+	params.Set("secret", strings.TrimRight(strings.ToUpper(secret), "="))
+	if issuer != "" {
+		params.Set("issuer", issuer)
+	}
+	params.Set("algorithm", alg.String())
+	params.Set("digits", strconv.Itoa(digits))
+	return params
+}
+
+// otpURI builds an otpauth URI, labelled "issuer:account" as the Key URI
+// Format describes.
+func otpURI(kind, issuer, account string, params url.Values) string {
+	label := account
+	if issuer != "" {
+		label = issuer + ":" + account
+	}
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     kind,
+		Path:     "/" + label,
+		RawQuery: strings.ReplaceAll(params.Encode(), "+", "%20"),
+	}
+	return u.String()
+}
+
+func otpQRCode(uri string, scale int) (image.Image, error) {
+	q, err := imageExt.EncodeQR(uri, imageExt.QRLevelM)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode OTP QR code: %v", err)
+	}
+	return q.Image(scale), nil
+}
@@ -0,0 +1,495 @@
+package cryptoExt
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Strength is an estimate of how hard a password is to guess, in the style
+// of zxcvbn: the password is split into the cheapest mix of known patterns
+// (common passwords, words, sequences, repeats, keyboard runs, years) and
+// brute-forced characters, and the guesses needed for each part multiplied.
+type Strength struct {
+	// Score rates the password from 0 (guessed in moments) to 4 (very
+	// unlikely to be guessed).
+	Score int
+	// Guesses is the estimated number of guesses needed, as log10.
+	Guesses float64
+	// Warning explains the weakest part of the password, if any.
+	Warning string
+	// Suggestions are ways to make the password stronger.
+	Suggestions []string
+}
+
+// bruteforceGuesses is the guesses per character not matching any pattern.
+const bruteforceGuesses = 10
+
+// commonPasswords are frequently used passwords, most common first.
+var commonPasswords = strings.Fields(`
+	123456 password 12345678 qwerty 123456789 12345 1234 111111 1234567 dragon
+	123123 baseball abc123 football monkey letmein 696969 shadow master 666666
+	qwertyuiop 123321 mustang 1234567890 michael 654321 superman 1qaz2wsx 7777777
+	121212 000000 qazwsx 123qwe killer trustno1 jordan jennifer zxcvbnm asdfgh
+	hunter buster soccer harley batman andrew tigger sunshine iloveyou 2000
+	charlie robert thomas hockey ranger daniel starwars klaster 112233 george
+	computer michelle jessica pepper 1111 zxcvbn 555555 11111111 131313 freedom
+	777777 pass maggie 159753 aaaaaa ginger princess joshua cheese amanda summer
+	love ashley 6969 nicole chelsea biteme matthew access yankees 987654321
+	dallas austin thunder taylor matrix welcome admin login passw0rd secret
+	hello whatever qwerty123 password1 changeme default guest root test
+`)
+
+// commonWords are frequent English words that show up in passwords.
+var commonWords = strings.Fields(`
+	the and you that was for are with his they this have from one had word but
+	not what all were when your can said there use each which she how their
+	will other about out many then them these some her would make like him into
+	time has look two more write see number way could people than first water
+	been call who oil its now find long down day did get come made may part
+	love baby angel money happy house family friend summer winter spring autumn
+	blue red green black white orange purple yellow pink silver gold star sun
+	moon sky fire dog cat bird fish horse tiger lion bear wolf eagle dragon
+	king queen prince princess power magic music game player super hero
+	secret letmein welcome hello world computer internet monkey cookie
+	coffee pizza chocolate flower heart soccer football baseball hockey
+	company office school city country apple banana cherry
+`)
+
+var dictionaryRanks = func() map[string]int {
+	ranks := make(map[string]int)
+	for i, w := range commonPasswords {
+		ranks[w] = i + 1
+	}
+	for i, w := range commonWords {
+		if _, ok := ranks[w]; !ok {
+			ranks[w] = len(commonPasswords) + (i+1)*10
+		}
+	}
+	return ranks
+}()
+
+// maxWordLen is the length in runes of the longest dictionary word.
+var maxWordLen = func() int {
+	longest := 0
+	for w := range dictionaryRanks {
+		longest = max(longest, len([]rune(w)))
+	}
+	return longest
+}()
+
+// maxStrengthRunes is how much of a password EstimateStrength looks at, as
+// in zxcvbn. Matching is quadratic in the length, and anything longer is
+// strong enough already.
+const maxStrengthRunes = 100
+
+// leet maps common character substitutions back to letters.
+var leet = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i',
+	'!': 'i', '|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't',
+	'2': 'z',
+}
+
+// keyboardRows are runs of adjacent keys on a QWERTY keyboard.
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"1qaz", "2wsx", "3edc", "4rfv", "5tgb", "6yhn", "7ujm", "8ik,", "9ol.", "0p;/",
+}
+
+// pwMatch is a part of a password, [i, j], matching a pattern.
+type pwMatch struct {
+	i, j    int
+	guesses float64 // log10
+	pattern string
+}
+
+// EstimateStrength estimates how hard password is to guess. Only the first
+// 100 characters are considered.
+func EstimateStrength(password string) Strength {
+	runes := []rune(password)
+	if len(runes) > maxStrengthRunes {
+		runes = runes[:maxStrengthRunes]
+	}
+	n := len(runes)
+	if n == 0 {
+		return Strength{Warning: "Password is empty.", Suggestions: []string{"Use a few words, avoid common phrases."}}
+	}
+	matches := passwordMatches(runes)
+
+	// best[k] is the fewest guesses, as log10, for the first k runes, and
+	// last[k] the match ending there, or nil for a brute-forced rune.
+	best := make([]float64, n+1)
+	last := make([]*pwMatch, n+1)
+	for k := 1; k <= n; k++ {
+		best[k] = best[k-1] + math.Log10(bruteforceGuesses)
+		for idx := range matches {
+			m := &matches[idx]
+			if m.j != k-1 {
+				continue
+			}
+			if g := best[m.i] + m.guesses; g < best[k] {
+				best[k], last[k] = g, m
+			}
+		}
+	}
+
+	s := Strength{Guesses: best[n]}
+	switch {
+	case s.Guesses < 3:
+		s.Score = 0
+	case s.Guesses < 6:
+		s.Score = 1
+	case s.Guesses < 8:
+		s.Score = 2
+	case s.Guesses < 10:
+		s.Score = 3
+	default:
+		s.Score = 4
+	}
+	if s.Score >= 3 {
+		return s
+	}
+
+	// Explain the longest pattern used.
+	var worst *pwMatch
+	for k := n; k > 0; {
+		m := last[k]
+		if m == nil {
+			k--
+			continue
+		}
+		if worst == nil || m.j-m.i > worst.j-worst.i {
+			worst = m
+		}
+		k = m.i
+	}
+	s.Suggestions = []string{"Add another word or two. Uncommon words are better."}
+	if worst == nil {
+		s.Warning = "Password is too short."
+		return s
+	}
+	switch worst.pattern {
+	case "common":
+		s.Warning = "This is a very common password."
+	case "dictionary":
+		s.Warning = "A word by itself is easy to guess."
+	case "reversed":
+		s.Warning = "Reversed words aren't much harder to guess."
+		s.Suggestions = append(s.Suggestions, "Avoid reversed spellings of common words.")
+	case "leet":
+		s.Warning = "Predictable substitutions like '@' instead of 'a' don't help much."
+	case "repeat":
+		s.Warning = `Repeats like "aaa" are easy to guess.`
+		s.Suggestions = append(s.Suggestions, "Avoid repeated words and characters.")
+	case "sequence":
+		s.Warning = `Sequences like "abc" or "6543" are easy to guess.`
+		s.Suggestions = append(s.Suggestions, "Avoid sequences.")
+	case "keyboard":
+		s.Warning = "Straight rows of keys are easy to guess."
+		s.Suggestions = append(s.Suggestions, "Use a longer keyboard pattern with more turns.")
+	case "year":
+		s.Warning = "Recent years are easy to guess."
+		s.Suggestions = append(s.Suggestions, "Avoid years that are associated with you.")
+	}
+	return s
+}
+
+// passwordMatches finds every pattern in a password.
+func passwordMatches(runes []rune) []pwMatch {
+	n := len(runes)
+	lower := make([]rune, n)
+	unleet := make([]rune, n)
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+		unleet[i] = lower[i]
+		if l, ok := leet[lower[i]]; ok {
+			unleet[i] = l
+		}
+	}
+
+	var matches []pwMatch
+	add := func(i, j int, guesses float64, pattern string) {
+		matches = append(matches, pwMatch{i, j, math.Log10(max(guesses, 1)), pattern})
+	}
+
+	// Dictionary words, plain, reversed and with substitutions.
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < min(n, i+maxWordLen); j++ {
+			word := string(lower[i : j+1])
+			caseGuesses := upperCaseVariations(runes[i : j+1])
+			if rank, ok := dictionaryRanks[word]; ok {
+				pattern := "dictionary"
+				if rank <= len(commonPasswords) {
+					pattern = "common"
+				}
+				add(i, j, float64(rank)*caseGuesses, pattern)
+			}
+			if rank, ok := dictionaryRanks[reverseString(word)]; ok {
+				add(i, j, float64(rank)*caseGuesses*2, "reversed")
+			}
+			if sub := string(unleet[i : j+1]); sub != word {
+				if rank, ok := dictionaryRanks[sub]; ok {
+					add(i, j, float64(rank)*caseGuesses*leetVariations(lower[i:j+1]), "leet")
+				}
+			}
+		}
+	}
+
+	// Repeated characters.
+	for i := 0; i < n; {
+		j := i
+		for j+1 < n && runes[j+1] == runes[i] {
+			j++
+		}
+		if j-i >= 2 {
+			add(i, j, charsetSize(runes[i])*float64(j-i+1), "repeat")
+		}
+		i = j + 1
+	}
+
+	// Sequences such as abc, 9876 or acegi, with a constant small step.
+	for i := 0; i+2 < n; {
+		delta := lower[i+1] - lower[i]
+		j := i + 1
+		if delta != 0 && delta >= -5 && delta <= 5 {
+			for j+1 < n && lower[j+1]-lower[j] == delta {
+				j++
+			}
+		}
+		if j-i >= 2 {
+			start := 26.0
+			switch {
+			case strings.ContainsRune("aAzZ019", runes[i]):
+				start = 4
+			case unicode.IsDigit(runes[i]):
+				start = 10
+			}
+			if delta < 0 {
+				start *= 2
+			}
+			add(i, j, start*float64(j-i+1), "sequence")
+			i = j
+			continue
+		}
+		i++
+	}
+
+	// Straight runs along a keyboard row or column, either way.
+	for _, row := range keyboardRows {
+		for _, keys := range []string{row, reverseString(row)} {
+			for i := 0; i+3 < n; i++ {
+				pos := strings.IndexRune(keys, lower[i])
+				if pos < 0 {
+					continue
+				}
+				j := i
+				for j+1 < n && pos+j+1-i < len(keys) && rune(keys[pos+j+1-i]) == lower[j+1] {
+					j++
+				}
+				if j-i >= 3 {
+					add(i, j, 47*2*float64(j-i+1)*upperCaseVariations(runes[i:j+1]), "keyboard")
+				}
+			}
+		}
+	}
+
+	// Years from 1900 to 2099, counting years further from now as less
+	// likely.
+	now := time.Now().Year()
+	for i := 0; i+3 < n; i++ {
+		if lower[i] != '1' && lower[i] != '2' {
+			continue
+		}
+		year := 0
+		for _, r := range lower[i : i+4] {
+			if r < '0' || r > '9' {
+				year = -1
+				break
+			}
+			year = year*10 + int(r-'0')
+		}
+		if year >= 1900 && year <= 2099 {
+			add(i, i+3, math.Max(math.Abs(float64(year-now)), 20), "year")
+		}
+	}
+	return matches
+}
+
+// upperCaseVariations is the guesses added by the capitalization of a word:
+// none for all lower case, few for a capitalized or all upper case word.
+func upperCaseVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0, upper == 1 && unicode.IsUpper(word[0]):
+		return 2
+	}
+	// Any choice of up to upper of the letters being capitals.
+	total, c := 0.0, 1.0
+	for k := 1; k <= min(upper, lower); k++ {
+		c = c * float64(upper+lower-k+1) / float64(k)
+		total += c
+	}
+	return max(total, 2)
+}
+
+// leetVariations is the guesses added by substituted characters.
+func leetVariations(word []rune) float64 {
+	subs := 0
+	for _, r := range word {
+		if _, ok := leet[r]; ok {
+			subs++
+		}
+	}
+	return math.Pow(2, float64(subs))
+}
+
+// charsetSize is the size of the character class r belongs to.
+func charsetSize(r rune) float64 {
+	switch {
+	case unicode.IsDigit(r):
+		return 10
+	case unicode.IsLower(r), unicode.IsUpper(r):
+		return 26
+	case r < unicode.MaxASCII:
+		return 33
+	}
+	return 100
+}
+
+func reverseString(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// BreachChecker looks up how often a password appears in known data
+// breaches. netExt.PwnedPasswords implements it with the Have I Been Pwned
+// range API, which never sees the password or its full hash.
+type BreachChecker interface {
+	BreachCount(ctx context.Context, password string) (int, error)
+}
+
+// PasswordPolicy is a set of rules passwords must follow. Zero fields are
+// not checked.
+type PasswordPolicy struct {
+	MinLength int
+	// MaxLength bounds the length in bytes. HashPassword only uses the
+	// first 72 bytes.
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MinClasses is how many of upper case, lower case, digits and symbols
+	// must appear.
+	MinClasses int
+	// MinScore is the lowest EstimateStrength score accepted.
+	MinScore int
+	// Breaches, if set, rejects passwords seen in data breaches.
+	Breaches BreachChecker
+}
+
+// DefaultPasswordPolicy follows current guidance: a reasonable length and
+// strength, no composition rules.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength: 8,
+	MaxLength: 72,
+	MinScore:  2,
+}
+
+// PasswordPolicyError lists the rules a password broke.
+type PasswordPolicyError struct {
+	Problems []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password rejected: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks password against the policy, returning a
+// *PasswordPolicyError listing every rule it broke, or an error if the
+// breach check fails.
+func (p PasswordPolicy) Validate(ctx context.Context, password string) error {
+	var problems []string
+	if len([]rune(password)) < p.MinLength {
+		problems = append(problems, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	tooLong := p.MaxLength > 0 && len(password) > p.MaxLength
+	if tooLong {
+		problems = append(problems, fmt.Sprintf("must be at most %d bytes", p.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		problems = append(problems, "must contain an upper case letter")
+	}
+	if p.RequireLower && !lower {
+		problems = append(problems, "must contain a lower case letter")
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, "must contain a symbol")
+	}
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < p.MinClasses {
+		problems = append(problems, fmt.Sprintf("must mix at least %d of upper case, lower case, digits and symbols", p.MinClasses))
+	}
+	if p.MinScore > 0 && !tooLong {
+		if s := EstimateStrength(password); s.Score < p.MinScore {
+			problem := "is too easy to guess"
+			if s.Warning != "" {
+				problem += ": " + strings.TrimSuffix(s.Warning, ".")
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	if p.Breaches != nil && password != "" {
+		count, err := p.Breaches.BreachCount(ctx, password)
+		if err != nil {
+			return fmt.Errorf("could not check password breaches: %w", err)
+		}
+		if count > 0 {
+			problems = append(problems, fmt.Sprintf("has appeared in %d data breaches", count))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &PasswordPolicyError{Problems: problems}
+	}
+	return nil
+}
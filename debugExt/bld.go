@@ -0,0 +1,103 @@
+// Package debugExt reports how the running binary was built, on top of
+// runtime/debug. It has no dependencies outside the standard library, so
+// CLIs can print their version without pulling in anything else.
+package debugExt
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// VersionInfo describes how the running binary was built.
+type VersionInfo struct {
+	Path      string    `json:"path"`       // Main package path
+	Module    string    `json:"module"`     // Main module path
+	Version   string    `json:"version"`    // Main module version, "(devel)" for local builds
+	Revision  string    `json:"revision"`   // VCS revision
+	Time      time.Time `json:"time"`       // VCS commit time
+	Modified  bool      `json:"modified"`   // Working tree had uncommitted changes
+	GoVersion string    `json:"go_version"` // Toolchain used to build the binary
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+}
+
+// BuildInfo returns version and VCS information embedded in the binary.
+// Fields that are not available (e.g. binaries built without VCS stamping)
+// are left empty; GoVersion, GOOS and GOARCH are always set.
+func BuildInfo() *VersionInfo {
+	info := &VersionInfo{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.Path = bi.Path
+	info.Module = bi.Main.Path
+	info.Version = bi.Main.Version
+	if bi.GoVersion != "" {
+		info.GoVersion = bi.GoVersion
+	}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.Time, _ = time.Parse(time.RFC3339, s.Value)
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		case "GOOS":
+			info.GOOS = s.Value
+		case "GOARCH":
+			info.GOARCH = s.Value
+		}
+	}
+
+	return info
+}
+
+// String returns a one-line description suitable for --version output, e.g.
+// "v1.2.3 (rev 1a2b3c4d5e6f, 2025-01-02T15:04:05Z, dirty) go1.24.1 linux/amd64".
+func (v *VersionInfo) String() string {
+	var b strings.Builder
+
+	version := v.Version
+	if version == "" {
+		version = "(devel)"
+	}
+	b.WriteString(version)
+
+	var details []string
+	if v.Revision != "" {
+		rev := v.Revision
+		if len(rev) > 12 {
+			rev = rev[:12]
+		}
+		details = append(details, "rev "+rev)
+	}
+	if !v.Time.IsZero() {
+		details = append(details, v.Time.UTC().Format(time.RFC3339))
+	}
+	if v.Modified {
+		details = append(details, "dirty")
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(details, ", "))
+	}
+
+	fmt.Fprintf(&b, " %s %s/%s", v.GoVersion, v.GOOS, v.GOARCH)
+	return b.String()
+}
+
+// VersionString returns the standard version line for the named program.
+func VersionString(program string) string {
+	return program + " " + BuildInfo().String()
+}
@@ -0,0 +1,160 @@
+package encodingExt
+
+import (
+	"encoding/ascii85"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Base58BitcoinAlphabet is the Base58 alphabet used by Bitcoin and IPFS.
+	Base58BitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	// Base58FlickrAlphabet is the Base58 alphabet used by Flickr short URLs.
+	Base58FlickrAlphabet = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+// Base58Encode returns the Base58 encoding of data using the Bitcoin alphabet
+func Base58Encode(data []byte) string {
+	return EncodeWithAlphabet(data, Base58BitcoinAlphabet)
+}
+
+// Base58Decode decodes a Base58 string using the Bitcoin alphabet
+func Base58Decode(encoded string) ([]byte, error) {
+	return DecodeWithAlphabet(encoded, Base58BitcoinAlphabet)
+}
+
+// Base58FlickrEncode returns the Base58 encoding of data using the Flickr alphabet
+func Base58FlickrEncode(data []byte) string {
+	return EncodeWithAlphabet(data, Base58FlickrAlphabet)
+}
+
+// Base58FlickrDecode decodes a Base58 string using the Flickr alphabet
+func Base58FlickrDecode(encoded string) ([]byte, error) {
+	return DecodeWithAlphabet(encoded, Base58FlickrAlphabet)
+}
+
+// Ascii85Encode returns the Ascii85 encoding of data, without the <~ ~> delimiters
+func Ascii85Encode(data []byte) string {
+	buf := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	n := ascii85.Encode(buf, data)
+	return string(buf[:n])
+}
+
+// Ascii85Decode decodes an Ascii85 string, with or without the <~ ~>
+// delimiters. Whitespace is ignored.
+func Ascii85Decode(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	if strings.HasPrefix(encoded, "<~") && strings.HasSuffix(encoded, "~>") {
+		encoded = encoded[2 : len(encoded)-2]
+	}
+	buf := make([]byte, 4*len(encoded)+4)
+	n, _, err := ascii85.Decode(buf, []byte(encoded), true)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// EncodeWithAlphabet encodes data as a number in the base given by the
+// length of alphabet, in the style of Base58: each leading zero byte is kept
+// as the first symbol of the alphabet. It panics if alphabet has fewer than
+// two symbols or repeats one.
+func EncodeWithAlphabet(data []byte, alphabet string) string {
+	symbols := alphabetSymbols(alphabet)
+	base := len(symbols)
+
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// Repeatedly divide the big-endian number by the base, least significant digit first
+	digits := make([]int, 0, len(data)*138/100+1)
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += digits[i] << 8
+			digits[i] = carry % base
+			carry /= base
+		}
+		for carry > 0 {
+			digits = append(digits, carry%base)
+			carry /= base
+		}
+	}
+
+	var sb strings.Builder
+	for range zeros {
+		sb.WriteRune(symbols[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		sb.WriteRune(symbols[digits[i]])
+	}
+	return sb.String()
+}
+
+// DecodeWithAlphabet reverses EncodeWithAlphabet. It panics if alphabet is
+// invalid, as EncodeWithAlphabet does.
+func DecodeWithAlphabet(encoded, alphabet string) ([]byte, error) {
+	symbols := alphabetSymbols(alphabet)
+	base := len(symbols)
+	index := make(map[rune]int, base)
+	for i, r := range symbols {
+		index[r] = i
+	}
+
+	runes := []rune(encoded)
+	zeros := 0
+	for zeros < len(runes) && runes[zeros] == symbols[0] {
+		zeros++
+	}
+
+	// Multiply the number so far by the base and add each digit, least significant byte first
+	var out []byte
+	for i, r := range runes[zeros:] {
+		carry, ok := index[r]
+		if !ok {
+			return nil, fmt.Errorf("could not decode: invalid character %q at position %d", r, zeros+i)
+		}
+		for j := range out {
+			carry += int(out[j]) * base
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			out = append(out, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	result := make([]byte, zeros, zeros+len(out))
+	for i := len(out) - 1; i >= 0; i-- {
+		result = append(result, out[i])
+	}
+	return result, nil
+}
+
+// alphabetSymbols splits alphabet into its symbols, panicking if it is unusable.
+func alphabetSymbols(alphabet string) []rune {
+	if !utf8.ValidString(alphabet) {
+		panic("encodingExt: alphabet is not valid UTF-8")
+	}
+	symbols := []rune(alphabet)
+	if len(symbols) < 2 {
+		panic("encodingExt: alphabet needs at least two symbols")
+	}
+	seen := make(map[rune]bool, len(symbols))
+	for _, r := range symbols {
+		if seen[r] {
+			panic(fmt.Sprintf("encodingExt: alphabet repeats %q", r))
+		}
+		seen[r] = true
+	}
+	return symbols
+}
+
+// IsBase58 checks if a string is valid Base58 in the Bitcoin alphabet
+func IsBase58(s string) bool {
+	return s != "" && strings.Trim(s, Base58BitcoinAlphabet) == ""
+}
@@ -0,0 +1,35 @@
+package encodingExt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DecodeFile reads the file at path and decodes it into v, choosing the
+// codec by extension: .json, .xml, .yaml or .yml, and .toml.
+func DecodeFile(path string, v interface{}) error {
+	var unmarshal func([]byte, interface{}) error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		unmarshal = JSONUnmarshal
+	case ".xml":
+		unmarshal = XMLUnmarshal
+	case ".yaml", ".yml":
+		unmarshal = YAMLUnmarshal
+	case ".toml":
+		unmarshal = TOMLUnmarshal
+	default:
+		return fmt.Errorf("could not decode %s: unsupported file extension %q", path, ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %v", path, err)
+	}
+	if err := unmarshal(data, v); err != nil {
+		return fmt.Errorf("could not decode %s: %v", path, err)
+	}
+	return nil
+}
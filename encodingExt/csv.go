@@ -0,0 +1,343 @@
+package encodingExt
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOption configures the CSV encoders and decoders.
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	delimiter rune
+	noHeader  bool
+}
+
+// CSVDelimiter sets the field delimiter, which defaults to a comma.
+func CSVDelimiter(r rune) CSVOption {
+	return func(o *csvOptions) {
+		o.delimiter = r
+	}
+}
+
+// CSVNoHeader disables the header row. Encoders write only data rows and
+// decoders map columns to struct fields in declaration order.
+func CSVNoHeader() CSVOption {
+	return func(o *csvOptions) {
+		o.noHeader = true
+	}
+}
+
+func newCSVOptions(opts []CSVOption) csvOptions {
+	o := csvOptions{delimiter: ','}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// csvStruct returns the struct type behind T and its fields.
+func csvStruct[T any]() (reflect.Type, []fieldInfo, error) {
+	t := reflect.TypeFor[T]()
+	st := t
+	if st.Kind() == reflect.Pointer {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("could not use %v for CSV: need a struct type", t)
+	}
+	return st, structFields(st, "csv"), nil
+}
+
+// CSVWriter writes structs of type T as CSV rows. Columns are named by
+// `csv:"name"` tags, falling back to the field name, and fields tagged
+// "-" are skipped.
+type CSVWriter[T any] struct {
+	w             *csv.Writer
+	fields        []fieldInfo
+	header        bool
+	headerWritten bool
+}
+
+// NewCSVWriter returns a CSVWriter that writes to w. The header row is
+// written before the first record.
+func NewCSVWriter[T any](w io.Writer, opts ...CSVOption) (*CSVWriter[T], error) {
+	o := newCSVOptions(opts)
+	_, fields, err := csvStruct[T]()
+	if err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = o.delimiter
+	return &CSVWriter[T]{w: cw, fields: fields, header: !o.noHeader}, nil
+}
+
+// WriteHeader writes the header row if it has not been written yet. Write
+// calls it automatically; call it directly to emit a header with no rows.
+func (cw *CSVWriter[T]) WriteHeader() error {
+	if !cw.header || cw.headerWritten {
+		return nil
+	}
+	names := make([]string, len(cw.fields))
+	for i, f := range cw.fields {
+		names[i] = f.name
+	}
+	if err := cw.w.Write(names); err != nil {
+		return err
+	}
+	cw.headerWritten = true
+	return nil
+}
+
+// Write writes row as a CSV record.
+func (cw *CSVWriter[T]) Write(row T) error {
+	if err := cw.WriteHeader(); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(row)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return fmt.Errorf("could not encode CSV row: nil %v", v.Type())
+		}
+		v = v.Elem()
+	}
+	record := make([]string, len(cw.fields))
+	for i, f := range cw.fields {
+		fv := fieldByIndex(v, f.index, false)
+		if !fv.IsValid() {
+			continue
+		}
+		s, err := formatCSVField(fv)
+		if err != nil {
+			return fmt.Errorf("could not encode CSV column %s: %v", f.name, err)
+		}
+		record[i] = s
+	}
+	return cw.w.Write(record)
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (cw *CSVWriter[T]) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// CSVReader reads CSV rows into structs of type T, matching header names to
+// `csv:"name"` tags or field names. Columns without a matching field are
+// ignored and fields without a column are left at their zero value.
+type CSVReader[T any] struct {
+	r       *csv.Reader
+	st      reflect.Type
+	columns []*fieldInfo
+}
+
+// NewCSVReader returns a CSVReader that reads from r, consuming the header
+// row unless CSVNoHeader is given.
+func NewCSVReader[T any](r io.Reader, opts ...CSVOption) (*CSVReader[T], error) {
+	o := newCSVOptions(opts)
+	st, fields, err := csvStruct[T]()
+	if err != nil {
+		return nil, err
+	}
+	cr := csv.NewReader(r)
+	cr.Comma = o.delimiter
+	cr.ReuseRecord = true
+
+	reader := &CSVReader[T]{r: cr, st: st}
+	if o.noHeader {
+		for i := range fields {
+			reader.columns = append(reader.columns, &fields[i])
+		}
+		return reader, nil
+	}
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return reader, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %v", err)
+	}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		reader.columns = append(reader.columns, matchField(fields, name))
+	}
+	return reader, nil
+}
+
+// Read returns the next row, or io.EOF when there are no more.
+func (cr *CSVReader[T]) Read() (T, error) {
+	var row T
+	record, err := cr.r.Read()
+	if err != nil {
+		return row, err
+	}
+
+	v := reflect.ValueOf(&row).Elem()
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(cr.st))
+		v = v.Elem()
+	}
+	for i, value := range record {
+		if i >= len(cr.columns) || cr.columns[i] == nil {
+			continue
+		}
+		f := cr.columns[i]
+		if err := parseCSVField(fieldByIndex(v, f.index, true), value); err != nil {
+			line, _ := cr.r.FieldPos(i)
+			return row, fmt.Errorf("could not decode CSV line %d column %s: %v", line, f.name, err)
+		}
+	}
+	return row, nil
+}
+
+// CSVMarshal encodes rows as CSV, with a header row unless CSVNoHeader is given.
+func CSVMarshal[T any](rows []T, opts ...CSVOption) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := NewCSVWriter[T](&buf, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.WriteHeader(); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CSVUnmarshal decodes CSV data into rows, replacing its contents.
+func CSVUnmarshal[T any](data []byte, rows *[]T, opts ...CSVOption) error {
+	r, err := NewCSVReader[T](bytes.NewReader(data), opts...)
+	if err != nil {
+		return err
+	}
+	var out []T
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		out = append(out, row)
+	}
+	*rows = out
+	return nil
+}
+
+// formatCSVField formats a field value as CSV text.
+func formatCSVField(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	case v.CanAddr() && v.Addr().Type().Implements(textMarshalerType):
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %v", v.Type())
+}
+
+// parseCSVField parses CSV text into a field. Empty text leaves pointers nil
+// and other fields at their zero value.
+func parseCSVField(v reflect.Value, s string) error {
+	if s == "" {
+		v.SetZero()
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == timeType:
+		t, err := parseTimeText(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Addr().Type().Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
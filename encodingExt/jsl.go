@@ -0,0 +1,119 @@
+package encodingExt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// JSONLinesEncoder writes values as JSON Lines (NDJSON), one compact JSON
+// document per line.
+type JSONLinesEncoder struct {
+	enc *json.Encoder
+}
+
+// NewJSONLinesEncoder returns an encoder that writes to w.
+func NewJSONLinesEncoder(w io.Writer) *JSONLinesEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONLinesEncoder{enc: enc}
+}
+
+// Encode writes v followed by a newline.
+func (e *JSONLinesEncoder) Encode(v interface{}) error {
+	return e.enc.Encode(v)
+}
+
+// JSONLineError reports a line that is not a valid JSON document.
+type JSONLineError struct {
+	Line int
+	Err  error
+}
+
+func (e *JSONLineError) Error() string {
+	return fmt.Sprintf("could not decode JSON line %d: %v", e.Line, e.Err)
+}
+
+func (e *JSONLineError) Unwrap() error {
+	return e.Err
+}
+
+// JSONLinesDecoder reads JSON Lines one document at a time, skipping blank
+// lines. Lines may be of any length.
+type JSONLinesDecoder struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewJSONLinesDecoder returns a decoder that reads from r.
+func NewJSONLinesDecoder(r io.Reader) *JSONLinesDecoder {
+	return &JSONLinesDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next line into v. It returns io.EOF when there are no
+// more lines. A line that fails to parse is reported as a *JSONLineError
+// and consumed, so decoding can continue with the next one.
+func (d *JSONLinesDecoder) Decode(v interface{}) error {
+	for {
+		data, err := d.r.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return err
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		d.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return &JSONLineError{Line: d.line, Err: err}
+		}
+		return nil
+	}
+}
+
+// Line returns the number of the line last read.
+func (d *JSONLinesDecoder) Line() int {
+	return d.line
+}
+
+// ReadJSONLines returns a sequence of the values in r. A line that fails to
+// parse yields its error and iteration continues; a read error ends the
+// sequence. Use iterExt.StopOnError to stop at the first error instead.
+func ReadJSONLines[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		dec := NewJSONLinesDecoder(r)
+		for {
+			var v T
+			err := dec.Decode(&v)
+			if err == io.EOF {
+				return
+			}
+			if !yield(v, err) {
+				return
+			}
+			var lineErr *JSONLineError
+			if err != nil && !errors.As(err, &lineErr) {
+				return
+			}
+		}
+	}
+}
+
+// WriteJSONLines writes every value in seq to w as JSON Lines.
+func WriteJSONLines[T any](w io.Writer, seq iter.Seq[T]) error {
+	enc := NewJSONLinesEncoder(w)
+	for v := range seq {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package encodingExt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/C0d3-5t3w/myT00L5/bytesExt"
+)
+
+// JSONCanonicalize encodes v as canonical JSON following the JSON
+// Canonicalization Scheme (RFC 8785): no insignificant whitespace, object
+// keys sorted by UTF-16 code units, minimal string escaping and numbers in
+// their shortest round-trip form. Equal values always produce identical
+// bytes, which makes the output suitable for hashing and signing. As in
+// JavaScript, numbers are IEEE 754 doubles, so integers beyond 2^53 lose
+// precision.
+func JSONCanonicalize(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := decodeJSONTree(data)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytesExt.GetBuffer(len(data))
+	defer bytesExt.PutBuffer(buf)
+	if err := writeCanonicalJSON(buf, tree); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// decodeJSONTree decodes data into generic values, keeping numbers exact.
+func decodeJSONTree(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return tree, nil
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, node any) error {
+	switch n := node.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(n))
+	case json.Number:
+		f, err := strconv.ParseFloat(string(n), 64)
+		if err != nil {
+			return fmt.Errorf("could not canonicalize number %s: %v", n, err)
+		}
+		buf.WriteString(canonicalNumber(f))
+	case string:
+		writeCanonicalString(buf, n)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range n {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, n[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("could not canonicalize %T", node)
+	}
+	return nil
+}
+
+// canonicalNumber formats f as ECMAScript's Number.prototype.toString does.
+func canonicalNumber(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+	abs := math.Abs(f)
+	if abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	// Exponents are written without padding, as in 1e+21 and 1.5e-7
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mant, exp, _ := strings.Cut(s, "e")
+	sign, digits := exp[:1], strings.TrimLeft(exp[1:], "0")
+	return mant + "e" + sign + digits
+}
+
+// writeCanonicalString writes s with only the escapes RFC 8785 requires.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// JSONMergePatch applies an RFC 7386 merge patch to the original document:
+// object members in the patch replace those in the original, members set to
+// null are removed, and any other patch value replaces the original
+// entirely. Empty input for original is treated as null.
+func JSONMergePatch(original, patch []byte) ([]byte, error) {
+	var target any
+	if len(bytes.TrimSpace(original)) > 0 {
+		var err error
+		if target, err = decodeJSONTree(original); err != nil {
+			return nil, fmt.Errorf("could not decode original: %v", err)
+		}
+	}
+	p, err := decodeJSONTree(patch)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode patch: %v", err)
+	}
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]any)
+	if !ok {
+		tm = make(map[string]any)
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		tm[k] = mergePatch(tm[k], v)
+	}
+	return tm
+}
+
+// CreateMergePatch returns the RFC 7386 merge patch that turns a into b,
+// so that JSONMergePatch(a, patch) yields a document equal to b. Because a
+// null in a merge patch means removal, members of b whose value is null
+// cannot be expressed and are dropped.
+func CreateMergePatch(a, b []byte) ([]byte, error) {
+	ta, err := decodeJSONTree(a)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode original: %v", err)
+	}
+	tb, err := decodeJSONTree(b)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode modified: %v", err)
+	}
+	return json.Marshal(diffMergePatch(ta, tb))
+}
+
+func diffMergePatch(a, b any) any {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok {
+		return b
+	}
+	patch := make(map[string]any)
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, bv := range bm {
+		av, ok := am[k]
+		if bv == nil {
+			if ok {
+				patch[k] = nil
+			}
+			continue
+		}
+		if ok && reflect.DeepEqual(av, bv) {
+			continue
+		}
+		patch[k] = diffMergePatch(av, bv)
+	}
+	return patch
+}
@@ -0,0 +1,388 @@
+package encodingExt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LogfmtField is a key and its value in a logfmt record.
+type LogfmtField struct {
+	Key   string
+	Value string
+}
+
+// LogfmtMarshal encodes a map or struct as a single logfmt record, such as
+//
+//	level=info msg="user logged in" user=42
+//
+// Map keys are sorted. Struct fields are named by `logfmt:"name"` tags,
+// falling back to the field name, "-" skips a field and omitempty skips zero
+// values. The record has no trailing newline.
+func LogfmtMarshal(v interface{}) ([]byte, error) {
+	fields, err := logfmtFields(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return AppendLogfmt(nil, fields...), nil
+}
+
+// LogfmtUnmarshal decodes a single logfmt record into a map[string]string,
+// a map[string]any holding strings, or a struct pointer.
+func LogfmtUnmarshal(data []byte, v interface{}) error {
+	fields, err := ParseLogfmt(string(data))
+	if err != nil {
+		return err
+	}
+	return assignLogfmt(fields, v)
+}
+
+// AppendLogfmt appends fields to dst as a logfmt record. Characters not
+// allowed in keys are replaced with underscores and values are quoted when
+// needed.
+func AppendLogfmt(dst []byte, fields ...LogfmtField) []byte {
+	for i, f := range fields {
+		if i > 0 {
+			dst = append(dst, ' ')
+		}
+		dst = appendLogfmtKey(dst, f.Key)
+		dst = append(dst, '=')
+		dst = appendLogfmtValue(dst, f.Value)
+	}
+	return dst
+}
+
+func appendLogfmtKey(dst []byte, key string) []byte {
+	if key == "" {
+		return append(dst, '_')
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		dst = utf8.AppendRune(dst, r)
+	}
+	return dst
+}
+
+func appendLogfmtValue(dst []byte, value string) []byte {
+	needsQuote := false
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			needsQuote = true
+			break
+		}
+	}
+	if !needsQuote {
+		return append(dst, value...)
+	}
+	return strconv.AppendQuote(dst, value)
+}
+
+// FormatLogfmtValue formats a value the way LogfmtMarshal does: nil as
+// null, errors by their message, then fmt.Stringer and
+// encoding.TextMarshaler, then fmt's default format.
+func FormatLogfmtValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return x
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	case encoding.TextMarshaler:
+		text, err := x.MarshalText()
+		if err != nil {
+			return err.Error()
+		}
+		return string(text)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "null"
+		}
+		return FormatLogfmtValue(rv.Elem().Interface())
+	}
+	if s, err := formatCSVField(rv); err == nil {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// logfmtFields lists the fields of a map or struct.
+func logfmtFields(rv reflect.Value) ([]LogfmtField, error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("could not encode %v as logfmt: map keys must be strings", rv.Type())
+		}
+		fields := make([]LogfmtField, 0, rv.Len())
+		for it := rv.MapRange(); it.Next(); {
+			fields = append(fields, LogfmtField{Key: it.Key().String(), Value: logfmtValueOf(it.Value())})
+		}
+		slices.SortFunc(fields, func(a, b LogfmtField) int { return strings.Compare(a.Key, b.Key) })
+		return fields, nil
+	case reflect.Struct:
+		var fields []LogfmtField
+		for _, f := range structFields(rv.Type(), "logfmt") {
+			fv := fieldByIndex(rv, f.index, false)
+			if !fv.IsValid() || f.omitEmpty && fv.IsZero() {
+				continue
+			}
+			fields = append(fields, LogfmtField{Key: f.name, Value: logfmtValueOf(fv)})
+		}
+		return fields, nil
+	}
+	return nil, fmt.Errorf("could not encode %v as logfmt: need a map or struct", rv.Type())
+}
+
+func logfmtValueOf(v reflect.Value) string {
+	if !v.IsValid() || (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil() {
+		return "null"
+	}
+	if v.CanInterface() {
+		return FormatLogfmtValue(v.Interface())
+	}
+	return fmt.Sprint(v)
+}
+
+// LogfmtSyntaxError reports malformed logfmt.
+type LogfmtSyntaxError struct {
+	Line   int // 0 when parsing a single record
+	Column int // byte offset in the line, from 1
+	Msg    string
+}
+
+func (e *LogfmtSyntaxError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("could not parse logfmt line %d column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("could not parse logfmt column %d: %s", e.Column, e.Msg)
+}
+
+// ParseLogfmt parses a single logfmt record. A key without "=" has an empty
+// value.
+func ParseLogfmt(line string) ([]LogfmtField, error) {
+	var fields []LogfmtField
+	i := 0
+	for {
+		for i < len(line) && isLogfmtSpace(line[i]) {
+			i++
+		}
+		if i == len(line) {
+			return fields, nil
+		}
+
+		start := i
+		for i < len(line) && !isLogfmtSpace(line[i]) && line[i] != '=' && line[i] != '"' {
+			i++
+		}
+		if i == start {
+			return nil, &LogfmtSyntaxError{Column: i + 1, Msg: fmt.Sprintf("unexpected %q, want a key", line[i])}
+		}
+		key := line[start:i]
+		if i == len(line) || line[i] != '=' {
+			if i < len(line) && line[i] == '"' {
+				return nil, &LogfmtSyntaxError{Column: i + 1, Msg: `unexpected '"' in key`}
+			}
+			fields = append(fields, LogfmtField{Key: key})
+			continue
+		}
+		i++ // '='
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, &LogfmtSyntaxError{Column: i + 1, Msg: "unterminated quoted value"}
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, &LogfmtSyntaxError{Column: i + 1, Msg: "invalid escape in quoted value"}
+			}
+			fields = append(fields, LogfmtField{Key: key, Value: value})
+			i = end + 1
+			continue
+		}
+		start = i
+		for i < len(line) && !isLogfmtSpace(line[i]) {
+			if line[i] == '"' {
+				return nil, &LogfmtSyntaxError{Column: i + 1, Msg: `unexpected '"' in value`}
+			}
+			i++
+		}
+		fields = append(fields, LogfmtField{Key: key, Value: line[start:i]})
+	}
+}
+
+func isLogfmtSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r'
+}
+
+// assignLogfmt stores fields in a map or struct pointer. A key repeated in a
+// record keeps its last value.
+func assignLogfmt(fields []LogfmtField, v interface{}) error {
+	switch m := v.(type) {
+	case *map[string]string:
+		if *m == nil {
+			*m = make(map[string]string, len(fields))
+		}
+		for _, f := range fields {
+			(*m)[f.Key] = f.Value
+		}
+		return nil
+	case *map[string]any:
+		if *m == nil {
+			*m = make(map[string]any, len(fields))
+		}
+		for _, f := range fields {
+			(*m)[f.Key] = f.Value
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("could not decode logfmt into %T: need a map or struct pointer", v)
+	}
+	rv = rv.Elem()
+	infos := structFields(rv.Type(), "logfmt")
+	for _, f := range fields {
+		info := matchField(infos, f.Key)
+		if info == nil {
+			continue
+		}
+		fv := fieldByIndex(rv, info.index, true)
+		value := f.Value
+		// A bare key is a flag.
+		if value == "" && fv.Kind() == reflect.Bool {
+			value = "true"
+		}
+		if err := parseCSVField(fv, value); err != nil {
+			return fmt.Errorf("could not decode logfmt key %s: %v", f.Key, err)
+		}
+	}
+	return nil
+}
+
+// LogfmtEncoder writes logfmt records, one per line.
+type LogfmtEncoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewLogfmtEncoder returns an encoder that writes to w.
+func NewLogfmtEncoder(w io.Writer) *LogfmtEncoder {
+	return &LogfmtEncoder{w: w}
+}
+
+// Encode writes a map or struct as a record, as LogfmtMarshal does.
+func (e *LogfmtEncoder) Encode(v interface{}) error {
+	fields, err := logfmtFields(reflect.ValueOf(v))
+	if err != nil {
+		return err
+	}
+	return e.EncodeFields(fields...)
+}
+
+// EncodeKeyvals writes alternating keys and values as a record, keeping
+// their order:
+//
+//	enc.EncodeKeyvals("level", "info", "msg", "started", "port", 8080)
+func (e *LogfmtEncoder) EncodeKeyvals(keyvals ...interface{}) error {
+	fields := make([]LogfmtField, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		fields = append(fields, LogfmtField{Key: fmt.Sprint(keyvals[i]), Value: FormatLogfmtValue(value)})
+	}
+	return e.EncodeFields(fields...)
+}
+
+// EncodeFields writes fields as a record.
+func (e *LogfmtEncoder) EncodeFields(fields ...LogfmtField) error {
+	e.buf = append(AppendLogfmt(e.buf[:0], fields...), '\n')
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+// LogfmtDecoder reads logfmt records one line at a time, skipping blank
+// lines. Lines may be of any length.
+type LogfmtDecoder struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewLogfmtDecoder returns a decoder that reads from r.
+func NewLogfmtDecoder(r io.Reader) *LogfmtDecoder {
+	return &LogfmtDecoder{r: bufio.NewReader(r)}
+}
+
+// Next returns the fields of the next record, or io.EOF when there are no
+// more. A line that fails to parse is reported as a *LogfmtSyntaxError and
+// consumed, so decoding can continue with the next one.
+func (d *LogfmtDecoder) Next() ([]LogfmtField, error) {
+	for {
+		data, err := d.r.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return nil, err
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		d.line++
+
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		fields, err := ParseLogfmt(string(bytes.TrimRight(data, "\r\n")))
+		if err != nil {
+			var syntaxErr *LogfmtSyntaxError
+			if errors.As(err, &syntaxErr) {
+				syntaxErr.Line = d.line
+			}
+			return nil, err
+		}
+		return fields, nil
+	}
+}
+
+// Decode reads the next record into a map or struct pointer, as
+// LogfmtUnmarshal does.
+func (d *LogfmtDecoder) Decode(v interface{}) error {
+	fields, err := d.Next()
+	if err != nil {
+		return err
+	}
+	return assignLogfmt(fields, v)
+}
+
+// Line returns the number of the line last read.
+func (d *LogfmtDecoder) Line() int {
+	return d.line
+}
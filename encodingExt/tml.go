@@ -0,0 +1,886 @@
+package encodingExt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TOMLMarshal encodes v, which must be a struct or map, as a TOML document.
+// Struct fields use their `toml:"name,omitempty"` tags; nested structs and
+// maps become tables, slices of them arrays of tables, and nil values are
+// left out since TOML has no null.
+func TOMLMarshal(v interface{}) ([]byte, error) {
+	node, err := toTree(reflect.ValueOf(v), "toml")
+	if err != nil {
+		return nil, fmt.Errorf("could not encode TOML: %v", err)
+	}
+	table, ok := node.(*mapNode)
+	if !ok {
+		return nil, fmt.Errorf("could not encode TOML: top-level value must be a table, not %T", v)
+	}
+	var sb strings.Builder
+	if err := writeTOMLTable(&sb, nil, table); err != nil {
+		return nil, fmt.Errorf("could not encode TOML: %v", err)
+	}
+	return []byte(strings.TrimPrefix(sb.String(), "\n")), nil
+}
+
+// TOMLUnmarshal decodes a TOML 1.0 document into v. Dates and times
+// without an offset decode into time.Time in UTC, or as their TOML text
+// when the destination is a string or interface.
+func TOMLUnmarshal(data []byte, v interface{}) error {
+	node, err := parseTOML(string(data))
+	if err != nil {
+		return err
+	}
+	return unmarshalTree(node, v, "toml")
+}
+
+// isTOMLTable reports whether node is written as a [table].
+func isTOMLTable(node any) bool {
+	_, ok := node.(*mapNode)
+	return ok
+}
+
+// isTOMLTableArray reports whether node is written as an [[array of tables]].
+func isTOMLTableArray(node any) bool {
+	list, ok := node.([]any)
+	if !ok || len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if !isTOMLTable(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTOMLTable writes the entries of table, then its sub-tables.
+func writeTOMLTable(sb *strings.Builder, path []string, table *mapNode) error {
+	var children []string
+	for _, key := range table.keys {
+		value := table.values[key]
+		if value == nil {
+			continue
+		}
+		if isTOMLTable(value) || isTOMLTableArray(value) {
+			children = append(children, key)
+			continue
+		}
+		text, err := tomlValue(value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", strings.Join(append(path, key), "."), err)
+		}
+		sb.WriteString(tomlKey(key) + " = " + text + "\n")
+	}
+
+	for _, key := range children {
+		childPath := append(append([]string(nil), path...), key)
+		header := tomlPath(childPath)
+		switch value := table.values[key].(type) {
+		case *mapNode:
+			// Tables holding only sub-tables are implied by their children
+			if hasTOMLEntries(value) || !hasTOMLChildren(value) {
+				sb.WriteString("\n[" + header + "]\n")
+			}
+			if err := writeTOMLTable(sb, childPath, value); err != nil {
+				return err
+			}
+		case []any:
+			for _, item := range value {
+				sb.WriteString("\n[[" + header + "]]\n")
+				if err := writeTOMLTable(sb, childPath, item.(*mapNode)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasTOMLEntries reports whether table has key/value entries of its own.
+func hasTOMLEntries(table *mapNode) bool {
+	for _, key := range table.keys {
+		if v := table.values[key]; v != nil && !isTOMLTable(v) && !isTOMLTableArray(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTOMLChildren reports whether table has sub-tables or arrays of tables.
+func hasTOMLChildren(table *mapNode) bool {
+	for _, key := range table.keys {
+		if v := table.values[key]; isTOMLTable(v) || isTOMLTableArray(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// tomlValue formats an inline value.
+func tomlValue(node any) (string, error) {
+	switch n := node.(type) {
+	case bool:
+		return strconv.FormatBool(n), nil
+	case int64:
+		return strconv.FormatInt(n, 10), nil
+	case uint64:
+		return "", fmt.Errorf("integer %d overflows int64", n)
+	case float64:
+		switch {
+		case math.IsInf(n, 1):
+			return "inf", nil
+		case math.IsInf(n, -1):
+			return "-inf", nil
+		case math.IsNaN(n):
+			return "nan", nil
+		}
+		s := strconv.FormatFloat(n, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s, nil
+	case string:
+		return tomlString(n), nil
+	case time.Time:
+		return n.Format(time.RFC3339Nano), nil
+	case localTime:
+		return n.text, nil
+	case []any:
+		parts := make([]string, len(n))
+		for i, item := range n {
+			if item == nil {
+				return "", fmt.Errorf("arrays cannot contain null")
+			}
+			text, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = text
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case *mapNode:
+		parts := make([]string, 0, len(n.keys))
+		for _, key := range n.keys {
+			if n.values[key] == nil {
+				continue
+			}
+			text, err := tomlValue(n.values[key])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, tomlKey(key)+" = "+text)
+		}
+		if len(parts) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	}
+	return "", fmt.Errorf("unsupported value %T", node)
+}
+
+// tomlKey returns key bare if possible, else quoted.
+func tomlKey(key string) string {
+	if key != "" && strings.Trim(key, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-") == "" {
+		return key
+	}
+	return tomlString(key)
+}
+
+func tomlPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range strings.ToValidUTF8(s, "\uFFFD") {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// tomlParser is a recursive descent parser over a whole document.
+type tomlParser struct {
+	s    string
+	i    int
+	root map[string]any
+
+	// Tables keyed by canonical path, recording how each was created
+	kinds map[string]tomlKind
+}
+
+type tomlKind int
+
+const (
+	tomlImplicit tomlKind = iota // created as the parent of another table
+	tomlHeader                   // defined by a [header]
+	tomlDotted                   // created by a dotted key
+	tomlInline                   // an inline table, closed to additions
+)
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.s[:min(p.i, len(p.s))], "\n") + 1
+	return fmt.Errorf("could not decode TOML: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func parseTOML(src string) (map[string]any, error) {
+	src = strings.TrimPrefix(src, "\ufeff")
+	if !utf8.ValidString(src) {
+		return nil, fmt.Errorf("could not decode TOML: invalid UTF-8")
+	}
+	p := &tomlParser{s: src, root: make(map[string]any), kinds: make(map[string]tomlKind)}
+	current, currentPath := p.root, ""
+
+	for {
+		if err := p.skipSpaceAndNewlines(); err != nil {
+			return nil, err
+		}
+		if p.i >= len(p.s) {
+			return p.root, nil
+		}
+
+		var err error
+		if p.s[p.i] == '[' {
+			current, currentPath, err = p.parseHeader()
+		} else {
+			err = p.parseKeyValue(current, currentPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.i < len(p.s) && p.s[p.i] == '#' {
+			if err := p.skipComment(); err != nil {
+				return nil, err
+			}
+		}
+		if p.i < len(p.s) && !p.atNewline() {
+			return nil, p.errorf("expected end of line, found %q", p.rest())
+		}
+	}
+}
+
+// rest returns the remainder of the current line, for error messages.
+func (p *tomlParser) rest() string {
+	end := strings.IndexByte(p.s[p.i:], '\n')
+	if end < 0 {
+		return p.s[p.i:]
+	}
+	return strings.TrimRight(p.s[p.i:p.i+end], "\r")
+}
+
+func (p *tomlParser) skipSpace() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func (p *tomlParser) atNewline() bool {
+	return p.s[p.i] == '\n' || strings.HasPrefix(p.s[p.i:], "\r\n")
+}
+
+// skipComment moves past a comment to the end of the line.
+func (p *tomlParser) skipComment() error {
+	for p.i < len(p.s) && !p.atNewline() {
+		if c := p.s[p.i]; (c < 0x20 && c != '\t') || c == 0x7f {
+			return p.errorf("control character in comment")
+		}
+		p.i++
+	}
+	return nil
+}
+
+// skipSpaceAndNewlines moves past blank lines and comments.
+func (p *tomlParser) skipSpaceAndNewlines() error {
+	for p.i < len(p.s) {
+		switch {
+		case p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == '\n':
+			p.i++
+		case strings.HasPrefix(p.s[p.i:], "\r\n"):
+			p.i += 2
+		case p.s[p.i] == '#':
+			if err := p.skipComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseHeader parses a [table] or [[array of tables]] header and returns
+// the table that following keys belong to.
+func (p *tomlParser) parseHeader() (map[string]any, string, error) {
+	array := strings.HasPrefix(p.s[p.i:], "[[")
+	if array {
+		p.i += 2
+	} else {
+		p.i++
+	}
+	p.skipSpace()
+	keys, err := p.parseKey()
+	if err != nil {
+		return nil, "", err
+	}
+	p.skipSpace()
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.s[p.i:], closing) {
+		return nil, "", p.errorf("expected %q after table header", closing)
+	}
+	p.i += len(closing)
+
+	parent, parentPath, err := p.descend(p.root, "", keys[:len(keys)-1], tomlImplicit)
+	if err != nil {
+		return nil, "", err
+	}
+	last := keys[len(keys)-1]
+	path := parentPath + "\x00" + last
+
+	if array {
+		var list []any
+		switch existing := parent[last].(type) {
+		case nil:
+		case []any:
+			if p.kinds[path] != tomlHeader {
+				return nil, "", p.errorf("cannot append to static array %s", strings.Join(keys, "."))
+			}
+			list = existing
+		default:
+			return nil, "", p.errorf("key %s is already defined", strings.Join(keys, "."))
+		}
+		table := make(map[string]any)
+		parent[last] = append(list, table)
+		p.kinds[path] = tomlHeader
+		return table, fmt.Sprintf("%s#%d", path, len(list)), nil
+	}
+
+	switch existing := parent[last].(type) {
+	case nil:
+		table := make(map[string]any)
+		parent[last] = table
+		p.kinds[path] = tomlHeader
+		return table, path, nil
+	case map[string]any:
+		if p.kinds[path] != tomlImplicit {
+			return nil, "", p.errorf("table %s is already defined", strings.Join(keys, "."))
+		}
+		p.kinds[path] = tomlHeader
+		return existing, path, nil
+	}
+	return nil, "", p.errorf("key %s is already defined", strings.Join(keys, "."))
+}
+
+// descend walks from table through keys, creating missing tables of the given
+// kind, and returns the innermost table with its canonical path. The last
+// element of an array of tables stands in for the array.
+func (p *tomlParser) descend(table map[string]any, path string, keys []string, kind tomlKind) (map[string]any, string, error) {
+	for _, key := range keys {
+		path += "\x00" + key
+		switch existing := table[key].(type) {
+		case nil:
+			next := make(map[string]any)
+			table[key] = next
+			p.kinds[path] = kind
+			table = next
+		case map[string]any:
+			k := p.kinds[path]
+			if k == tomlInline || (kind == tomlDotted && k != tomlDotted) {
+				return nil, "", p.errorf("cannot add keys to table %s", key)
+			}
+			table = existing
+		case []any:
+			if p.kinds[path] != tomlHeader || kind == tomlDotted {
+				return nil, "", p.errorf("cannot add keys to array %s", key)
+			}
+			last, _ := existing[len(existing)-1].(map[string]any)
+			path = fmt.Sprintf("%s#%d", path, len(existing)-1)
+			table = last
+		default:
+			return nil, "", p.errorf("key %s is already defined", key)
+		}
+	}
+	return table, path, nil
+}
+
+// parseKeyValue parses "key = value" into table.
+func (p *tomlParser) parseKeyValue(table map[string]any, path string) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.i >= len(p.s) || p.s[p.i] != '=' {
+		return p.errorf("expected '=' after key %s", strings.Join(keys, "."))
+	}
+	p.i++
+	p.skipSpace()
+
+	parent, parentPath, err := p.descend(table, path, keys[:len(keys)-1], tomlDotted)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists {
+		return p.errorf("key %s is already defined", strings.Join(keys, "."))
+	}
+	value, err := p.parseValue(parentPath + "\x00" + last)
+	if err != nil {
+		return err
+	}
+	parent[last] = value
+	return nil
+}
+
+// parseKey parses a possibly dotted key.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return nil, p.errorf("expected a key")
+		}
+		var key string
+		switch c := p.s[p.i]; {
+		case c == '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		case c == '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.i
+			for p.i < len(p.s) && isTOMLBareKeyChar(p.s[p.i]) {
+				p.i++
+			}
+			if p.i == start {
+				return nil, p.errorf("invalid key %q", p.rest())
+			}
+			key = p.s[start:p.i]
+		}
+		keys = append(keys, key)
+		p.skipSpace()
+		if p.i >= len(p.s) || p.s[p.i] != '.' {
+			return keys, nil
+		}
+		p.i++
+	}
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseValue parses a value; path is its canonical path for inline tables.
+func (p *tomlParser) parseValue(path string) (any, error) {
+	if p.i >= len(p.s) {
+		return nil, p.errorf("expected a value")
+	}
+	switch c := p.s[p.i]; {
+	case strings.HasPrefix(p.s[p.i:], `"""`):
+		return p.parseMultilineBasicString()
+	case strings.HasPrefix(p.s[p.i:], "'''"):
+		return p.parseMultilineLiteralString()
+	case c == '"':
+		return p.parseBasicString()
+	case c == '\'':
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray(path)
+	case c == '{':
+		return p.parseInlineTable(path)
+	case strings.HasPrefix(p.s[p.i:], "true"):
+		p.i += 4
+		return true, nil
+	case strings.HasPrefix(p.s[p.i:], "false"):
+		p.i += 5
+		return false, nil
+	}
+
+	// Numbers and dates run to the next delimiter; a date and time may be
+	// separated by a single space
+	start := p.i
+	for p.i < len(p.s) && strings.IndexByte(" \t\r\n,]}#", p.s[p.i]) < 0 {
+		p.i++
+	}
+	if p.i+1 < len(p.s) && p.s[p.i] == ' ' && len(p.s[start:p.i]) == 10 && p.s[p.i+1] >= '0' && p.s[p.i+1] <= '9' {
+		p.i++
+		for p.i < len(p.s) && strings.IndexByte(" \t\r\n,]}#", p.s[p.i]) < 0 {
+			p.i++
+		}
+	}
+	token := p.s[start:p.i]
+	if v, ok := parseTOMLDateTime(token); ok {
+		return v, nil
+	}
+	if v, ok := parseTOMLNumber(token); ok {
+		return v, nil
+	}
+	p.i = start
+	return nil, p.errorf("invalid value %q", token)
+}
+
+// parseTOMLNumber parses an integer or float token.
+func parseTOMLNumber(token string) (any, bool) {
+	switch token {
+	case "inf", "+inf":
+		return math.Inf(1), true
+	case "-inf":
+		return math.Inf(-1), true
+	case "nan", "+nan", "-nan":
+		return math.NaN(), true
+	}
+
+	// Underscores must sit between digits
+	for i := 0; i < len(token); i++ {
+		if token[i] == '_' && (i == 0 || i == len(token)-1 || !isHexDigit(token[i-1]) || !isHexDigit(token[i+1])) {
+			return nil, false
+		}
+	}
+	clean := strings.ReplaceAll(token, "_", "")
+
+	for prefix, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if strings.HasPrefix(clean, prefix) {
+			digits := clean[2:]
+			if digits == "" || strings.ContainsAny(digits, "+-") {
+				return nil, false
+			}
+			i, err := strconv.ParseInt(digits, base, 64)
+			return i, err == nil
+		}
+	}
+
+	digits := strings.TrimLeft(clean, "+-")
+	if len(clean)-len(digits) > 1 || digits == "" {
+		return nil, false
+	}
+	if strings.Trim(digits, "0123456789") == "" {
+		if len(digits) > 1 && digits[0] == '0' {
+			return nil, false
+		}
+		i, err := strconv.ParseInt(clean, 10, 64)
+		return i, err == nil
+	}
+
+	// Floats need digits on both sides of the point and no leading zeros
+	mant, exp, hasExp := strings.Cut(strings.ToLower(digits), "e")
+	whole, frac, hasFrac := strings.Cut(mant, ".")
+	if whole == "" || strings.Trim(whole, "0123456789") != "" || (len(whole) > 1 && whole[0] == '0') {
+		return nil, false
+	}
+	if hasFrac && (frac == "" || strings.Trim(frac, "0123456789") != "") {
+		return nil, false
+	}
+	if hasExp {
+		e := strings.TrimLeft(exp, "+-")
+		if e == "" || len(exp)-len(e) > 1 || strings.Trim(e, "0123456789") != "" {
+			return nil, false
+		}
+	}
+	if !hasFrac && !hasExp {
+		return nil, false
+	}
+	f, err := strconv.ParseFloat(clean, 64)
+	return f, err == nil
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// parseTOMLDateTime parses offset date-times into time.Time and local
+// dates and times into localTime.
+func parseTOMLDateTime(token string) (any, bool) {
+	if len(token) < 8 || !(token[2] == ':' || (len(token) >= 10 && token[4] == '-')) {
+		return nil, false
+	}
+	norm := token
+	if len(norm) > 10 && (norm[10] == ' ' || norm[10] == 't') {
+		norm = norm[:10] + "T" + norm[11:]
+	}
+	norm = strings.Replace(norm, "z", "Z", 1)
+
+	if t, err := time.Parse(time.RFC3339Nano, norm); err == nil {
+		return t, true
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", time.DateOnly, "15:04:05.999999999"} {
+		if t, err := time.Parse(layout, norm); err == nil {
+			return localTime{t: t, text: token}, true
+		}
+	}
+	return nil, false
+}
+
+// parseArray parses an array, which may span lines.
+func (p *tomlParser) parseArray(path string) ([]any, error) {
+	p.i++
+	list := []any{}
+	for {
+		if err := p.skipSpaceAndNewlines(); err != nil {
+			return nil, err
+		}
+		if p.i >= len(p.s) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.s[p.i] == ']' {
+			p.i++
+			return list, nil
+		}
+		item, err := p.parseValue(fmt.Sprintf("%s#%d", path, len(list)))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+		if err := p.skipSpaceAndNewlines(); err != nil {
+			return nil, err
+		}
+		if p.i < len(p.s) && p.s[p.i] == ',' {
+			p.i++
+			continue
+		}
+		if p.i < len(p.s) && p.s[p.i] == ']' {
+			p.i++
+			return list, nil
+		}
+		return nil, p.errorf("expected ',' or ']' in array")
+	}
+}
+
+// parseInlineTable parses a single-line { key = value, ... } table.
+func (p *tomlParser) parseInlineTable(path string) (map[string]any, error) {
+	p.i++
+	table := make(map[string]any)
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] == '}' {
+		p.i++
+		p.kinds[path] = tomlInline
+		return table, nil
+	}
+	for {
+		if err := p.parseKeyValue(table, path); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return nil, p.errorf("unterminated inline table")
+		}
+		switch p.s[p.i] {
+		case ',':
+			p.i++
+			p.skipSpace()
+		case '}':
+			p.i++
+			p.kinds[path] = tomlInline
+			return table, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+// parseBasicString parses a "double-quoted" string.
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.i++
+	var sb strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		switch {
+		case c == '"':
+			p.i++
+			return sb.String(), nil
+		case c == '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		case c == '\n' || (c < 0x20 && c != '\t') || c == 0x7f:
+			return "", p.errorf("invalid character in string")
+		default:
+			sb.WriteByte(c)
+			p.i++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseMultilineBasicString parses a basic string in triple double quotes.
+func (p *tomlParser) parseMultilineBasicString() (string, error) {
+	p.i += 3
+	p.skipFirstNewline()
+	var sb strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		switch {
+		case strings.HasPrefix(p.s[p.i:], `"""`):
+			// Up to two extra quotes may close the string
+			end := p.i + 3
+			for end < len(p.s) && end < p.i+5 && p.s[end] == '"' {
+				end++
+			}
+			sb.WriteString(p.s[p.i : end-3])
+			p.i = end
+			return sb.String(), nil
+		case c == '\\':
+			// A backslash at the end of a line trims the following whitespace
+			j := p.i + 1
+			for j < len(p.s) && (p.s[j] == ' ' || p.s[j] == '\t') {
+				j++
+			}
+			if j < len(p.s) && (p.s[j] == '\n' || strings.HasPrefix(p.s[j:], "\r\n")) {
+				p.i = j
+				for p.i < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.i]) >= 0 {
+					p.i++
+				}
+				continue
+			}
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		case strings.HasPrefix(p.s[p.i:], "\r\n"):
+			sb.WriteByte('\n')
+			p.i += 2
+		case c != '\n' && ((c < 0x20 && c != '\t') || c == 0x7f):
+			return "", p.errorf("invalid character in string")
+		default:
+			sb.WriteByte(c)
+			p.i++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseLiteralString parses a 'single-quoted' string.
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.i++
+	start := p.i
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		if c == '\'' {
+			p.i++
+			return p.s[start : p.i-1], nil
+		}
+		if c == '\n' || (c < 0x20 && c != '\t') || c == 0x7f {
+			return "", p.errorf("invalid character in string")
+		}
+		p.i++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseMultilineLiteralString parses a literal string in triple single quotes.
+func (p *tomlParser) parseMultilineLiteralString() (string, error) {
+	p.i += 3
+	p.skipFirstNewline()
+	end := strings.Index(p.s[p.i:], "'''")
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+	end += p.i
+	// Up to two extra quotes may close the string
+	for extra := 0; extra < 2 && end+3 < len(p.s) && p.s[end+3] == '\''; extra++ {
+		end++
+	}
+	s := strings.ReplaceAll(p.s[p.i:end], "\r\n", "\n")
+	for _, c := range []byte(s) {
+		if (c < 0x20 && c != '\t' && c != '\n') || c == 0x7f {
+			return "", p.errorf("invalid character in string")
+		}
+	}
+	p.i = end + 3
+	return s, nil
+}
+
+// skipFirstNewline drops a newline directly after an opening delimiter.
+func (p *tomlParser) skipFirstNewline() {
+	if strings.HasPrefix(p.s[p.i:], "\r\n") {
+		p.i += 2
+	} else if p.i < len(p.s) && p.s[p.i] == '\n' {
+		p.i++
+	}
+}
+
+// parseEscape decodes the escape sequence at p.i into sb.
+func (p *tomlParser) parseEscape(sb *strings.Builder) error {
+	if p.i+1 >= len(p.s) {
+		return p.errorf("invalid escape")
+	}
+	c := p.s[p.i+1]
+	p.i += 2
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'e':
+		sb.WriteByte(0x1b)
+	case '"', '\\':
+		sb.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.i+size > len(p.s) {
+			return p.errorf("invalid escape")
+		}
+		code, err := strconv.ParseUint(p.s[p.i:p.i+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid escape \\%c%s", c, p.s[p.i:p.i+size])
+		}
+		sb.WriteRune(rune(code))
+		p.i += size
+	default:
+		return p.errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
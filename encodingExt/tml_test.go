@@ -0,0 +1,102 @@
+package encodingExt
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOMLUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]any
+	}{
+		{"table", "a = 1\n[t]\nb = \"x\"\n",
+			map[string]any{"a": int64(1), "t": map[string]any{"b": "x"}}},
+		{"dotted key", "a.b = 1\n", map[string]any{"a": map[string]any{"b": int64(1)}}},
+		{"inline table", "x = { y = 1, z = [true, false] }\n",
+			map[string]any{"x": map[string]any{"y": int64(1), "z": []any{true, false}}}},
+		{"array of tables", "[[arr]]\nn = 1\n[[arr]]\nn = 2\n",
+			map[string]any{"arr": []any{map[string]any{"n": int64(1)}, map[string]any{"n": int64(2)}}}},
+		{"numbers", "a = 0x1F\nb = 1_000\nc = -2.5e3\nd = inf\n",
+			map[string]any{"a": int64(31), "b": int64(1000), "c": -2500.0, "d": math.Inf(1)}},
+		{"datetime", "d = 1979-05-27T07:32:00Z\n",
+			map[string]any{"d": time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)}},
+		{"strings", "a = 'C:\\dir'\nb = \"tab\\tend\"\nc = '''\nraw\n'''\n",
+			map[string]any{"a": `C:\dir`, "b": "tab\tend", "c": "raw\n"}},
+		{"comments", "# top\na = 1 # trailing\n", map[string]any{"a": int64(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			if err := TOMLUnmarshal([]byte(tt.in), &got); err != nil {
+				t.Fatalf("TOMLUnmarshal(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TOMLUnmarshal(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOMLUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"a = \n", "invalid value"},
+		{"a = 1\na = 2\n", "already defined"},
+		{"[a]\n[a]\n", "already defined"},
+		{"a = [1, 2\n", "expected ',' or ']'"},
+		{"a = \"x\n", "invalid character in string"},
+		{"[t\n", "after table header"},
+		{"a = 1 b\n", "expected end of line"},
+		{"= 1\n", "invalid key"},
+		{"a = {b = 1\n", ""},
+		{"a = '''\nnever closed\n", ""},
+	}
+	for _, tt := range tests {
+		var got map[string]any
+		err := TOMLUnmarshal([]byte(tt.in), &got)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("TOMLUnmarshal(%q) error = %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestTOMLRoundTrip(t *testing.T) {
+	type server struct {
+		Name string `toml:"name"`
+		Port int    `toml:"port"`
+	}
+	type config struct {
+		Title   string            `toml:"title"`
+		Debug   bool              `toml:"debug"`
+		Ratio   float64           `toml:"ratio"`
+		Hosts   []string          `toml:"hosts"`
+		Servers []server          `toml:"servers"`
+		Labels  map[string]string `toml:"labels"`
+	}
+	in := config{
+		Title:   "multi\nline \"quoted\"",
+		Debug:   true,
+		Ratio:   0.25,
+		Hosts:   []string{"a", "b"},
+		Servers: []server{{"x", 1}, {"y", 2}},
+		Labels:  map[string]string{"env": "prod"},
+	}
+	data, err := TOMLMarshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out config
+	if err := TOMLUnmarshal(data, &out); err != nil {
+		t.Fatalf("TOMLUnmarshal(%q): %v", data, err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip through\n%s\ngot %#v, want %#v", data, out, in)
+	}
+}
@@ -0,0 +1,658 @@
+package encodingExt
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The YAML and TOML codecs share a reflection layer: values are converted to
+// a tree of nil, bool, int64, uint64, float64, string, time.Time, []any and
+// *mapNode before encoding, and decoded documents are trees of the same
+// scalars plus map[string]any, which are then assigned to the destination.
+
+// mapNode is a mapping that keeps its keys in encoding order.
+type mapNode struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *mapNode) set(key string, value any) {
+	if m.values == nil {
+		m.values = make(map[string]any)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// plainScalar is an unquoted YAML scalar. Its type is resolved against the
+// destination, so "1.10" stays "1.10" when decoded into a string.
+type plainScalar string
+
+// localTime is a TOML date or time without an offset, kept with its source
+// text so it can be decoded into either a time.Time or a string.
+type localTime struct {
+	t    time.Time
+	text string
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// fieldInfo describes a struct field as seen by the YAML and TOML codecs.
+type fieldInfo struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields returns the encodable fields of t using the given tag key.
+// Like encoding/json, untagged embedded structs are flattened into their
+// parent and fields tagged "-" are skipped.
+func structFields(t reflect.Type, tagKey string) []fieldInfo {
+	var fields []fieldInfo
+	seen := make(map[string]bool)
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			tag := sf.Tag.Get(tagKey)
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			idx := append(append([]int(nil), index...), i)
+
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+				walk(ft, idx)
+				continue
+			}
+			if !sf.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			fields = append(fields, fieldInfo{
+				name:      name,
+				index:     idx,
+				omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+			})
+		}
+	}
+	walk(t, nil)
+	return fields
+}
+
+// fieldByIndex returns the field at index, allocating nil embedded pointers
+// when alloc is set. It returns an invalid Value if a pointer is nil.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// toTree converts v into an encoding tree.
+func toTree(v reflect.Value, tagKey string) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Kind() == reflect.Interface {
+			return toTree(v.Elem(), tagKey)
+		}
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time), nil
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type() == reflect.TypeFor[localTime]():
+		return v.Interface().(localTime), nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		return toTree(v.Elem(), tagKey)
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.IsNil() {
+				return nil, nil
+			}
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		list := make([]any, v.Len())
+		for i := range list {
+			item, err := toTree(v.Index(i), tagKey)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		return list, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		node := &mapNode{}
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			key, err := mapKeyString(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			values[key] = iter.Value()
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			item, err := toTree(values[key], tagKey)
+			if err != nil {
+				return nil, err
+			}
+			node.set(key, item)
+		}
+		return node, nil
+	case reflect.Struct:
+		node := &mapNode{values: make(map[string]any)}
+		for _, f := range structFields(v.Type(), tagKey) {
+			fv := fieldByIndex(v, f.index, false)
+			if !fv.IsValid() || (f.omitEmpty && fv.IsZero()) {
+				continue
+			}
+			if f.omitEmpty && (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map) && fv.Len() == 0 {
+				continue
+			}
+			item, err := toTree(fv, tagKey)
+			if err != nil {
+				return nil, err
+			}
+			node.set(f.name, item)
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", v.Type())
+}
+
+// mapKeyString formats a map key for encoding.
+func mapKeyString(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if k.Type().Implements(textMarshalerType) {
+		text, err := k.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %v", k.Type())
+}
+
+// decodeError reports a tree value that cannot be stored in the destination.
+func decodeError(path string, node any, t reflect.Type) error {
+	if path == "" {
+		path = "value"
+	}
+	return fmt.Errorf("could not decode %s: cannot store %s in %v", path, describeNode(node), t)
+}
+
+func describeNode(node any) string {
+	switch n := node.(type) {
+	case map[string]any:
+		return "mapping"
+	case []any:
+		return "sequence"
+	case plainScalar:
+		return strconv.Quote(string(n))
+	case string:
+		return "string " + strconv.Quote(n)
+	case localTime:
+		return n.text
+	}
+	return fmt.Sprintf("%T %v", node, node)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// resolvePlain applies the YAML 1.2 core schema to an unquoted scalar.
+func resolvePlain(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	if i, ok := parseYAMLInt(s); ok {
+		return i
+	}
+	if isYAMLFloat(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+func parseYAMLInt(s string) (int64, bool) {
+	switch {
+	case strings.HasPrefix(s, "0x"):
+		i, err := strconv.ParseInt(s[2:], 16, 64)
+		return i, err == nil && s[2:] != "" && !strings.ContainsAny(s[2:], "+-_")
+	case strings.HasPrefix(s, "0o"):
+		i, err := strconv.ParseInt(s[2:], 8, 64)
+		return i, err == nil && s[2:] != "" && !strings.ContainsAny(s[2:], "+-_")
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 || digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return 0, false
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	return i, err == nil
+}
+
+func isYAMLFloat(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	mant, exp, hasExp := strings.Cut(strings.ToLower(s), "e")
+	if hasExp {
+		exp = strings.TrimLeft(exp, "+-")
+		if exp == "" || strings.Trim(exp, "0123456789") != "" {
+			return false
+		}
+	}
+	whole, frac, _ := strings.Cut(mant, ".")
+	return (whole != "" || frac != "") &&
+		strings.Trim(whole, "0123456789") == "" &&
+		strings.Trim(frac, "0123456789") == ""
+}
+
+// plainValue converts a decoded tree into plain Go values for storing in an
+// interface: mappings become map[string]any, YAML scalars are resolved and
+// TOML local dates and times are kept as text.
+func plainValue(node any) any {
+	switch n := node.(type) {
+	case plainScalar:
+		return resolvePlain(string(n))
+	case localTime:
+		return n.text
+	case []any:
+		out := make([]any, len(n))
+		for i, item := range n {
+			out[i] = plainValue(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(n))
+		for k, item := range n {
+			out[k] = plainValue(item)
+		}
+		return out
+	}
+	return node
+}
+
+// fromTree stores a decoded tree in v. Like encoding/json, a null only
+// resets pointers, maps, slices and interfaces, and unknown mapping keys
+// are ignored. Struct fields match keys by tag name, then case-insensitively
+// by name.
+func fromTree(node any, v reflect.Value, tagKey, path string) error {
+	if p, ok := node.(plainScalar); ok && resolvePlain(string(p)) == nil {
+		node = nil
+	}
+	if node == nil {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			v.SetZero()
+		}
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return fromTree(node, v.Elem(), tagKey, path)
+	}
+
+	// Scalars as text, for special types that read it
+	var text string
+	isText := false
+	switch n := node.(type) {
+	case plainScalar:
+		text, isText = string(n), true
+	case string:
+		text, isText = n, true
+	case localTime:
+		text, isText = n.text, true
+	}
+
+	switch {
+	case v.Type() == timeType:
+		switch n := node.(type) {
+		case time.Time:
+			v.Set(reflect.ValueOf(n))
+			return nil
+		case localTime:
+			v.Set(reflect.ValueOf(n.t))
+			return nil
+		}
+		if isText {
+			t, err := parseTimeText(text)
+			if err != nil {
+				return fmt.Errorf("could not decode %s: %v", path, err)
+			}
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return decodeError(path, node, v.Type())
+	case v.Type() == durationType && isText:
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("could not decode %s: %v", path, err)
+		}
+		v.SetInt(int64(d))
+		return nil
+	case isText && v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType):
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("could not decode %s: %v", path, err)
+		}
+		return nil
+	}
+
+	if p, ok := node.(plainScalar); ok && v.Kind() != reflect.String {
+		node = resolvePlain(string(p))
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return decodeError(path, node, v.Type())
+		}
+		v.Set(reflect.ValueOf(plainValue(node)))
+		return nil
+	case reflect.Bool:
+		b, ok := node.(bool)
+		if !ok {
+			return decodeError(path, node, v.Type())
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch n := node.(type) {
+		case int64:
+			i = n
+		case float64:
+			if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+				return decodeError(path, node, v.Type())
+			}
+			i = int64(n)
+		default:
+			return decodeError(path, node, v.Type())
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("could not decode %s: %d overflows %v", path, i, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch n := node.(type) {
+		case int64:
+			if n < 0 {
+				return fmt.Errorf("could not decode %s: %d overflows %v", path, n, v.Type())
+			}
+			u = uint64(n)
+		case uint64:
+			u = n
+		case float64:
+			if n != math.Trunc(n) || n < 0 || n >= math.MaxUint64 {
+				return decodeError(path, node, v.Type())
+			}
+			u = uint64(n)
+		default:
+			return decodeError(path, node, v.Type())
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("could not decode %s: %d overflows %v", path, u, v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch n := node.(type) {
+		case float64:
+			f = n
+		case int64:
+			f = float64(n)
+		case uint64:
+			f = float64(n)
+		default:
+			return decodeError(path, node, v.Type())
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.String:
+		if !isText {
+			return decodeError(path, node, v.Type())
+		}
+		v.SetString(text)
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 && isText {
+			b, err := base64.StdEncoding.DecodeString(text)
+			if err != nil {
+				return fmt.Errorf("could not decode %s: %v", path, err)
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		list, ok := node.([]any)
+		if !ok {
+			return decodeError(path, node, v.Type())
+		}
+		s := reflect.MakeSlice(v.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := fromTree(item, s.Index(i), tagKey, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Array:
+		list, ok := node.([]any)
+		if !ok || len(list) > v.Len() {
+			return decodeError(path, node, v.Type())
+		}
+		v.SetZero()
+		for i, item := range list {
+			if err := fromTree(item, v.Index(i), tagKey, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := node.(map[string]any)
+		if !ok {
+			return decodeError(path, node, v.Type())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(m)))
+		}
+		for k, item := range m {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := setMapKey(key, k); err != nil {
+				return fmt.Errorf("could not decode %s: %v", joinPath(path, k), err)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if existing := v.MapIndex(key); existing.IsValid() {
+				elem.Set(existing)
+			}
+			if err := fromTree(item, elem, tagKey, joinPath(path, k)); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+		return nil
+	case reflect.Struct:
+		m, ok := node.(map[string]any)
+		if !ok {
+			return decodeError(path, node, v.Type())
+		}
+		fields := structFields(v.Type(), tagKey)
+		for k, item := range m {
+			f := matchField(fields, k)
+			if f == nil {
+				continue
+			}
+			if err := fromTree(item, fieldByIndex(v, f.index, true), tagKey, joinPath(path, k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return decodeError(path, node, v.Type())
+}
+
+// matchField finds the field for key, preferring an exact match.
+func matchField(fields []fieldInfo, key string) *fieldInfo {
+	for i := range fields {
+		if fields[i].name == key {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, key) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// setMapKey parses a mapping key into the map's key type.
+func setMapKey(key reflect.Value, s string) error {
+	if key.Kind() == reflect.String {
+		key.SetString(s)
+		return nil
+	}
+	if key.Addr().Type().Implements(textUnmarshalerType) {
+		return key.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, key.Type().Bits())
+		if err != nil {
+			return err
+		}
+		key.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 10, key.Type().Bits())
+		if err != nil {
+			return err
+		}
+		key.SetUint(u)
+		return nil
+	}
+	return fmt.Errorf("unsupported map key type %v", key.Type())
+}
+
+// parseTimeText parses the timestamp forms used by YAML and TOML.
+func parseTimeText(s string) (time.Time, error) {
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04:05.999999999",
+		time.DateOnly,
+		"15:04:05.999999999",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// unmarshalTree stores a decoded document in v, which must be a non-nil pointer.
+func unmarshalTree(node any, v any, tagKey string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("could not decode into %T: need a non-nil pointer", v)
+	}
+	return fromTree(node, rv.Elem(), tagKey, "")
+}
@@ -0,0 +1,130 @@
+package encodingExt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// EncodeDataURI returns an RFC 2397 data URI holding data as base64, such
+// as "data:image/png;base64,iVBOR...", ready for an img src attribute. If
+// mime is empty it is detected with DetectContentType.
+func EncodeDataURI(mime string, data []byte) string {
+	if mime == "" {
+		mime = DetectContentType(data)
+	}
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// DecodeDataURI parses an RFC 2397 data URI and returns its media type,
+// including any parameters, and its decoded content. The media type
+// defaults to "text/plain;charset=US-ASCII".
+func DecodeDataURI(uri string) (string, []byte, error) {
+	rest, ok := cutPrefixFold(strings.TrimSpace(uri), "data:")
+	if !ok {
+		return "", nil, fmt.Errorf("could not decode data URI: missing data: scheme")
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, fmt.Errorf("could not decode data URI: missing comma")
+	}
+
+	mime, isBase64 := header, false
+	if before, ok := cutSuffixFold(header, ";base64"); ok {
+		mime, isBase64 = before, true
+	}
+	if mime == "" || strings.HasPrefix(mime, ";") {
+		mime = "text/plain" + mime
+		if !strings.Contains(mime, "charset=") {
+			mime += ";charset=US-ASCII"
+		}
+	}
+
+	if !isBase64 {
+		data, err := url.PathUnescape(payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("could not decode data URI: %v", err)
+		}
+		return mime, []byte(data), nil
+	}
+
+	// Payloads may be percent-encoded, wrapped or unpadded
+	if strings.Contains(payload, "%") {
+		unescaped, err := url.PathUnescape(payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("could not decode data URI: %v", err)
+		}
+		payload = unescaped
+	}
+	payload = strings.TrimRight(RemoveWhitespace(payload), "=")
+	data, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil {
+		data, err = base64.RawURLEncoding.DecodeString(payload)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("could not decode data URI: %v", err)
+	}
+	return mime, data, nil
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
+
+func cutSuffixFold(s, suffix string) (string, bool) {
+	if len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix) {
+		return s[:len(s)-len(suffix)], true
+	}
+	return s, false
+}
+
+// DetectContentType sniffs the MIME type of data using the WHATWG algorithm
+// of net/http, and additionally recognises SVG images, which it reports as
+// "image/svg+xml". It never returns an empty string, falling back to
+// "application/octet-stream".
+func DetectContentType(data []byte) string {
+	mime := http.DetectContentType(data)
+	if strings.HasPrefix(mime, "text/xml") || strings.HasPrefix(mime, "text/plain") || strings.HasPrefix(mime, "text/html") {
+		if isSVG(data) {
+			return "image/svg+xml"
+		}
+	}
+	return mime
+}
+
+// isSVG reports whether data starts, after any XML prolog, comments and
+// doctype, with an <svg> element.
+func isSVG(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	for {
+		data = bytes.TrimLeft(data, " \t\r\n")
+		switch {
+		case bytes.HasPrefix(data, []byte("<?")):
+			end := bytes.Index(data, []byte("?>"))
+			if end < 0 {
+				return false
+			}
+			data = data[end+2:]
+		case bytes.HasPrefix(data, []byte("<!--")):
+			end := bytes.Index(data, []byte("-->"))
+			if end < 0 {
+				return false
+			}
+			data = data[end+3:]
+		case bytes.HasPrefix(data, []byte("<!")):
+			end := bytes.IndexByte(data, '>')
+			if end < 0 {
+				return false
+			}
+			data = data[end+1:]
+		default:
+			return bytes.HasPrefix(data, []byte("<svg")) && len(data) > 4 && bytes.IndexByte([]byte(" \t\r\n>/"), data[4]) >= 0
+		}
+	}
+}
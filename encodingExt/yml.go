@@ -0,0 +1,1119 @@
+package encodingExt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// YAMLMarshal encodes v as a block-style YAML document. Struct fields use
+// their `yaml:"name,omitempty"` tags, maps are written with sorted keys and
+// []byte values as base64 strings.
+func YAMLMarshal(v interface{}) ([]byte, error) {
+	node, err := toTree(reflect.ValueOf(v), "yaml")
+	if err != nil {
+		return nil, fmt.Errorf("could not encode YAML: %v", err)
+	}
+	var sb strings.Builder
+	writeYAML(&sb, node, 0)
+	return []byte(sb.String()), nil
+}
+
+// YAMLUnmarshal decodes the first document in data into v. It supports the
+// block and flow styles, quoted and block scalars, anchors, aliases and
+// "<<" merge keys, which covers typical configuration files; tags other
+// than !!str and !!binary are ignored. Plain scalars follow the YAML 1.2
+// core schema, so "yes" and "no" are strings.
+func YAMLUnmarshal(data []byte, v interface{}) error {
+	node, err := parseYAML(string(data))
+	if err != nil {
+		return err
+	}
+	return unmarshalTree(node, v, "yaml")
+}
+
+// writeYAML writes node as a block at the given indentation.
+func writeYAML(sb *strings.Builder, node any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch n := node.(type) {
+	case *mapNode:
+		if len(n.keys) == 0 {
+			sb.WriteString(pad + "{}\n")
+			return
+		}
+		for _, key := range n.keys {
+			sb.WriteString(pad + yamlScalar(key, indent) + ":")
+			writeYAMLValue(sb, n.values[key], indent)
+		}
+	case []any:
+		if len(n) == 0 {
+			sb.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range n {
+			// Write the item two columns in, then put the dash in its first indent
+			var ib strings.Builder
+			writeYAML(&ib, item, indent+2)
+			sb.WriteString(pad + "- " + ib.String()[indent+2:])
+		}
+	default:
+		// Block scalars indent relative to the owning entry, not the value column
+		sb.WriteString(pad + yamlScalar(n, max(indent-2, 0)) + "\n")
+	}
+}
+
+// writeYAMLValue writes the value of a mapping entry after its key.
+func writeYAMLValue(sb *strings.Builder, node any, indent int) {
+	switch n := node.(type) {
+	case *mapNode:
+		if len(n.keys) == 0 {
+			sb.WriteString(" {}\n")
+			return
+		}
+		sb.WriteString("\n")
+		writeYAML(sb, n, indent+2)
+	case []any:
+		if len(n) == 0 {
+			sb.WriteString(" []\n")
+			return
+		}
+		sb.WriteString("\n")
+		writeYAML(sb, n, indent+2)
+	default:
+		sb.WriteString(" " + yamlScalar(n, indent) + "\n")
+	}
+}
+
+// yamlScalar formats a scalar so that it reads back as the same value.
+func yamlScalar(node any, indent int) string {
+	switch n := node.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(n)
+	case int64:
+		return strconv.FormatInt(n, 10)
+	case uint64:
+		return strconv.FormatUint(n, 10)
+	case float64:
+		switch {
+		case math.IsInf(n, 1):
+			return ".inf"
+		case math.IsInf(n, -1):
+			return "-.inf"
+		case math.IsNaN(n):
+			return ".nan"
+		}
+		s := strconv.FormatFloat(n, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s
+	case time.Time:
+		return n.Format(time.RFC3339Nano)
+	case localTime:
+		return n.text
+	case string:
+		return yamlString(n, indent)
+	}
+	return fmt.Sprint(node)
+}
+
+// yamlString quotes s where a plain scalar would be misread, using a literal
+// block for multi-line text.
+func yamlString(s string, indent int) string {
+	if s == "" {
+		return `""`
+	}
+	if strings.Contains(s, "\n") && yamlLiteralSafe(s) {
+		chomp := "-"
+		body := s
+		if strings.HasSuffix(s, "\n") {
+			chomp = ""
+			body = s[:len(s)-1]
+			if strings.HasSuffix(body, "\n") {
+				chomp = "+"
+			}
+		}
+		pad := strings.Repeat(" ", indent+2)
+		var sb strings.Builder
+		sb.WriteString("|" + chomp)
+		for _, line := range strings.Split(body, "\n") {
+			sb.WriteString("\n")
+			if line != "" {
+				sb.WriteString(pad + line)
+			}
+		}
+		return sb.String()
+	}
+	if yamlNeedsQuotes(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// yamlLiteralSafe reports whether s can be written as a literal block.
+func yamlLiteralSafe(s string) bool {
+	if s[0] == ' ' || s[0] == '\n' || !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if r != '\n' && (r < ' ' || r == 0x7f || r == 0xfeff) {
+			return false
+		}
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasSuffix(line, " ") {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlNeedsQuotes reports whether s must be quoted to stay a string. YAML
+// 1.1 booleans such as "yes" are quoted too, for older parsers.
+func yamlNeedsQuotes(s string) bool {
+	if _, ok := resolvePlain(s).(string); !ok {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off":
+		return true
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(s[0])) && !(len(s) > 1 && (s[0] == '-' || s[0] == '?' || s[0] == ':') && s[1] != ' ') {
+		return true
+	}
+	if s[0] == ' ' || s[len(s)-1] == ' ' || s[len(s)-1] == ':' || s == "-" || s == "---" || s == "..." {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f || r == 0xfeff {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlParser parses YAML line by line, recursing on indentation.
+type yamlParser struct {
+	lines   []string
+	pos     int
+	line    int // index of the line being parsed, for errors
+	anchors map[string]any
+}
+
+// errorf reports a syntax error at a 1-based line number.
+func (p *yamlParser) errorf(format string, args ...any) error {
+	line := min(p.line+1, len(p.lines))
+	return fmt.Errorf("could not decode YAML: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func parseYAML(src string) (any, error) {
+	src = strings.TrimPrefix(src, "\ufeff")
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	p := &yamlParser{lines: strings.Split(src, "\n"), anchors: make(map[string]any)}
+
+	// Skip directives and the document start marker
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.lines[p.pos]
+		if strings.HasPrefix(line, "%") {
+			p.pos++
+			continue
+		}
+		if line == "---" || strings.HasPrefix(line, "--- ") {
+			rest := strings.TrimSpace(line[3:])
+			if rest == "" || rest[0] == '#' {
+				p.pos++
+			} else {
+				p.lines[p.pos] = "    " + rest
+			}
+		}
+		break
+	}
+
+	node, err := p.parseBlock(0, -1)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) && !p.atDocumentEnd() {
+		p.line = p.pos
+		return nil, p.errorf("unexpected content %q", strings.TrimSpace(p.lines[p.pos]))
+	}
+	return node, nil
+}
+
+// atDocumentEnd reports whether the current line ends the document.
+func (p *yamlParser) atDocumentEnd() bool {
+	line := p.lines[p.pos]
+	return line == "---" || strings.HasPrefix(line, "--- ") || line == "..." || strings.HasPrefix(line, "... ")
+}
+
+// skipBlank moves past empty and comment-only lines.
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) {
+		t := strings.TrimSpace(p.lines[p.pos])
+		if t != "" && t[0] != '#' {
+			return
+		}
+		p.pos++
+	}
+}
+
+// next returns the indentation and content of the next significant line,
+// which becomes the line reported in errors.
+func (p *yamlParser) next() (indent int, content string, ok bool, err error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) || p.atDocumentEnd() {
+		return 0, "", false, nil
+	}
+	p.line = p.pos
+	line := p.lines[p.pos]
+	indent = len(line) - len(strings.TrimLeft(line, " "))
+	if line[indent] == '\t' {
+		return 0, "", false, p.errorf("tabs are not allowed for indentation")
+	}
+	return indent, stripYAMLComment(line[indent:]), true, nil
+}
+
+// isSeqEntry reports whether content starts a block sequence entry.
+func isSeqEntry(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ") || strings.HasPrefix(content, "-\t")
+}
+
+// parseBlock parses the node starting at the next line, which must be
+// indented more than parent.
+func (p *yamlParser) parseBlock(minIndent, parent int) (any, error) {
+	indent, content, ok, err := p.next()
+	if err != nil || !ok || indent < minIndent || indent <= parent {
+		return nil, err
+	}
+
+	// A node may start with an anchor or tag on its own line
+	props, rest := splitYAMLProps(content)
+	if props != "" && rest == "" {
+		p.pos++
+		node, err := p.parseBlock(parent+1, parent)
+		if err != nil {
+			return nil, err
+		}
+		return p.applyProps(props, node)
+	}
+
+	switch {
+	case isSeqEntry(content):
+		return p.parseSeq(indent)
+	case findMappingColon(content) >= 0:
+		return p.parseMap(indent)
+	}
+	p.pos++
+	return p.parseValue(content, indent, parent)
+}
+
+// parseSeq parses a block sequence whose dashes are at indent.
+func (p *yamlParser) parseSeq(indent int) ([]any, error) {
+	list := []any{}
+	for {
+		ind, content, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || ind != indent || !isSeqEntry(content) {
+			return list, nil
+		}
+		rest := strings.TrimLeft(content[1:], " \t")
+		col := indent + len(content) - len(rest)
+
+		var item any
+		props, value := splitYAMLProps(rest)
+		switch {
+		case rest == "":
+			p.pos++
+			item, err = p.parseBlock(indent+1, indent)
+		case value != "" && (isSeqEntry(value) || findMappingColon(value) >= 0):
+			// Re-read the rest of the line as a nested block at its own column
+			p.lines[p.pos] = strings.Repeat(" ", col+len(rest)-len(value)) + value
+			item, err = p.parseBlock(col, indent)
+			if err == nil && props != "" {
+				item, err = p.applyProps(props, item)
+			}
+		default:
+			p.pos++
+			item, err = p.parseValue(rest, indent, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+}
+
+// parseMap parses a block mapping whose keys are at indent.
+func (p *yamlParser) parseMap(indent int) (map[string]any, error) {
+	m := make(map[string]any)
+	var merges []any
+	for {
+		ind, content, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || ind != indent {
+			break
+		}
+		line := p.line
+		colon := findMappingColon(content)
+		if colon < 0 {
+			if isSeqEntry(content) {
+				break
+			}
+			return nil, p.errorf("expected a mapping key, found %q", content)
+		}
+		key, err := p.parseKey(content[:colon])
+		if err != nil {
+			return nil, err
+		}
+		rest := strings.TrimLeft(content[colon+1:], " \t")
+		p.pos++
+
+		var value any
+		props, after := splitYAMLProps(rest)
+		if after == "" {
+			// The value is on the following lines; a sequence may sit at the key's indentation
+			var next string
+			ind, next, ok, err = p.next()
+			switch {
+			case err != nil:
+			case ok && ind == indent && isSeqEntry(next):
+				value, err = p.parseSeq(indent)
+			default:
+				value, err = p.parseBlock(indent+1, indent)
+			}
+			if err == nil && props != "" {
+				value, err = p.applyProps(props, value)
+			}
+		} else {
+			value, err = p.parseValue(rest, indent, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if key == "<<" {
+			merges = append(merges, value)
+			continue
+		}
+		if _, dup := m[key]; dup {
+			p.line = line
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		m[key] = value
+	}
+
+	// Merged mappings fill in keys the mapping does not set itself
+	for _, merge := range merges {
+		sources, ok := merge.([]any)
+		if !ok {
+			sources = []any{merge}
+		}
+		for _, src := range sources {
+			sm, ok := src.(map[string]any)
+			if !ok {
+				return nil, p.errorf("merge key value must be a mapping")
+			}
+			for k, v := range sm {
+				if _, set := m[k]; !set {
+					m[k] = v
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// parseKey decodes a mapping key.
+func (p *yamlParser) parseKey(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	switch s[0] {
+	case '"', '\'':
+		node, rest, err := parseYAMLQuoted(s)
+		if err != nil {
+			return "", p.errorf("%v", err)
+		}
+		if strings.TrimSpace(rest) != "" {
+			return "", p.errorf("invalid key %q", s)
+		}
+		return node, nil
+	case '[', '{', '?', '&', '*', '!':
+		return "", p.errorf("unsupported complex key %q", s)
+	}
+	return s, nil
+}
+
+// parseValue parses an inline value that started on the previous line,
+// reading continuation lines indented more than parent.
+func (p *yamlParser) parseValue(content string, indent, parent int) (any, error) {
+	props, rest := splitYAMLProps(content)
+	var node any
+	var err error
+	switch {
+	case rest == "":
+		node = nil
+	case rest[0] == '*':
+		name := rest[1:]
+		v, ok := p.anchors[name]
+		if !ok {
+			return nil, p.errorf("unknown alias %q", name)
+		}
+		node = v
+	case rest[0] == '|' || rest[0] == '>':
+		node, err = p.parseBlockScalar(rest, parent)
+	case rest[0] == '[' || rest[0] == '{':
+		text := rest
+		for !flowBalanced(text) && p.pos < len(p.lines) {
+			text += " " + stripYAMLComment(strings.TrimSpace(p.lines[p.pos]))
+			p.pos++
+		}
+		fp := &yamlFlowParser{s: text, anchors: p.anchors}
+		node, err = fp.parse()
+		if err == nil {
+			fp.skipSpace()
+			if fp.i < len(fp.s) {
+				err = fmt.Errorf("unexpected %q after flow collection", fp.s[fp.i:])
+			}
+		}
+		if err != nil {
+			err = p.errorf("%v", err)
+		}
+	case rest[0] == '"' || rest[0] == '\'':
+		text := rest
+		for {
+			s, tail, qerr := parseYAMLQuoted(text)
+			if qerr == nil {
+				if t := strings.TrimSpace(tail); t != "" && t[0] != '#' {
+					return nil, p.errorf("unexpected %q after quoted scalar", t)
+				}
+				node = s
+				break
+			}
+			if p.pos >= len(p.lines) {
+				return nil, p.errorf("%v", qerr)
+			}
+			text += "\n" + p.lines[p.pos]
+			p.pos++
+		}
+	default:
+		if findMappingColon(rest) >= 0 {
+			return nil, p.errorf("mapping values are not allowed in %q", rest)
+		}
+		// A plain scalar may continue on more indented lines
+		parts := []string{strings.TrimSpace(rest)}
+		for p.pos < len(p.lines) {
+			line := p.lines[p.pos]
+			t := strings.TrimSpace(line)
+			ind := len(line) - len(strings.TrimLeft(line, " "))
+			if t == "" {
+				if ind2, _, ok := p.peekAfterBlank(); ok && ind2 > parent {
+					parts = append(parts, "")
+					p.pos++
+					continue
+				}
+				break
+			}
+			if ind <= parent || t[0] == '#' || findMappingColon(t) >= 0 || isSeqEntry(t) {
+				break
+			}
+			parts = append(parts, stripYAMLComment(t))
+			p.pos++
+		}
+		node = plainScalar(foldLines(parts))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.applyProps(props, node)
+}
+
+// peekAfterBlank returns the indentation of the next non-blank line.
+func (p *yamlParser) peekAfterBlank() (int, string, bool) {
+	for i := p.pos; i < len(p.lines); i++ {
+		t := strings.TrimSpace(p.lines[i])
+		if t != "" {
+			return len(p.lines[i]) - len(strings.TrimLeft(p.lines[i], " ")), t, true
+		}
+	}
+	return 0, "", false
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) scalar whose header is
+// header and whose content is indented more than parent.
+func (p *yamlParser) parseBlockScalar(header string, parent int) (any, error) {
+	style := header[0]
+	chomp := byte(0)
+	explicit := 0
+	for _, c := range []byte(stripYAMLComment(header[1:])) {
+		switch {
+		case c == '-' || c == '+':
+			chomp = c
+		case c >= '1' && c <= '9':
+			explicit = int(c - '0')
+		case c == ' ' || c == '\t':
+		default:
+			return nil, p.errorf("invalid block scalar header %q", header)
+		}
+	}
+
+	indent := -1
+	if explicit > 0 {
+		indent = max(parent, 0) + explicit
+	}
+	var lines []string
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		ind := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		if indent < 0 {
+			if ind <= parent {
+				break
+			}
+			indent = ind
+		}
+		if ind < indent {
+			break
+		}
+		lines = append(lines, line[indent:])
+		p.pos++
+	}
+
+	// Trailing blank lines belong to the scalar only for chomping
+	trailing := 0
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var body string
+	if style == '|' {
+		body = strings.Join(lines, "\n")
+	} else {
+		body = foldBlockLines(lines)
+	}
+
+	switch {
+	case len(lines) == 0:
+		if chomp == '+' {
+			return strings.Repeat("\n", trailing), nil
+		}
+		return "", nil
+	case chomp == '-':
+		return body, nil
+	case chomp == '+':
+		return body + "\n" + strings.Repeat("\n", trailing), nil
+	}
+	return body + "\n", nil
+}
+
+// foldBlockLines joins the lines of a folded block scalar. Adjacent text
+// lines are joined with a space, each empty line is a newline and
+// more-indented lines keep their line breaks.
+func foldBlockLines(lines []string) string {
+	var sb strings.Builder
+	last := -1 // index of the previous non-empty line
+	for i, line := range lines {
+		if line == "" {
+			if last >= 0 || i > 0 {
+				sb.WriteString("\n")
+			}
+			continue
+		}
+		if last >= 0 {
+			moreIndented := strings.HasPrefix(line, " ") || strings.HasPrefix(lines[last], " ")
+			switch {
+			case last == i-1 && !moreIndented:
+				sb.WriteString(" ")
+			case last == i-1 || moreIndented:
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString(line)
+		last = i
+	}
+	return sb.String()
+}
+
+// applyProps applies an anchor and tag to a parsed node.
+func (p *yamlParser) applyProps(props string, node any) (any, error) {
+	for _, prop := range strings.Fields(props) {
+		switch {
+		case prop[0] == '&':
+			p.anchors[prop[1:]] = node
+		case prop == "!!str" || prop == "!!binary":
+			switch n := node.(type) {
+			case plainScalar:
+				node = string(n)
+			case nil:
+				node = ""
+			}
+			if prop == "!!binary" {
+				if s, ok := node.(string); ok {
+					node = strings.Join(strings.Fields(s), "")
+				}
+			}
+		}
+	}
+	return node, nil
+}
+
+// splitYAMLProps splits leading anchors and tags from content.
+func splitYAMLProps(content string) (props, rest string) {
+	rest = content
+	for rest != "" && (rest[0] == '&' || rest[0] == '!') {
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		props += " " + rest[:end]
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
+	return strings.TrimSpace(props), rest
+}
+
+// findMappingColon returns the index of the ':' ending a block mapping key
+// in content, or -1 if content is not a key line.
+func findMappingColon(content string) int {
+	if content == "" {
+		return -1
+	}
+	i := 0
+	switch content[0] {
+	case '[', '{', '|', '>', '#':
+		return -1
+	case '"', '\'':
+		_, rest, err := parseYAMLQuoted(content)
+		if err != nil {
+			return -1
+		}
+		i = len(content) - len(rest)
+	case '*':
+		// An alias can be a key, as in "*a : value", but is rare; treat as a value
+		return -1
+	}
+	for ; i < len(content); i++ {
+		if content[i] == ':' && (i+1 == len(content) || content[i+1] == ' ' || content[i+1] == '\t') {
+			return i
+		}
+		if content[i] == '#' && i > 0 && (content[i-1] == ' ' || content[i-1] == '\t') {
+			return -1
+		}
+	}
+	return -1
+}
+
+// stripYAMLComment removes a trailing comment and whitespace from a line,
+// ignoring '#' inside quoted scalars.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					i++
+				} else {
+					quote = 0
+				}
+			}
+		case quote == '"':
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.IndexByte(" \t[{,:-?", s[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#':
+			if i == 0 || s[i-1] == ' ' || s[i-1] == '\t' {
+				return strings.TrimRight(s[:i], " \t")
+			}
+		}
+	}
+	return strings.TrimRight(s, " \t")
+}
+
+// flowBalanced reports whether every bracket in a flow collection is closed.
+func flowBalanced(s string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			}
+		case quote == '"':
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0 && quote == 0
+}
+
+// foldLines joins the lines of a multi-line flow scalar: single line breaks
+// become spaces and each empty line becomes a newline.
+func foldLines(lines []string) string {
+	var sb strings.Builder
+	pendingBreak := false
+	for i, line := range lines {
+		if i > 0 && line == "" {
+			sb.WriteString("\n")
+			pendingBreak = false
+			continue
+		}
+		if pendingBreak {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(line)
+		pendingBreak = true
+	}
+	return sb.String()
+}
+
+// parseYAMLQuoted parses a single- or double-quoted scalar at the start of s,
+// which may span lines, and returns it with the text after the closing quote.
+func parseYAMLQuoted(s string) (string, string, error) {
+	quote := s[0]
+	var raw strings.Builder
+	i := 1
+	for ; i < len(s); i++ {
+		c := s[i]
+		if quote == '\'' && c == '\'' {
+			if i+1 < len(s) && s[i+1] == '\'' {
+				raw.WriteString("''")
+				i++
+				continue
+			}
+			break
+		}
+		if quote == '"' && c == '\\' && i+1 < len(s) {
+			raw.WriteByte(c)
+			raw.WriteByte(s[i+1])
+			i++
+			continue
+		}
+		if quote == '"' && c == '"' {
+			break
+		}
+		raw.WriteByte(c)
+	}
+	if i >= len(s) {
+		return "", "", fmt.Errorf("unterminated quoted scalar")
+	}
+
+	// Fold line breaks, trimming the whitespace around them
+	lines := strings.Split(raw.String(), "\n")
+	if len(lines) > 1 {
+		for j := range lines {
+			if j > 0 {
+				lines[j] = strings.TrimLeft(lines[j], " \t")
+			}
+			if j < len(lines)-1 && !(quote == '"' && strings.HasSuffix(lines[j], "\\") && !strings.HasSuffix(lines[j], "\\\\")) {
+				lines[j] = strings.TrimRight(lines[j], " \t")
+			}
+		}
+	}
+
+	var out string
+	if quote == '\'' {
+		out = strings.ReplaceAll(foldLines(lines), "''", "'")
+	} else {
+		var sb strings.Builder
+		for j, line := range lines {
+			escapedBreak := strings.HasSuffix(line, "\\") && j < len(lines)-1
+			if escapedBreak {
+				line = line[:len(line)-1]
+			}
+			if j > 0 && lines[j-1] != "" && !strings.HasSuffix(lines[j-1], "\\") {
+				if line != "" {
+					sb.WriteString(" ")
+				}
+			}
+			if j > 0 && line == "" {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(line)
+		}
+		unescaped, err := unescapeYAML(sb.String())
+		if err != nil {
+			return "", "", err
+		}
+		out = unescaped
+	}
+	return out, s[i+1:], nil
+}
+
+// unescapeYAML expands the escape sequences of a double-quoted scalar.
+func unescapeYAML(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("invalid escape at end of string")
+		}
+		size := 0
+		switch s[i] {
+		case '0':
+			sb.WriteByte(0)
+		case 'a':
+			sb.WriteByte('\a')
+		case 'b':
+			sb.WriteByte('\b')
+		case 't', '\t':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'v':
+			sb.WriteByte('\v')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'e':
+			sb.WriteByte(0x1b)
+		case ' ', '"', '/', '\\':
+			sb.WriteByte(s[i])
+		case 'N':
+			sb.WriteRune('\u0085')
+		case '_':
+			sb.WriteRune(' ')
+		case 'L':
+			sb.WriteRune(' ')
+		case 'P':
+			sb.WriteRune(' ')
+		case 'x':
+			size = 2
+		case 'u':
+			size = 4
+		case 'U':
+			size = 8
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", s[i])
+		}
+		if size > 0 {
+			if i+size >= len(s) {
+				return "", fmt.Errorf("invalid escape \\%s", s[i:])
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape \\%s", s[i:i+1+size])
+			}
+			sb.WriteRune(rune(code))
+			i += size
+		}
+	}
+	return sb.String(), nil
+}
+
+// yamlFlowParser parses flow collections such as [a, b] and {a: 1}.
+type yamlFlowParser struct {
+	s       string
+	i       int
+	anchors map[string]any
+}
+
+func (fp *yamlFlowParser) skipSpace() {
+	for fp.i < len(fp.s) && (fp.s[fp.i] == ' ' || fp.s[fp.i] == '\t' || fp.s[fp.i] == '\n') {
+		fp.i++
+	}
+}
+
+func (fp *yamlFlowParser) parse() (any, error) {
+	fp.skipSpace()
+	if fp.i >= len(fp.s) {
+		return nil, fmt.Errorf("unexpected end of flow collection")
+	}
+
+	var anchor string
+	for fp.i < len(fp.s) && (fp.s[fp.i] == '&' || fp.s[fp.i] == '!') {
+		start := fp.i
+		for fp.i < len(fp.s) && !strings.ContainsRune(" \t\n,[]{}", rune(fp.s[fp.i])) {
+			fp.i++
+		}
+		if fp.s[start] == '&' {
+			anchor = fp.s[start+1 : fp.i]
+		}
+		fp.skipSpace()
+	}
+	if fp.i >= len(fp.s) {
+		return nil, fmt.Errorf("unexpected end of flow collection")
+	}
+
+	node, err := fp.parseNode()
+	if err == nil && anchor != "" {
+		fp.anchors[anchor] = node
+	}
+	return node, err
+}
+
+func (fp *yamlFlowParser) parseNode() (any, error) {
+	switch c := fp.s[fp.i]; c {
+	case '[':
+		fp.i++
+		list := []any{}
+		for {
+			fp.skipSpace()
+			if fp.i < len(fp.s) && fp.s[fp.i] == ']' {
+				fp.i++
+				return list, nil
+			}
+			item, err := fp.parse()
+			if err != nil {
+				return nil, err
+			}
+			// A single "key: value" pair in a sequence is a one-entry mapping
+			fp.skipSpace()
+			if fp.i < len(fp.s) && fp.s[fp.i] == ':' {
+				fp.i++
+				value, err := fp.parse()
+				if err != nil {
+					return nil, err
+				}
+				item = map[string]any{flowKey(item): value}
+			}
+			list = append(list, item)
+			if err := fp.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		fp.i++
+		m := make(map[string]any)
+		for {
+			fp.skipSpace()
+			if fp.i < len(fp.s) && fp.s[fp.i] == '}' {
+				fp.i++
+				return m, nil
+			}
+			key, err := fp.parse()
+			if err != nil {
+				return nil, err
+			}
+			fp.skipSpace()
+			var value any
+			if fp.i < len(fp.s) && fp.s[fp.i] == ':' {
+				fp.i++
+				fp.skipSpace()
+				if fp.i < len(fp.s) && fp.s[fp.i] != ',' && fp.s[fp.i] != '}' {
+					if value, err = fp.parse(); err != nil {
+						return nil, err
+					}
+				}
+			}
+			k := flowKey(key)
+			if _, dup := m[k]; dup {
+				return nil, fmt.Errorf("duplicate key %q", k)
+			}
+			m[k] = value
+			if err := fp.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '"', '\'':
+		s, rest, err := parseYAMLQuoted(fp.s[fp.i:])
+		if err != nil {
+			return nil, err
+		}
+		fp.i = len(fp.s) - len(rest)
+		return s, nil
+	case '*':
+		start := fp.i + 1
+		for fp.i < len(fp.s) && !strings.ContainsRune(" \t\n,[]{}", rune(fp.s[fp.i])) {
+			fp.i++
+		}
+		v, ok := fp.anchors[fp.s[start:fp.i]]
+		if !ok {
+			return nil, fmt.Errorf("unknown alias %q", fp.s[start:fp.i])
+		}
+		return v, nil
+	case ']', '}', ',':
+		return nil, fmt.Errorf("unexpected %q in flow collection", c)
+	}
+
+	// Plain scalars end at flow indicators or a ": " separator
+	start := fp.i
+	for fp.i < len(fp.s) {
+		c := fp.s[fp.i]
+		if c == ',' || c == ']' || c == '}' || c == '[' || c == '{' {
+			break
+		}
+		if c == ':' && (fp.i+1 == len(fp.s) || strings.IndexByte(" \t\n,]}", fp.s[fp.i+1]) >= 0) {
+			break
+		}
+		if c == '#' && fp.i > start && (fp.s[fp.i-1] == ' ' || fp.s[fp.i-1] == '\t') {
+			break
+		}
+		fp.i++
+	}
+	return plainScalar(strings.Join(strings.Fields(fp.s[start:fp.i]), " ")), nil
+}
+
+// separator consumes the ',' between entries or stops before the closing bracket.
+func (fp *yamlFlowParser) separator(end byte) error {
+	fp.skipSpace()
+	if fp.i >= len(fp.s) {
+		return fmt.Errorf("unterminated flow collection")
+	}
+	switch fp.s[fp.i] {
+	case ',':
+		fp.i++
+		return nil
+	case end:
+		return nil
+	}
+	return fmt.Errorf("expected ',' or %q, found %q", end, fp.s[fp.i:])
+}
+
+// flowKey converts a parsed flow node to a mapping key.
+func flowKey(node any) string {
+	switch n := node.(type) {
+	case plainScalar:
+		return string(n)
+	case string:
+		return n
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(node)
+}
@@ -0,0 +1,113 @@
+package encodingExt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYAMLUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want any
+	}{
+		{"scalars", "a: 1\nb: 2.5\nc: true\nd: null\ne: yes\n",
+			map[string]any{"a": int64(1), "b": 2.5, "c": true, "d": nil, "e": "yes"}},
+		{"flow", "a: [1, 2, {c: d}]\n",
+			map[string]any{"a": []any{int64(1), int64(2), map[string]any{"c": "d"}}}},
+		{"anchor", "a: &x 1\nb: *x\n", map[string]any{"a": int64(1), "b": int64(1)}},
+		{"flow anchor", "a: [&x 1, *x]\n", map[string]any{"a": []any{int64(1), int64(1)}}},
+		{"merge", "base: &b {x: 1}\nc:\n  <<: *b\n  y: 2\n",
+			map[string]any{"base": map[string]any{"x": int64(1)}, "c": map[string]any{"x": int64(1), "y": int64(2)}}},
+		{"literal", "a: |\n  x\n  y\n", map[string]any{"a": "x\ny\n"}},
+		{"folded", "a: >\n  x\n  y\n", map[string]any{"a": "x y\n"}},
+		{"colon in value", "url: http://host:80/\n", map[string]any{"url": "http://host:80/"}},
+		{"comment", "a: b # c: d\n", map[string]any{"a": "b"}},
+		{"quoted colon", "a: \"b: c\"\n", map[string]any{"a": "b: c"}},
+		{"sequence of maps", "- a: b\n  c: d\n- e\n", []any{map[string]any{"a": "b", "c": "d"}, "e"}},
+		{"sequence at key indent", "a:\n- 1\n- 2\n", map[string]any{"a": []any{int64(1), int64(2)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got any
+			if err := YAMLUnmarshal([]byte(tt.in), &got); err != nil {
+				t.Fatalf("YAMLUnmarshal(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("YAMLUnmarshal(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		line int
+		want string
+	}{
+		{"a: {&\n", 1, "unexpected end of flow collection"},
+		{"a: [&\n", 1, "unexpected end of flow collection"},
+		{"a: [!\n", 1, "unexpected end of flow collection"},
+		{"[&x\n", 1, "unexpected end of flow collection"},
+		{"key: value: bad\n", 1, "mapping values are not allowed"},
+		{"- value: bad: x\n", 1, "mapping values are not allowed"},
+		{"a: [1, 2\n", 1, "unterminated flow collection"},
+		{"a: {b: 1} x\n", 1, `unexpected "x" after flow collection`},
+		{"a: 'x\n", 1, "unterminated"},
+		{"a: \"x\" y\n", 1, `unexpected "y" after quoted scalar`},
+		{"a: 1\na: 2\n", 2, "duplicate key"},
+		{"a:\n  b: 1\na: 2\n", 3, "duplicate key"},
+		{"a: 1\nb: *nope\n", 2, "unknown alias"},
+		{"\ta: 1\n", 1, "tabs are not allowed"},
+		{"x:\n\ty: 2\n", 2, "tabs are not allowed"},
+		{"x: 1\n\ty: 2\n", 2, "tabs are not allowed"},
+		{"- 1\n\t- 2\n", 2, "tabs are not allowed"},
+		{"a:\n  <<: 1\n", 2, "merge key value must be a mapping"},
+		{"a: 1\n b: 2\n", 2, "unexpected content"},
+	}
+	for _, tt := range tests {
+		var got any
+		err := YAMLUnmarshal([]byte(tt.in), &got)
+		want := fmt.Sprintf("line %d: %s", tt.line, tt.want)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("YAMLUnmarshal(%q) error = %v, want %q", tt.in, err, want)
+		}
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	type inner struct {
+		Name string   `yaml:"name"`
+		Tags []string `yaml:"tags,omitempty"`
+	}
+	type config struct {
+		Port    int               `yaml:"port"`
+		Debug   bool              `yaml:"debug"`
+		Ratio   float64           `yaml:"ratio"`
+		Text    string            `yaml:"text"`
+		Servers []inner           `yaml:"servers"`
+		Labels  map[string]string `yaml:"labels"`
+	}
+	in := config{
+		Port:    8080,
+		Debug:   true,
+		Ratio:   0.5,
+		Text:    "line one\nline two: with colon\n",
+		Servers: []inner{{Name: "a", Tags: []string{"x", "y"}}, {Name: "yes"}},
+		Labels:  map[string]string{"env": "prod", "empty": ""},
+	}
+	data, err := YAMLMarshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out config
+	if err := YAMLUnmarshal(data, &out); err != nil {
+		t.Fatalf("YAMLUnmarshal(%q): %v", data, err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip through\n%s\ngot %#v, want %#v", data, out, in)
+	}
+}
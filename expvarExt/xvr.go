@@ -5,9 +5,13 @@ package expvarExt
 import (
 	"encoding/json"
 	"expvar"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/C0d3-5t3w/myT00L5/debugExt"
+	"github.com/C0d3-5t3w/myT00L5/mathExt"
 )
 
 // Bool is a boolean variable that satisfies the expvar.Var interface.
@@ -201,3 +205,80 @@ func PublishFunc(name string, fn func() interface{}) {
 		return fn()
 	}))
 }
+
+// Stats is a streaming summary of observed values (count, mean, standard
+// deviation, min, max and estimated p50/p90/p99) that satisfies the expvar.Var interface.
+type Stats struct {
+	mu        sync.Mutex
+	running   mathExt.RunningStats
+	quantiles map[string]*mathExt.QuantileEstimator
+}
+
+// NewStats creates a new Stats variable.
+func NewStats() *Stats {
+	return &Stats{
+		quantiles: map[string]*mathExt.QuantileEstimator{
+			"p50": mathExt.NewQuantileEstimator(0.50),
+			"p90": mathExt.NewQuantileEstimator(0.90),
+			"p99": mathExt.NewQuantileEstimator(0.99),
+		},
+	}
+}
+
+// Observe records a value. NaN and infinite values are skipped, since they
+// would poison every statistic and cannot be encoded as JSON.
+func (v *Stats) Observe(val float64) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.running.Add(val)
+	for _, q := range v.quantiles {
+		q.Add(val)
+	}
+}
+
+// ObserveDuration records a duration in seconds.
+func (v *Stats) ObserveDuration(d time.Duration) {
+	v.Observe(d.Seconds())
+}
+
+// Snapshot returns the current summary values.
+func (v *Stats) Snapshot() map[string]interface{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	snapshot := map[string]interface{}{
+		"count":  v.running.Count(),
+		"mean":   v.running.Mean(),
+		"stddev": v.running.StdDev(),
+		"min":    v.running.Min(),
+		"max":    v.running.Max(),
+	}
+	for name, q := range v.quantiles {
+		snapshot[name] = q.Value()
+	}
+	return snapshot
+}
+
+// String returns the summary as a JSON object.
+func (v *Stats) String() string {
+	b, _ := json.Marshal(v.Snapshot())
+	return string(b)
+}
+
+// PublishStats publishes a Stats variable with the given name.
+func PublishStats(name string) *Stats {
+	v := NewStats()
+	expvar.Publish(name, v)
+	return v
+}
+
+// PublishBuildInfo publishes the build information as an expvar variable
+// with the given name (commonly "build").
+func PublishBuildInfo(name string) {
+	info := debugExt.BuildInfo()
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return info
+	}))
+}
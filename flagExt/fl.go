@@ -1,13 +1,20 @@
 package flagExt
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/C0d3-5t3w/myT00L5/debugExt"
 )
 
+// ErrVersion is returned by Parse when the version flag was given and the
+// FlagSet does not exit on error.
+var ErrVersion = errors.New("flag: version requested")
+
 // Flag represents an extended flag with additional features
 type Flag struct {
 	Name        string
@@ -25,6 +32,8 @@ type FlagSet struct {
 	flags         map[string]*Flag
 	envPrefix     string
 	errorHandling flag.ErrorHandling
+	version       *bool
+	program       string
 }
 
 // NewFlagSet creates a new FlagSet with the specified name and error handling policy
@@ -135,6 +144,14 @@ func (f *FlagSet) SetEnvPrefix(prefix string) {
 	f.envPrefix = prefix
 }
 
+// VersionFlag defines a -version flag. When it is given, Parse prints the
+// program name with the binary's build information and exits, or returns
+// ErrVersion if the FlagSet does not use flag.ExitOnError.
+func (f *FlagSet) VersionFlag(program string) {
+	f.program = program
+	f.version = f.FlagSet.Bool("version", false, "print version information and exit")
+}
+
 // Parse parses the command line arguments and validates required flags
 func (f *FlagSet) Parse(arguments []string) error {
 	err := f.FlagSet.Parse(arguments)
@@ -142,6 +159,14 @@ func (f *FlagSet) Parse(arguments []string) error {
 		return err
 	}
 
+	if f.version != nil && *f.version {
+		fmt.Fprintln(f.Output(), debugExt.VersionString(f.program))
+		if f.errorHandling == flag.ExitOnError {
+			os.Exit(0)
+		}
+		return ErrVersion
+	}
+
 	// Track which flags have been set
 	setFlags := make(map[string]bool)
 	f.FlagSet.Visit(func(f *flag.Flag) {
@@ -5,4 +5,7 @@ go 1.24.1
 require (
 	golang.org/x/crypto v0.37.0
 	golang.org/x/net v0.39.0
+	golang.org/x/text v0.24.0
 )
+
+require golang.org/x/sys v0.32.0 // indirect
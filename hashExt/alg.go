@@ -0,0 +1,185 @@
+package hashExt
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// StringToSHA3_256 returns SHA3-256 hash of the input string
+func StringToSHA3_256(text string) string {
+	sum := sha3.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// StringToSHA3_512 returns SHA3-512 hash of the input string
+func StringToSHA3_512(text string) string {
+	sum := sha3.Sum512([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// StringToBLAKE2b returns BLAKE2b-512 hash of the input string
+func StringToBLAKE2b(text string) string {
+	sum := blake2b.Sum512([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// StringToBLAKE2b256 returns BLAKE2b-256 hash of the input string
+func StringToBLAKE2b256(text string) string {
+	sum := blake2b.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// FileToSHA3_256 returns SHA3-256 hash of a file
+func FileToSHA3_256(filepath string) (string, error) {
+	return FileToHash(filepath, sha3.New256())
+}
+
+// FileToSHA3_512 returns SHA3-512 hash of a file
+func FileToSHA3_512(filepath string) (string, error) {
+	return FileToHash(filepath, sha3.New512())
+}
+
+// FileToBLAKE2b returns BLAKE2b-512 hash of a file
+func FileToBLAKE2b(filepath string) (string, error) {
+	return FileToHash(filepath, mustBLAKE2b(blake2b.New512))
+}
+
+// FileToBLAKE2b256 returns BLAKE2b-256 hash of a file
+func FileToBLAKE2b256(filepath string) (string, error) {
+	return FileToHash(filepath, mustBLAKE2b(blake2b.New256))
+}
+
+// mustBLAKE2b creates an unkeyed BLAKE2b hasher, which cannot fail
+func mustBLAKE2b(newFunc func(key []byte) (hash.Hash, error)) hash.Hash {
+	h, err := newFunc(nil)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]func() hash.Hash{
+		"md5":         md5.New,
+		"sha1":        sha1.New,
+		"sha256":      sha256.New,
+		"sha512":      sha512.New,
+		"sha3-256":    func() hash.Hash { return sha3.New256() },
+		"sha3-512":    func() hash.Hash { return sha3.New512() },
+		"blake2b":     func() hash.Hash { return mustBLAKE2b(blake2b.New512) },
+		"blake2b-256": func() hash.Hash { return mustBLAKE2b(blake2b.New256) },
+		"blake2b-512": func() hash.Hash { return mustBLAKE2b(blake2b.New512) },
+		"crc32":       func() hash.Hash { return crc32.NewIEEE() },
+		"crc64":       func() hash.Hash { return crc64.New(crc64Table) },
+		"fnv1a":       func() hash.Hash { return fnv.New64a() },
+		"xxhash64":    func() hash.Hash { return NewXXHash64(0) },
+	}
+)
+
+// normalizeName lower-cases an algorithm name and drops separators, so
+// "SHA-256", "sha_256" and "sha256" are the same algorithm. The dash before
+// a SHA-3 or BLAKE2b digest size is kept to tell "sha3-256" from "sha256".
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.ReplaceAll(name, "_", "-")
+	for _, prefix := range []string{"sha3", "blake2b"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			return prefix + rest
+		}
+	}
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// HashByName returns a new hasher for an algorithm name such as "sha256",
+// "SHA3-256" or "blake2b", so algorithms can be chosen from config strings.
+func HashByName(name string) (hash.Hash, error) {
+	registryMu.RLock()
+	newFunc, ok := registry[normalizeName(name)]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %q", name)
+	}
+	return newFunc(), nil
+}
+
+// RegisterHash makes an algorithm available to HashByName, replacing any
+// existing algorithm of the same name.
+func RegisterHash(name string, newFunc func() hash.Hash) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[normalizeName(name)] = newFunc
+}
+
+// HashNames returns the registered algorithm names in sorted order
+func HashNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// FileToHashByName returns the hash of a file using the named algorithm
+func FileToHashByName(filepath, name string) (string, error) {
+	hasher, err := HashByName(name)
+	if err != nil {
+		return "", err
+	}
+	return FileToHash(filepath, hasher)
+}
+
+// FileToHashes computes several digests of a file while reading it only once,
+// returning a map from each algorithm name, as given, to its hex digest.
+//
+//	sums, err := hashExt.FileToHashes("release.tar.gz", "md5", "sha1", "sha256")
+func FileToHashes(filepath string, algorithms ...string) (map[string]string, error) {
+	if len(algorithms) == 0 {
+		return nil, fmt.Errorf("no hash algorithms given")
+	}
+
+	hashers := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, len(algorithms))
+	for i, name := range algorithms {
+		hasher, err := HashByName(name)
+		if err != nil {
+			return nil, err
+		}
+		hashers[i], writers[i] = hasher, hasher
+	}
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(io.MultiWriter(writers...), file); err != nil {
+		return nil, err
+	}
+
+	sums := make(map[string]string, len(algorithms))
+	for i, name := range algorithms {
+		sums[name] = hex.EncodeToString(hashers[i].Sum(nil))
+	}
+	return sums, nil
+}
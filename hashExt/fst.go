@@ -0,0 +1,188 @@
+package hashExt
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"hash/crc64"
+	"hash/fnv"
+	"math/bits"
+)
+
+// crc64Table is the ECMA-182 polynomial table, as used by xz
+var crc64Table = crc64.MakeTable(crc64.ECMA)
+
+// StringToFNV1a returns the 64-bit FNV-1a hash of the input string
+func StringToFNV1a(text string) string {
+	return uint64ToHex(BytesToFNV1a([]byte(text)))
+}
+
+// StringToCRC64 returns the CRC64 (ECMA) checksum of the input string
+func StringToCRC64(text string) string {
+	return uint64ToHex(BytesToCRC64([]byte(text)))
+}
+
+// StringToXXHash64 returns the xxHash64 hash (seed 0) of the input string
+func StringToXXHash64(text string) string {
+	return uint64ToHex(BytesToXXHash64([]byte(text)))
+}
+
+// FileToFNV1a returns the 64-bit FNV-1a hash of a file
+func FileToFNV1a(filepath string) (string, error) {
+	return FileToHash(filepath, fnv.New64a())
+}
+
+// FileToCRC64 returns the CRC64 (ECMA) checksum of a file
+func FileToCRC64(filepath string) (string, error) {
+	return FileToHash(filepath, crc64.New(crc64Table))
+}
+
+// FileToXXHash64 returns the xxHash64 hash (seed 0) of a file
+func FileToXXHash64(filepath string) (string, error) {
+	return FileToHash(filepath, NewXXHash64(0))
+}
+
+// BytesToFNV1a returns the 64-bit FNV-1a hash of data, for sharding and
+// hash tables where a number is more useful than a hex string
+func BytesToFNV1a(data []byte) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write(data)
+	return hasher.Sum64()
+}
+
+// BytesToCRC64 returns the CRC64 (ECMA) checksum of data
+func BytesToCRC64(data []byte) uint64 {
+	return crc64.Checksum(data, crc64Table)
+}
+
+// BytesToXXHash64 returns the xxHash64 hash (seed 0) of data
+func BytesToXXHash64(data []byte) uint64 {
+	hasher := NewXXHash64(0)
+	hasher.Write(data)
+	return hasher.Sum64()
+}
+
+func uint64ToHex(v uint64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return hex.EncodeToString(buf[:])
+}
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxHash64 implements the 64-bit xxHash algorithm
+type xxHash64 struct {
+	seed           uint64
+	v1, v2, v3, v4 uint64
+	total          uint64
+	mem            [32]byte
+	n              int // Bytes buffered in mem
+}
+
+// NewXXHash64 returns a streaming xxHash64 hasher with the given seed.
+// Its digest is the big-endian hash value, matching the xxhsum tool.
+func NewXXHash64(seed uint64) hash.Hash64 {
+	h := &xxHash64{seed: seed}
+	h.Reset()
+	return h
+}
+
+func (h *xxHash64) Reset() {
+	h.v1 = h.seed + xxPrime1 + xxPrime2
+	h.v2 = h.seed + xxPrime2
+	h.v3 = h.seed
+	h.v4 = h.seed - xxPrime1
+	h.total = 0
+	h.n = 0
+}
+
+func (h *xxHash64) Size() int      { return 8 }
+func (h *xxHash64) BlockSize() int { return 32 }
+
+func (h *xxHash64) Write(p []byte) (int, error) {
+	written := len(p)
+	h.total += uint64(written)
+
+	if h.n+len(p) < 32 {
+		h.n += copy(h.mem[h.n:], p)
+		return written, nil
+	}
+
+	if h.n > 0 {
+		c := copy(h.mem[h.n:], p)
+		p = p[c:]
+		h.stripe(h.mem[:])
+		h.n = 0
+	}
+	for len(p) >= 32 {
+		h.stripe(p[:32])
+		p = p[32:]
+	}
+	h.n = copy(h.mem[:], p)
+	return written, nil
+}
+
+func (h *xxHash64) stripe(b []byte) {
+	h.v1 = xxRound(h.v1, binary.LittleEndian.Uint64(b[0:]))
+	h.v2 = xxRound(h.v2, binary.LittleEndian.Uint64(b[8:]))
+	h.v3 = xxRound(h.v3, binary.LittleEndian.Uint64(b[16:]))
+	h.v4 = xxRound(h.v4, binary.LittleEndian.Uint64(b[24:]))
+}
+
+func (h *xxHash64) Sum64() uint64 {
+	var acc uint64
+	if h.total >= 32 {
+		acc = bits.RotateLeft64(h.v1, 1) + bits.RotateLeft64(h.v2, 7) +
+			bits.RotateLeft64(h.v3, 12) + bits.RotateLeft64(h.v4, 18)
+		acc = xxMergeRound(acc, h.v1)
+		acc = xxMergeRound(acc, h.v2)
+		acc = xxMergeRound(acc, h.v3)
+		acc = xxMergeRound(acc, h.v4)
+	} else {
+		acc = h.seed + xxPrime5
+	}
+	acc += h.total
+
+	b := h.mem[:h.n]
+	for ; len(b) >= 8; b = b[8:] {
+		acc ^= xxRound(0, binary.LittleEndian.Uint64(b))
+		acc = bits.RotateLeft64(acc, 27)*xxPrime1 + xxPrime4
+	}
+	if len(b) >= 4 {
+		acc ^= uint64(binary.LittleEndian.Uint32(b)) * xxPrime1
+		acc = bits.RotateLeft64(acc, 23)*xxPrime2 + xxPrime3
+		b = b[4:]
+	}
+	for _, c := range b {
+		acc ^= uint64(c) * xxPrime5
+		acc = bits.RotateLeft64(acc, 11) * xxPrime1
+	}
+
+	acc ^= acc >> 33
+	acc *= xxPrime2
+	acc ^= acc >> 29
+	acc *= xxPrime3
+	acc ^= acc >> 32
+	return acc
+}
+
+func (h *xxHash64) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, h.Sum64())
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime1 + xxPrime4
+}
@@ -11,6 +11,8 @@ import (
 	"io"
 	"os"
 	"strings"
+
+	"github.com/C0d3-5t3w/myT00L5/bytesExt"
 )
 
 // StringToMD5 returns MD5 hash of the input string
@@ -88,7 +90,7 @@ func FileToCRC32(filepath string) (string, error) {
 	return FileToHash(filepath, crc32.NewIEEE())
 }
 
-// CompareHashes compares two hashes (case-insensitive)
+// CompareHashes compares two hashes (case-insensitive) in constant time
 func CompareHashes(hash1, hash2 string) bool {
-	return strings.EqualFold(hash1, hash2)
+	return bytesExt.EqualConstantTimeString(strings.ToLower(hash1), strings.ToLower(hash2))
 }
@@ -0,0 +1,115 @@
+package hashExt
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// DefaultChunkSize is the chunk size FileToSHA256Parallel uses when given 0
+const DefaultChunkSize = 4 << 20
+
+// FileToSHA256Parallel hashes a file in chunks of chunkSize bytes on the given
+// number of workers, then combines the chunk digests into one. progress, if
+// not nil, is called after each chunk with the bytes hashed so far and the
+// file size; calls are serialised and the count never goes backwards.
+//
+// The result is NOT the plain SHA-256 of the file: it is the SHA-256 of the
+// chunk size (8 bytes, big-endian) followed by the SHA-256 of every chunk in
+// order, so it depends on chunkSize and can only be compared with digests
+// made with the same chunk size. Zero or negative arguments select
+// DefaultChunkSize and one worker per CPU.
+func FileToSHA256Parallel(path string, chunkSize int64, workers int, progress func(done, total int64)) (string, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := info.Size()
+	chunks := int((size + chunkSize - 1) / chunkSize)
+	workers = max(1, min(workers, chunks))
+
+	digests := make([][]byte, chunks)
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int64
+		firstErr error
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, min(chunkSize, size))
+			for i := range jobs {
+				offset := int64(i) * chunkSize
+				n := min(chunkSize, size-offset)
+				// ReadAt may return io.EOF with a full last chunk, but a short
+				// read means the file shrank while it was being hashed.
+				read, err := file.ReadAt(buf[:n], offset)
+				if int64(read) == n {
+					err = nil
+				} else if err == nil || err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("could not read chunk at offset %d: %v", offset, err)
+					}
+					mu.Unlock()
+					continue
+				}
+				sum := sha256.Sum256(buf[:n])
+				digests[i] = sum[:]
+
+				mu.Lock()
+				done += n
+				if progress != nil {
+					progress(done, size)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range chunks {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return "", firstErr
+	}
+
+	root := sha256.New()
+	binary.Write(root, binary.BigEndian, chunkSize)
+	for _, d := range digests {
+		root.Write(d)
+	}
+	return hex.EncodeToString(root.Sum(nil)), nil
+}
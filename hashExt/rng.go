@@ -0,0 +1,199 @@
+package hashExt
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultReplicas is the number of virtual nodes per node NewRing uses when given 0
+const DefaultReplicas = 160
+
+// Ring is a consistent hashing ring for sharding keys across nodes. Each
+// node is placed at many points (virtual nodes) so keys spread evenly, and
+// adding or removing a node only moves the keys that node gains or loses.
+// A Ring is safe for concurrent use.
+type Ring struct {
+	mu       sync.RWMutex
+	replicas int
+	hash     func([]byte) uint64
+	points   []ringPoint // Sorted by hash
+	nodes    map[string]bool
+}
+
+type ringPoint struct {
+	hash uint64
+	node string
+}
+
+// RebalanceStats describes how a change to a Ring moved the key space
+type RebalanceStats struct {
+	Moved  float64            // Fraction of the key space that changed node
+	Gained map[string]float64 // Fraction of the key space each node gained
+	Lost   map[string]float64 // Fraction of the key space each node lost
+}
+
+// NewRing creates an empty ring with replicas virtual nodes per node, hashing
+// with hashFunc (BytesToXXHash64 if nil). All clients sharing a ring must use
+// the same settings to agree on key placement.
+func NewRing(replicas int, hashFunc func([]byte) uint64) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	if hashFunc == nil {
+		hashFunc = BytesToXXHash64
+	}
+	return &Ring{replicas: replicas, hash: hashFunc, nodes: make(map[string]bool)}
+}
+
+// AddNode adds nodes to the ring; nodes already present are ignored
+func (r *Ring) AddNode(nodes ...string) RebalanceStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := r.ownership()
+	for _, node := range nodes {
+		if r.nodes[node] {
+			continue
+		}
+		r.nodes[node] = true
+		for i := range r.replicas {
+			r.points = append(r.points, ringPoint{r.hash([]byte(node + "#" + strconv.Itoa(i))), node})
+		}
+	}
+	r.sortPoints()
+	return rebalanceStats(before, r.ownership())
+}
+
+// RemoveNode removes nodes from the ring; unknown nodes are ignored
+func (r *Ring) RemoveNode(nodes ...string) RebalanceStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := r.ownership()
+	for _, node := range nodes {
+		delete(r.nodes, node)
+	}
+	r.points = slices.DeleteFunc(r.points, func(p ringPoint) bool {
+		return !r.nodes[p.node]
+	})
+	return rebalanceStats(before, r.ownership())
+}
+
+func (r *Ring) sortPoints() {
+	// Break hash ties by name so placement doesn't depend on insertion order
+	sort.Slice(r.points, func(i, j int) bool {
+		if r.points[i].hash != r.points[j].hash {
+			return r.points[i].hash < r.points[j].hash
+		}
+		return r.points[i].node < r.points[j].node
+	})
+}
+
+// search returns the index of the first point at or after h, wrapping around
+func (r *Ring) search(h uint64) int {
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return i
+}
+
+// GetNode returns the node responsible for key, or false if the ring is empty
+func (r *Ring) GetNode(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return "", false
+	}
+	return r.points[r.search(r.hash([]byte(key)))].node, true
+}
+
+// GetNodes returns up to n distinct nodes for key in ring order, for
+// replicating a key to several nodes. The first is the GetNode result.
+func (r *Ring) GetNodes(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 || n <= 0 {
+		return nil
+	}
+
+	n = min(n, len(r.nodes))
+	result := make([]string, 0, n)
+	start := r.search(r.hash([]byte(key)))
+	for i := 0; len(result) < n; i++ {
+		node := r.points[(start+i)%len(r.points)].node
+		if !slices.Contains(result, node) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// Nodes returns the nodes in the ring in sorted order
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]string, 0, len(r.nodes))
+	for node := range r.nodes {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// Len returns the number of nodes in the ring
+func (r *Ring) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.nodes)
+}
+
+// Ownership returns the fraction of the key space each node is responsible
+// for. With enough replicas every node's share is close to 1/Len.
+func (r *Ring) Ownership() map[string]float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ownership()
+}
+
+// ownership credits each point with the arc between its predecessor and itself
+func (r *Ring) ownership() map[string]float64 {
+	shares := make(map[string]float64, len(r.nodes))
+	if len(r.points) == 0 {
+		return shares
+	}
+
+	const space = 1 << 64
+	prev := r.points[len(r.points)-1].hash
+	for _, p := range r.points {
+		// Unsigned subtraction wraps around for the first point
+		shares[p.node] += float64(p.hash-prev) / space
+		prev = p.hash
+	}
+	if len(r.points) == 1 || len(r.nodes) == 1 {
+		for node := range shares {
+			shares[node] = 1
+		}
+	}
+	return shares
+}
+
+func rebalanceStats(before, after map[string]float64) RebalanceStats {
+	stats := RebalanceStats{Gained: map[string]float64{}, Lost: map[string]float64{}}
+	for node, share := range after {
+		if d := share - before[node]; d > 0 {
+			stats.Gained[node] = d
+			stats.Moved += d
+		}
+	}
+	for node, share := range before {
+		if d := share - after[node]; d > 0 {
+			stats.Lost[node] = d
+		}
+	}
+	return stats
+}
@@ -0,0 +1,184 @@
+package hashExt
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ChecksumResult is the outcome of verifying one entry of a checksum file
+type ChecksumResult struct {
+	Path     string // Path as written in the checksum file
+	Expected string // Digest from the checksum file
+	Actual   string // Digest of the file on disk, empty if it could not be read
+	OK       bool   // Whether the digests match
+	Err      error  // Error reading the file, if any
+}
+
+// ChecksumFileName returns the conventional manifest name for an algorithm,
+// such as SHA256SUMS or SHA3-256SUMS
+func ChecksumFileName(algorithm string) string {
+	return strings.ToUpper(normalizeName(algorithm)) + "SUMS"
+}
+
+// WriteChecksumFile hashes every regular file under dir and writes a manifest
+// named by ChecksumFileName into dir, in the format of sha256sum and friends,
+// so it can also be checked with "sha256sum -c". Paths are relative to dir,
+// use forward slashes and are sorted. It returns the path of the manifest.
+func WriteChecksumFile(dir, algorithm string) (string, error) {
+	if _, err := HashByName(algorithm); err != nil {
+		return "", err
+	}
+	name := ChecksumFileName(algorithm)
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel != name {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not walk %s: %v", dir, err)
+	}
+	slices.Sort(files)
+
+	var sb strings.Builder
+	for _, rel := range files {
+		sum, err := FileToHashByName(filepath.Join(dir, filepath.FromSlash(rel)), algorithm)
+		if err != nil {
+			return "", fmt.Errorf("could not hash %s: %v", rel, err)
+		}
+		sb.WriteString(formatChecksumLine(sum, rel))
+	}
+
+	manifest := filepath.Join(dir, name)
+	if err := os.WriteFile(manifest, []byte(sb.String()), 0644); err != nil {
+		return "", fmt.Errorf("could not write checksum file: %v", err)
+	}
+	return manifest, nil
+}
+
+// formatChecksumLine writes one manifest line, escaping backslashes and
+// newlines in the name the way GNU coreutils does.
+func formatChecksumLine(sum, name string) string {
+	if strings.ContainsAny(name, "\\\n") {
+		name = strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(name)
+		return "\\" + sum + "  " + name + "\n"
+	}
+	return sum + "  " + name + "\n"
+}
+
+// parseChecksumLine splits a manifest line into digest and name. Both text
+// ("  ") and binary (" *") separators are accepted.
+func parseChecksumLine(line string) (sum, name string, ok bool) {
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+	sum, name, ok = strings.Cut(line, " ")
+	if !ok || len(name) < 2 || (name[0] != ' ' && name[0] != '*') {
+		return "", "", false
+	}
+	name = name[1:]
+	if escaped {
+		name = strings.NewReplacer("\\\\", "\\", "\\n", "\n").Replace(name)
+	}
+	return sum, name, sum != "" && name != ""
+}
+
+// VerifyChecksumFile checks every entry of a sha256sum-style manifest against
+// the files on disk, relative to the manifest's directory. The algorithm is
+// taken from the manifest's name (SHA256SUMS, checksums.sha512, ...) or,
+// failing that, from the digest length. An error is returned only if the
+// manifest itself cannot be read or parsed; per-file failures are reported
+// in the results. Entries with absolute paths or paths leading out of the
+// manifest's directory are failed without being read.
+func VerifyChecksumFile(path string) ([]ChecksumResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read checksum file: %v", err)
+	}
+
+	algorithm := algorithmFromFileName(filepath.Base(path))
+	dir := filepath.Dir(path)
+
+	var results []ChecksumResult
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sum, name, ok := parseChecksumLine(line)
+		if !ok {
+			return nil, fmt.Errorf("could not parse checksum file line %d: %q", lineNo, line)
+		}
+
+		alg := algorithm
+		if alg == "" {
+			if alg = algorithmFromLength(len(sum)); alg == "" {
+				return nil, fmt.Errorf("could not determine hash algorithm for line %d", lineNo)
+			}
+		}
+
+		result := ChecksumResult{Path: name, Expected: strings.ToLower(sum)}
+		if filepath.IsLocal(filepath.FromSlash(name)) {
+			result.Actual, result.Err = FileToHashByName(filepath.Join(dir, filepath.FromSlash(name)), alg)
+		} else {
+			result.Err = fmt.Errorf("path %q is outside the checksum file's directory", name)
+		}
+		result.OK = result.Err == nil && result.Actual == result.Expected
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read checksum file: %v", err)
+	}
+	return results, nil
+}
+
+// algorithmFromFileName recognises names such as SHA256SUMS, sha1sums.txt
+// and checksums.sha512, returning "" if no algorithm is known.
+func algorithmFromFileName(base string) string {
+	name := strings.TrimSuffix(strings.ToLower(base), ".txt")
+	candidates := []string{strings.TrimSuffix(name, "sums"), strings.TrimSuffix(name, "sum")}
+	if ext := filepath.Ext(name); ext != "" {
+		candidates = append(candidates, ext[1:])
+	}
+	for _, c := range candidates {
+		if _, err := HashByName(c); err == nil && c != "" {
+			return c
+		}
+	}
+	return ""
+}
+
+// algorithmFromLength guesses the algorithm of the common *sum tools from
+// the length of a hex digest
+func algorithmFromLength(n int) string {
+	switch n {
+	case 32:
+		return "md5"
+	case 40:
+		return "sha1"
+	case 64:
+		return "sha256"
+	case 128:
+		return "sha512"
+	}
+	return ""
+}
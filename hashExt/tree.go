@@ -0,0 +1,200 @@
+package hashExt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/C0d3-5t3w/myT00L5/osExt"
+)
+
+// DirOption configures DirToSHA256.
+type DirOption func(*dirOptions)
+
+// SymlinkPolicy controls how DirToSHA256 treats symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SymlinkHashTarget hashes the link's target path without following it.
+	// This is the default, so retargeting a link changes the digest.
+	SymlinkHashTarget SymlinkPolicy = iota
+	// SymlinkFollow hashes the file or directory the link points to. Links
+	// that lead back into a directory already being hashed are an error.
+	SymlinkFollow
+	// SymlinkSkip leaves links out of the digest.
+	SymlinkSkip
+)
+
+type dirOptions struct {
+	ignore   *osExt.IgnoreMatcher
+	symlinks SymlinkPolicy
+	newHash  func() hash.Hash
+	err      error
+}
+
+// ExcludePaths leaves out entries matching any of the .gitignore-style
+// patterns, which are matched against slash-separated paths relative to root.
+func ExcludePaths(patterns ...string) DirOption {
+	return func(o *dirOptions) {
+		m, err := osExt.NewIgnoreMatcher(patterns...)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.ignore = m
+	}
+}
+
+// Symlinks sets how symbolic links are handled.
+func Symlinks(policy SymlinkPolicy) DirOption {
+	return func(o *dirOptions) {
+		o.symlinks = policy
+	}
+}
+
+// Entry type markers, so a file and a link with the same contents and
+// target text hash differently
+const (
+	treeFile    = 'f'
+	treeDir     = 'd'
+	treeSymlink = 'l'
+)
+
+// DirToSHA256 returns a Merkle-style SHA-256 digest of the directory tree at
+// root. Each file is hashed by content, each link by its target, and each
+// directory by the sorted names, types and digests of its entries, so the
+// result changes if any file is added, removed, renamed or modified, but not
+// when only timestamps or permissions change. Empty directories contribute
+// to the digest.
+func DirToSHA256(root string, opts ...DirOption) (string, error) {
+	o := &dirOptions{newHash: sha256.New}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.err != nil {
+		return "", o.err
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", root)
+	}
+
+	sum, err := o.hashDir(root, "", map[string]bool{})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// hashDir hashes the directory at dir, whose path relative to the root is rel.
+// active holds the real paths of the directories currently being hashed, to
+// detect cycles when following links.
+func (o *dirOptions) hashDir(dir, rel string, active map[string]bool) ([]byte, error) {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	if active[real] {
+		return nil, fmt.Errorf("symlink cycle at %s", rel)
+	}
+	active[real] = true
+	defer delete(active, real)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	h := o.newHash()
+	for _, entry := range entries {
+		name := entry.Name()
+		childRel := path.Join(rel, name)
+		childPath := filepath.Join(dir, name)
+
+		kind, sum, err := o.hashEntry(childPath, childRel, entry, active)
+		if err != nil {
+			return nil, err
+		}
+		if sum == nil {
+			continue
+		}
+		fmt.Fprintf(h, "%c %d:%s\x00", kind, len(name), name)
+		h.Write(sum)
+	}
+	return h.Sum(nil), nil
+}
+
+// hashEntry returns the type marker and digest of one entry, or a nil digest
+// if the entry is excluded.
+func (o *dirOptions) hashEntry(p, rel string, entry fs.DirEntry, active map[string]bool) (byte, []byte, error) {
+	mode := entry.Type()
+	if mode&fs.ModeSymlink != 0 {
+		switch o.symlinks {
+		case SymlinkSkip:
+			return 0, nil, nil
+		case SymlinkHashTarget:
+			if o.excluded(rel, false) {
+				return 0, nil, nil
+			}
+			target, err := os.Readlink(p)
+			if err != nil {
+				return 0, nil, err
+			}
+			h := o.newHash()
+			io.WriteString(h, filepath.ToSlash(target))
+			return treeSymlink, h.Sum(nil), nil
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			return 0, nil, err
+		}
+		mode = info.Mode().Type()
+	}
+
+	switch {
+	case mode.IsDir():
+		if o.excluded(rel, true) {
+			return 0, nil, nil
+		}
+		sum, err := o.hashDir(p, rel, active)
+		return treeDir, sum, err
+	case mode.IsRegular():
+		if o.excluded(rel, false) {
+			return 0, nil, nil
+		}
+		sum, err := o.hashFile(p)
+		return treeFile, sum, err
+	default:
+		// Devices, sockets and pipes have no stable contents
+		return 0, nil, nil
+	}
+}
+
+func (o *dirOptions) excluded(rel string, isDir bool) bool {
+	return o.ignore != nil && o.ignore.Match(rel, isDir)
+}
+
+func (o *dirOptions) hashFile(p string) ([]byte, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	h := o.newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,289 @@
+package imageExt
+
+import (
+	"image/color"
+	"math"
+)
+
+// Color space conversions work on sRGB colors without alpha: alpha is
+// removed from the input and the colors they return are opaque.
+
+// HSL is a color as hue in degrees [0, 360), saturation and lightness in [0, 1].
+type HSL struct {
+	H, S, L float64
+}
+
+// HSV is a color as hue in degrees [0, 360), saturation and value in [0, 1].
+type HSV struct {
+	H, S, V float64
+}
+
+// Lab is a color in CIE L*a*b* under the D65 white point, with L in [0, 100].
+type Lab struct {
+	L, A, B float64
+}
+
+// RGBA implements color.Color.
+func (c HSL) RGBA() (r, g, b, a uint32) {
+	return HSLToRGB(c).RGBA()
+}
+
+// RGBA implements color.Color.
+func (c HSV) RGBA() (r, g, b, a uint32) {
+	return HSVToRGB(c).RGBA()
+}
+
+// RGBA implements color.Color.
+func (c Lab) RGBA() (r, g, b, a uint32) {
+	return LabToRGB(c).RGBA()
+}
+
+// rgbFloats returns the channels of c, alpha removed, in [0, 1].
+func rgbFloats(c color.Color) (r, g, b float64) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return float64(n.R) / 255, float64(n.G) / 255, float64(n.B) / 255
+}
+
+func rgbFromFloats(r, g, b float64) color.RGBA {
+	to8 := func(v float64) uint8 {
+		return uint8(math.Round(math.Min(math.Max(v, 0), 1) * 255))
+	}
+	return color.RGBA{to8(r), to8(g), to8(b), 255}
+}
+
+// hue returns the hue in degrees of a color with the given channels, maximum
+// and chroma.
+func hue(r, g, b, maxC, chroma float64) float64 {
+	if chroma == 0 {
+		return 0
+	}
+	var h float64
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/chroma, 6)
+	case g:
+		h = (b-r)/chroma + 2
+	default:
+		h = (r-g)/chroma + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// RGBToHSL converts c to HSL.
+func RGBToHSL(c color.Color) HSL {
+	r, g, b := rgbFloats(c)
+	maxC, minC := max(r, g, b), min(r, g, b)
+	chroma := maxC - minC
+	l := (maxC + minC) / 2
+	s := 0.0
+	if chroma != 0 {
+		s = chroma / (1 - math.Abs(2*l-1))
+	}
+	return HSL{H: hue(r, g, b, maxC, chroma), S: s, L: l}
+}
+
+// HSLToRGB converts an HSL color to RGB.
+func HSLToRGB(c HSL) color.RGBA {
+	chroma := (1 - math.Abs(2*c.L-1)) * c.S
+	return rgbFromHueChroma(c.H, chroma, c.L-chroma/2)
+}
+
+// RGBToHSV converts c to HSV.
+func RGBToHSV(c color.Color) HSV {
+	r, g, b := rgbFloats(c)
+	maxC, minC := max(r, g, b), min(r, g, b)
+	chroma := maxC - minC
+	s := 0.0
+	if maxC != 0 {
+		s = chroma / maxC
+	}
+	return HSV{H: hue(r, g, b, maxC, chroma), S: s, V: maxC}
+}
+
+// HSVToRGB converts an HSV color to RGB.
+func HSVToRGB(c HSV) color.RGBA {
+	chroma := c.V * c.S
+	return rgbFromHueChroma(c.H, chroma, c.V-chroma)
+}
+
+// rgbFromHueChroma builds a color from hue, chroma and the amount m added to
+// every channel.
+func rgbFromHueChroma(h, chroma, m float64) color.RGBA {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	hp := h / 60
+	x := chroma * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch int(hp) {
+	case 0:
+		r, g, b = chroma, x, 0
+	case 1:
+		r, g, b = x, chroma, 0
+	case 2:
+		r, g, b = 0, chroma, x
+	case 3:
+		r, g, b = 0, x, chroma
+	case 4:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return rgbFromFloats(r+m, g+m, b+m)
+}
+
+// D65 reference white in XYZ.
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// RGBToLab converts c to CIE L*a*b*.
+func RGBToLab(c color.Color) Lab {
+	r, g, b := rgbFloats(c)
+	r, g, b = srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / whiteX
+	y := (0.2126729*r + 0.7151522*g + 0.0721750*b) / whiteY
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / whiteZ
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return Lab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+// LabToRGB converts a CIE L*a*b* color to RGB, clamping colors outside the
+// sRGB gamut.
+func LabToRGB(c Lab) color.RGBA {
+	fy := (c.L + 16) / 116
+	fx := fy + c.A/500
+	fz := fy - c.B/200
+	finv := func(t float64) float64 {
+		if t3 := t * t * t; t3 > 216.0/24389 {
+			return t3
+		}
+		return (116*t - 16) * 27 / 24389
+	}
+	x, y, z := finv(fx)*whiteX, finv(fy)*whiteY, finv(fz)*whiteZ
+
+	r := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	g := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	b := 0.0556434*x - 0.2040259*y + 1.0572252*z
+	return rgbFromFloats(linearToSRGB(r), linearToSRGB(g), linearToSRGB(b))
+}
+
+// RGBToYCbCr converts c to Y'CbCr as used by JPEG.
+func RGBToYCbCr(c color.Color) color.YCbCr {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	y, cb, cr := color.RGBToYCbCr(n.R, n.G, n.B)
+	return color.YCbCr{Y: y, Cb: cb, Cr: cr}
+}
+
+// YCbCrToRGB converts a Y'CbCr color to RGB.
+func YCbCrToRGB(c color.YCbCr) color.RGBA {
+	r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+	return color.RGBA{r, g, b, 255}
+}
+
+// DeltaE76 returns the CIE76 color difference, the Euclidean distance in Lab.
+// A difference of about 2.3 is just noticeable.
+func DeltaE76(a, b Lab) float64 {
+	return math.Sqrt((a.L-b.L)*(a.L-b.L) + (a.A-b.A)*(a.A-b.A) + (a.B-b.B)*(a.B-b.B))
+}
+
+// DeltaE2000 returns the CIEDE2000 color difference, which follows perceived
+// differences more closely than DeltaE76. A difference under 1 is not
+// perceptible.
+func DeltaE2000(lab1, lab2 Lab) float64 {
+	const pow25to7 = 6103515625.0 // 25^7
+	deg := math.Pi / 180
+
+	c1 := math.Hypot(lab1.A, lab1.B)
+	c2 := math.Hypot(lab2.A, lab2.B)
+	cMean7 := math.Pow((c1+c2)/2, 7)
+	g := 0.5 * (1 - math.Sqrt(cMean7/(cMean7+pow25to7)))
+	a1, a2 := (1+g)*lab1.A, (1+g)*lab2.A
+	c1p, c2p := math.Hypot(a1, lab1.B), math.Hypot(a2, lab2.B)
+
+	hp := func(b, a float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		h := math.Atan2(b, a) / deg
+		if h < 0 {
+			h += 360
+		}
+		return h
+	}
+	h1p, h2p := hp(lab1.B, a1), hp(lab2.B, a2)
+
+	dLp := lab2.L - lab1.L
+	dCp := c2p - c1p
+	var dhp float64
+	if c1p*c2p != 0 {
+		dhp = h2p - h1p
+		if dhp > 180 {
+			dhp -= 360
+		} else if dhp < -180 {
+			dhp += 360
+		}
+	}
+	dHp := 2 * math.Sqrt(c1p*c2p) * math.Sin(dhp/2*deg)
+
+	lMean := (lab1.L + lab2.L) / 2
+	cMeanP := (c1p + c2p) / 2
+	hMeanP := h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			hMeanP /= 2
+		case h1p+h2p < 360:
+			hMeanP = (hMeanP + 360) / 2
+		default:
+			hMeanP = (hMeanP - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17*math.Cos((hMeanP-30)*deg) + 0.24*math.Cos(2*hMeanP*deg) +
+		0.32*math.Cos((3*hMeanP+6)*deg) - 0.20*math.Cos((4*hMeanP-63)*deg)
+	dTheta := 30 * math.Exp(-((hMeanP-275)/25)*((hMeanP-275)/25))
+	cMeanP7 := math.Pow(cMeanP, 7)
+	rc := 2 * math.Sqrt(cMeanP7/(cMeanP7+pow25to7))
+	sl := 1 + 0.015*(lMean-50)*(lMean-50)/math.Sqrt(20+(lMean-50)*(lMean-50))
+	sc := 1 + 0.045*cMeanP
+	sh := 1 + 0.015*cMeanP*t
+	rt := -math.Sin(2*dTheta*deg) * rc
+
+	l, c, h := dLp/sl, dCp/sc, dHp/sh
+	return math.Sqrt(l*l + c*c + h*h + rt*c*h)
+}
+
+// ColorDistance returns the perceptual difference between two colors as the
+// CIEDE2000 difference of their Lab values.
+func ColorDistance(a, b color.Color) float64 {
+	return DeltaE2000(RGBToLab(a), RGBToLab(b))
+}
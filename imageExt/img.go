@@ -7,7 +7,6 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
-	"image/jpeg"
 	"image/png"
 	"math"
 	"os"
@@ -148,8 +147,9 @@ func AdjustBrightness(img image.Image, percentage float64) *image.RGBA {
 	return dst
 }
 
-// SaveJPEG saves an image to a file in JPEG format with the given quality
-func SaveJPEG(img image.Image, filename string, quality int) error {
+// SaveJPEG saves an image to a file in JPEG format with the given quality.
+// Options select progressive encoding and chroma subsampling.
+func SaveJPEG(img image.Image, filename string, quality int, opts ...JPEGOption) error {
 	if quality < 1 || quality > 100 {
 		return errors.New("quality must be between 1 and 100")
 	}
@@ -160,7 +160,7 @@ func SaveJPEG(img image.Image, filename string, quality int) error {
 	}
 	defer f.Close()
 
-	return jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
+	return EncodeJPEG(f, img, quality, opts...)
 }
 
 // SavePNG saves an image to a file in PNG format
@@ -0,0 +1,507 @@
+package imageExt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"math"
+	"os"
+)
+
+// Subsampling is the chroma subsampling of a JPEG: how many luma samples
+// share one pair of chroma samples.
+type Subsampling int
+
+const (
+	// Subsampling420 halves chroma resolution both ways, as image/jpeg does.
+	Subsampling420 Subsampling = iota
+	// Subsampling422 halves chroma resolution horizontally.
+	Subsampling422
+	// Subsampling444 keeps full chroma resolution, for sharp colored edges
+	// such as text and line art.
+	Subsampling444
+)
+
+// JPEGOption configures JPEG encoding.
+type JPEGOption func(*jpegOptions)
+
+type jpegOptions struct {
+	progressive bool
+	subsampling Subsampling
+}
+
+// JPEGProgressive writes a progressive JPEG, which browsers show at low
+// detail first and refine as it loads.
+func JPEGProgressive() JPEGOption {
+	return func(o *jpegOptions) {
+		o.progressive = true
+	}
+}
+
+// JPEGSubsampling sets the chroma subsampling, Subsampling420 by default.
+func JPEGSubsampling(s Subsampling) JPEGOption {
+	return func(o *jpegOptions) {
+		o.subsampling = s
+	}
+}
+
+// EncodeJPEG writes img to w as a JPEG with the given quality, from 1 to 100.
+// Without options it uses image/jpeg.
+func EncodeJPEG(w io.Writer, img image.Image, quality int, opts ...JPEGOption) error {
+	if quality < 1 || quality > 100 {
+		return errors.New("quality must be between 1 and 100")
+	}
+	if len(opts) == 0 {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	}
+	var o jpegOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return encodeJPEG(w, img, quality, o)
+}
+
+// SaveJPEGTargetSize saves img as a JPEG of at most maxBytes, using the
+// highest quality that fits, which it returns. It fails without writing the
+// file if even quality 1 is too large.
+func SaveJPEGTargetSize(img image.Image, filename string, maxBytes int, opts ...JPEGOption) (int, error) {
+	var best []byte
+	bestQuality := 0
+	var buf bytes.Buffer
+	lo, hi := 1, 100
+	for lo <= hi {
+		q := (lo + hi) / 2
+		buf.Reset()
+		if err := EncodeJPEG(&buf, img, q, opts...); err != nil {
+			return 0, err
+		}
+		if buf.Len() <= maxBytes {
+			best, bestQuality = append(best[:0], buf.Bytes()...), q
+			lo = q + 1
+		} else {
+			hi = q - 1
+		}
+	}
+	if best == nil {
+		return 0, fmt.Errorf("could not encode JPEG within %d bytes: %d bytes at quality 1", maxBytes, buf.Len())
+	}
+	return bestQuality, os.WriteFile(filename, best, 0644)
+}
+
+// zigzag maps zig-zag order to natural order within an 8x8 block.
+var zigzag = [64]int{
+	0, 1, 8, 16, 9, 2, 3, 10,
+	17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34,
+	27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36,
+	29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46,
+	53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+// Quantization tables from section K.1 of the JPEG standard, in zig-zag order.
+var baseQuant = [2][64]int{
+	{
+		16, 11, 12, 14, 12, 10, 16, 14, 13, 14, 18, 17, 16, 19, 24, 40,
+		26, 24, 22, 22, 24, 49, 35, 37, 29, 40, 58, 51, 61, 60, 57, 51,
+		56, 55, 64, 72, 92, 78, 64, 68, 87, 69, 55, 56, 80, 109, 81, 87,
+		95, 98, 103, 104, 103, 62, 77, 113, 121, 112, 100, 120, 92, 101, 103, 99,
+	},
+	{
+		17, 18, 18, 24, 21, 24, 47, 26, 26, 47, 99, 66, 56, 66, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99, 99,
+	},
+}
+
+// huffSpec is a Huffman table as counts of codes per length and symbols.
+type huffSpec struct {
+	counts [16]byte
+	values []byte
+}
+
+// Huffman tables from section K.3 of the JPEG standard: luminance DC and AC,
+// then chrominance DC and AC.
+var huffSpecs = [4]huffSpec{
+	{[16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0}, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+	{[16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 125}, []byte{
+		0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12, 0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+		0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08, 0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+		0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+		0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+		0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+		0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+		0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+		0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+		0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+		0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+		0xf9, 0xfa,
+	}},
+	{[16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0}, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+	{[16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 119}, []byte{
+		0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21, 0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+		0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91, 0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+		0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34, 0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+		0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+		0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+		0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+		0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+		0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+		0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+		0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+		0xf9, 0xfa,
+	}},
+}
+
+// huffCode is a Huffman code and its length in bits.
+type huffCode struct {
+	code uint32
+	size uint8
+}
+
+// huffCodes builds the code for each symbol of a table.
+func huffCodes(spec huffSpec) [256]huffCode {
+	var codes [256]huffCode
+	code, k := uint32(0), 0
+	for length, count := range spec.counts {
+		for range count {
+			codes[spec.values[k]] = huffCode{code, uint8(length + 1)}
+			code++
+			k++
+		}
+		code <<= 1
+	}
+	return codes
+}
+
+// dctCos[x][u] is C(u)/2 * cos((2x+1)uπ/16), the forward DCT basis.
+var dctCos = func() (t [8][8]float64) {
+	for x := range 8 {
+		for u := range 8 {
+			c := 0.5
+			if u == 0 {
+				c = 0.5 / math.Sqrt2
+			}
+			t[x][u] = c * math.Cos(float64(2*x+1)*float64(u)*math.Pi/16)
+		}
+	}
+	return t
+}()
+
+// jpegComponent is one color channel: its sampling factors, quantization and
+// Huffman table index, and quantized blocks in zig-zag order.
+type jpegComponent struct {
+	id      int
+	h, v    int
+	table   int // 0 for luma, 1 for chroma
+	blocksX int // blocks per row, covering whole MCUs
+	blocksY int
+	realX   int // blocks per row covering the component's own size
+	realY   int
+	blocks  [][64]int32
+}
+
+// encodeJPEG writes a baseline or progressive JPEG with the given chroma
+// subsampling.
+func encodeJPEG(w io.Writer, img image.Image, quality int, o jpegOptions) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > 65535 || height > 65535 {
+		return fmt.Errorf("could not encode JPEG: invalid size %dx%d", width, height)
+	}
+
+	// Quality scaling as in image/jpeg.
+	scale := 200 - quality*2
+	if quality < 50 {
+		scale = 5000 / quality
+	}
+	var quant [2][64]int
+	for t := range quant {
+		for i, q := range baseQuant[t] {
+			quant[t][i] = min(max((q*scale+50)/100, 1), 255)
+		}
+	}
+
+	// Sample the image into Y'CbCr planes, or a single gray plane.
+	_, gray := img.(*image.Gray)
+	planes := make([][]uint8, 3)
+	if gray {
+		planes = planes[:1]
+	}
+	for i := range planes {
+		planes[i] = make([]uint8, width*height)
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			if gray {
+				planes[0][y*width+x] = color.GrayModel.Convert(c).(color.Gray).Y
+				continue
+			}
+			r, g, bl, _ := c.RGBA()
+			yy, cb, cr := color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(bl>>8))
+			planes[0][y*width+x], planes[1][y*width+x], planes[2][y*width+x] = yy, cb, cr
+		}
+	}
+
+	hMax, vMax := 1, 1
+	if !gray {
+		switch o.subsampling {
+		case Subsampling420:
+			hMax, vMax = 2, 2
+		case Subsampling422:
+			hMax = 2
+		}
+	}
+	mcusX := (width + 8*hMax - 1) / (8 * hMax)
+	mcusY := (height + 8*vMax - 1) / (8 * vMax)
+
+	comps := make([]*jpegComponent, len(planes))
+	for i, plane := range planes {
+		c := &jpegComponent{id: i + 1, h: 1, v: 1, table: min(i, 1)}
+		if i == 0 {
+			c.h, c.v = hMax, vMax
+		}
+		fx, fy := hMax/c.h, vMax/c.v
+		compW := (width*c.h + hMax - 1) / hMax
+		compH := (height*c.v + vMax - 1) / vMax
+		c.blocksX, c.blocksY = mcusX*c.h, mcusY*c.v
+		c.realX, c.realY = (compW+7)/8, (compH+7)/8
+		c.blocks = make([][64]int32, c.blocksX*c.blocksY)
+
+		// sample averages the fx by fy source pixels behind a component
+		// sample, repeating edge pixels past the image.
+		sample := func(cx, cy int) float64 {
+			sum := 0
+			for dy := range fy {
+				for dx := range fx {
+					x := min(cx*fx+dx, width-1)
+					y := min(cy*fy+dy, height-1)
+					sum += int(plane[y*width+x])
+				}
+			}
+			return float64(sum) / float64(fx*fy)
+		}
+
+		var pixels, rows [8][8]float64
+		for by := range c.blocksY {
+			for bx := range c.blocksX {
+				for y := range 8 {
+					for x := range 8 {
+						pixels[y][x] = sample(bx*8+x, by*8+y) - 128
+					}
+				}
+				// Separable forward DCT: rows, then columns.
+				for y := range 8 {
+					for u := range 8 {
+						s := 0.0
+						for x := range 8 {
+							s += pixels[y][x] * dctCos[x][u]
+						}
+						rows[y][u] = s
+					}
+				}
+				block := &c.blocks[by*c.blocksX+bx]
+				for k := range 64 {
+					u, v := zigzag[k]%8, zigzag[k]/8
+					s := 0.0
+					for y := range 8 {
+						s += rows[y][u] * dctCos[y][v]
+					}
+					q := int32(math.Round(s / float64(quant[c.table][k])))
+					block[k] = min(max(q, -1023), 1023)
+				}
+			}
+		}
+		comps[i] = c
+	}
+
+	e := &jpegWriter{w: bufio.NewWriter(w)}
+	for t := range huffSpecs {
+		e.codes[t] = huffCodes(huffSpecs[t])
+	}
+
+	e.marker(0xd8, nil)
+	// Define quantization tables.
+	nTables := min(len(comps), 2)
+	dqt := make([]byte, 0, 65*nTables)
+	for t := range nTables {
+		dqt = append(dqt, byte(t))
+		for _, q := range quant[t] {
+			dqt = append(dqt, byte(q))
+		}
+	}
+	e.marker(0xdb, dqt)
+	// Start of frame, baseline or progressive.
+	sof := []byte{8, byte(height >> 8), byte(height), byte(width >> 8), byte(width), byte(len(comps))}
+	for _, c := range comps {
+		sof = append(sof, byte(c.id), byte(c.h<<4|c.v), byte(c.table))
+	}
+	if o.progressive {
+		e.marker(0xc2, sof)
+	} else {
+		e.marker(0xc0, sof)
+	}
+	// Define Huffman tables.
+	var dht []byte
+	for t := range nTables * 2 {
+		class, id := t%2, t/2
+		dht = append(dht, byte(class<<4|id))
+		dht = append(dht, huffSpecs[t].counts[:]...)
+		dht = append(dht, huffSpecs[t].values...)
+	}
+	e.marker(0xc4, dht)
+
+	if o.progressive {
+		e.scan(comps, 0, 0)
+		for _, band := range [][2]int{{1, 5}, {6, 63}} {
+			for _, c := range comps {
+				e.scan([]*jpegComponent{c}, band[0], band[1])
+			}
+		}
+	} else {
+		e.scan(comps, 0, 63)
+	}
+
+	e.marker(0xd9, nil)
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// jpegWriter writes JPEG markers and entropy-coded data.
+type jpegWriter struct {
+	w     *bufio.Writer
+	codes [4][256]huffCode
+	bits  uint32
+	nBits uint8
+	err   error
+}
+
+func (e *jpegWriter) write(p []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(p)
+	}
+}
+
+// marker writes a marker with an optional length-prefixed payload.
+func (e *jpegWriter) marker(code byte, payload []byte) {
+	e.write([]byte{0xff, code})
+	if payload != nil {
+		n := len(payload) + 2
+		e.write([]byte{byte(n >> 8), byte(n)})
+		e.write(payload)
+	}
+}
+
+// emit writes the low size bits of bits, stuffing a zero after each 0xff.
+func (e *jpegWriter) emit(bits uint32, size uint8) {
+	e.bits = e.bits<<size | bits&(1<<size-1)
+	e.nBits += size
+	for e.nBits >= 8 {
+		e.nBits -= 8
+		c := byte(e.bits >> e.nBits)
+		if c == 0xff {
+			e.write([]byte{0xff, 0})
+		} else {
+			e.write([]byte{c})
+		}
+	}
+}
+
+// flush pads the last byte of a scan with ones.
+func (e *jpegWriter) flush() {
+	if e.nBits > 0 {
+		e.emit(1<<(8-e.nBits)-1, 8-e.nBits)
+	}
+	e.bits, e.nBits = 0, 0
+}
+
+// emitValue writes a symbol followed by the extra bits of v.
+func (e *jpegWriter) emitValue(table int, run uint8, v int32) {
+	a, bits := v, v
+	if a < 0 {
+		a, bits = -v, v-1
+	}
+	size := uint8(0)
+	for a > 0 {
+		size++
+		a >>= 1
+	}
+	c := e.codes[table][run<<4|size]
+	e.emit(c.code, c.size)
+	if size > 0 {
+		e.emit(uint32(bits), size)
+	}
+}
+
+// scan writes a scan of coefficients ss to se of comps. A scan of several
+// components interleaves them by MCU; one of a single component covers just
+// that component's blocks.
+func (e *jpegWriter) scan(comps []*jpegComponent, ss, se int) {
+	sos := []byte{byte(len(comps))}
+	for _, c := range comps {
+		sos = append(sos, byte(c.id), byte(c.table<<4|c.table))
+	}
+	sos = append(sos, byte(ss), byte(se), 0)
+	e.marker(0xda, sos)
+
+	preds := make([]int32, len(comps))
+	encode := func(ci int, block *[64]int32) {
+		c := comps[ci]
+		if ss == 0 {
+			e.emitValue(2*c.table, 0, block[0]-preds[ci])
+			preds[ci] = block[0]
+		}
+		if se == 0 {
+			return
+		}
+		run := uint8(0)
+		for k := max(ss, 1); k <= se; k++ {
+			if block[k] == 0 {
+				run++
+				continue
+			}
+			for run >= 16 {
+				e.emitValue(2*c.table+1, 15, 0)
+				run -= 16
+			}
+			e.emitValue(2*c.table+1, run, block[k])
+			run = 0
+		}
+		if run > 0 {
+			e.emitValue(2*c.table+1, 0, 0)
+		}
+	}
+
+	if len(comps) == 1 {
+		c := comps[0]
+		for by := range c.realY {
+			for bx := range c.realX {
+				encode(0, &c.blocks[by*c.blocksX+bx])
+			}
+		}
+	} else {
+		mcusX, mcusY := comps[0].blocksX/comps[0].h, comps[0].blocksY/comps[0].v
+		for my := range mcusY {
+			for mx := range mcusX {
+				for ci, c := range comps {
+					for v := range c.v {
+						for h := range c.h {
+							encode(ci, &c.blocks[(my*c.v+v)*c.blocksX+mx*c.h+h])
+						}
+					}
+				}
+			}
+		}
+	}
+	e.flush()
+}
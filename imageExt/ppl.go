@@ -0,0 +1,376 @@
+package imageExt
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Pipeline queues image operations and applies them all in one pass over the
+// output pixels, so a chain of operations allocates a single image instead of
+// one per step. Geometric operations (Resize, Crop, Rotate, flips) are
+// combined into one mapping from output to source pixels, and filters are
+// applied in order to each sampled pixel.
+//
+//	p := imageExt.NewPipeline().Resize(800, 600).Grayscale().Encode("jpeg", 85)
+//	err := p.ProcessFile("in.png", "out.jpg")
+//
+// A Pipeline is safe for concurrent use once built.
+type Pipeline struct {
+	stages   []stage
+	filters  []func(color.RGBA) color.RGBA
+	bilinear bool
+	format   string // set by Encode; empty to follow the file extension
+	quality  int
+}
+
+// stage is a geometric operation: it computes its output size from its
+// input size and maps output coordinates back to input coordinates.
+type stage struct {
+	size    func(w, h int) (int, int)
+	inverse func(x, y float64, w, h int) (float64, float64) // w, h are the input size
+}
+
+// NewPipeline returns an empty pipeline that samples with nearest neighbor.
+func NewPipeline() *Pipeline {
+	return &Pipeline{quality: jpeg.DefaultQuality}
+}
+
+// Bilinear makes the pipeline sample with bilinear interpolation, which is
+// slower but smoother when scaling or rotating.
+func (p *Pipeline) Bilinear() *Pipeline {
+	p.bilinear = true
+	return p
+}
+
+// Resize scales the image to width by height.
+func (p *Pipeline) Resize(width, height int) *Pipeline {
+	p.stages = append(p.stages, stage{
+		size: func(int, int) (int, int) { return width, height },
+		inverse: func(x, y float64, w, h int) (float64, float64) {
+			return x * float64(w) / float64(width), y * float64(h) / float64(height)
+		},
+	})
+	return p
+}
+
+// Crop keeps the part of the image within rect, in the coordinates of the
+// image as it is at this point in the pipeline, with (0, 0) at the top left.
+func (p *Pipeline) Crop(rect image.Rectangle) *Pipeline {
+	p.stages = append(p.stages, stage{
+		size: func(w, h int) (int, int) {
+			r := rect.Intersect(image.Rect(0, 0, w, h))
+			return r.Dx(), r.Dy()
+		},
+		inverse: func(x, y float64, w, h int) (float64, float64) {
+			r := rect.Intersect(image.Rect(0, 0, w, h))
+			return x + float64(r.Min.X), y + float64(r.Min.Y)
+		},
+	})
+	return p
+}
+
+// Rotate turns the image clockwise by degrees. The canvas grows to hold the
+// rotated image and uncovered corners are transparent; multiples of 90
+// degrees rotate exactly.
+func (p *Pipeline) Rotate(degrees float64) *Pipeline {
+	theta := degrees * math.Pi / 180
+	sin, cos := math.Sincos(theta)
+	// Snap so right angles give exact sizes and pixel positions.
+	sin, cos = math.Round(sin*1e12)/1e12, math.Round(cos*1e12)/1e12
+	p.stages = append(p.stages, stage{
+		size: func(w, h int) (int, int) {
+			fw := math.Abs(float64(w)*cos) + math.Abs(float64(h)*sin)
+			fh := math.Abs(float64(w)*sin) + math.Abs(float64(h)*cos)
+			return int(math.Ceil(fw - 1e-9)), int(math.Ceil(fh - 1e-9))
+		},
+		inverse: func(x, y float64, w, h int) (float64, float64) {
+			fw := math.Abs(float64(w)*cos) + math.Abs(float64(h)*sin)
+			fh := math.Abs(float64(w)*sin) + math.Abs(float64(h)*cos)
+			dx, dy := x-math.Ceil(fw-1e-9)/2, y-math.Ceil(fh-1e-9)/2
+			return float64(w)/2 + dx*cos + dy*sin, float64(h)/2 - dx*sin + dy*cos
+		},
+	})
+	return p
+}
+
+// FlipHorizontal mirrors the image left to right.
+func (p *Pipeline) FlipHorizontal() *Pipeline {
+	p.stages = append(p.stages, stage{
+		size:    func(w, h int) (int, int) { return w, h },
+		inverse: func(x, y float64, w, h int) (float64, float64) { return float64(w) - x, y },
+	})
+	return p
+}
+
+// FlipVertical mirrors the image top to bottom.
+func (p *Pipeline) FlipVertical() *Pipeline {
+	p.stages = append(p.stages, stage{
+		size:    func(w, h int) (int, int) { return w, h },
+		inverse: func(x, y float64, w, h int) (float64, float64) { return x, float64(h) - y },
+	})
+	return p
+}
+
+// Filter adds a per-pixel color operation. Colors are alpha-premultiplied.
+func (p *Pipeline) Filter(fn func(color.RGBA) color.RGBA) *Pipeline {
+	p.filters = append(p.filters, fn)
+	return p
+}
+
+// Grayscale converts the image to shades of gray.
+func (p *Pipeline) Grayscale() *Pipeline {
+	return p.Filter(func(c color.RGBA) color.RGBA {
+		y := uint8((19595*uint32(c.R) + 38470*uint32(c.G) + 7471*uint32(c.B) + 1<<15) >> 16)
+		return color.RGBA{y, y, y, c.A}
+	})
+}
+
+// Brightness changes the brightness by percentage, from -100 to 100, like
+// AdjustBrightness.
+func (p *Pipeline) Brightness(percentage float64) *Pipeline {
+	factor := 1.0 + percentage/100.0
+	return p.Filter(func(c color.RGBA) color.RGBA {
+		return color.RGBA{scaleChannel(c.R, factor, 0, c.A), scaleChannel(c.G, factor, 0, c.A), scaleChannel(c.B, factor, 0, c.A), c.A}
+	})
+}
+
+// Contrast changes the contrast by percentage, from -100 to 100.
+func (p *Pipeline) Contrast(percentage float64) *Pipeline {
+	factor := 1.0 + percentage/100.0
+	return p.Filter(func(c color.RGBA) color.RGBA {
+		mid := float64(c.A) / 2
+		return color.RGBA{scaleChannel(c.R, factor, mid, c.A), scaleChannel(c.G, factor, mid, c.A), scaleChannel(c.B, factor, mid, c.A), c.A}
+	})
+}
+
+// Invert inverts the colors, keeping alpha.
+func (p *Pipeline) Invert() *Pipeline {
+	return p.Filter(func(c color.RGBA) color.RGBA {
+		return color.RGBA{c.A - c.R, c.A - c.G, c.A - c.B, c.A}
+	})
+}
+
+// scaleChannel scales v about mid, clamped to [0, limit] so the color stays
+// valid premultiplied.
+func scaleChannel(v uint8, factor, mid float64, limit uint8) uint8 {
+	return uint8(math.Min(math.Max((float64(v)-mid)*factor+mid, 0), float64(limit)))
+}
+
+// Encode sets the output format, "jpeg" or "png", and the JPEG quality.
+// Without it the format follows the output file's extension, or PNG.
+func (p *Pipeline) Encode(format string, quality int) *Pipeline {
+	p.format = strings.ToLower(format)
+	p.quality = quality
+	return p
+}
+
+// Apply runs the pipeline on img and returns the result.
+func (p *Pipeline) Apply(img image.Image) *image.RGBA {
+	return p.applyInto(img, nil)
+}
+
+// applyInto runs the pipeline, reusing dst's pixel buffer when it is large
+// enough.
+func (p *Pipeline) applyInto(img image.Image, dst *image.RGBA) *image.RGBA {
+	b := img.Bounds()
+	sizes := make([][2]int, len(p.stages)+1)
+	sizes[0] = [2]int{b.Dx(), b.Dy()}
+	for i, s := range p.stages {
+		w, h := s.size(sizes[i][0], sizes[i][1])
+		sizes[i+1] = [2]int{max(w, 0), max(h, 0)}
+	}
+	w, h := sizes[len(p.stages)][0], sizes[len(p.stages)][1]
+
+	rect := image.Rect(0, 0, w, h)
+	if dst != nil && cap(dst.Pix) >= 4*w*h {
+		dst.Pix = dst.Pix[:4*w*h]
+		dst.Stride = 4 * w
+		dst.Rect = rect
+	} else {
+		dst = image.NewRGBA(rect)
+	}
+
+	sample := p.sampler(img)
+	for y := 0; y < h; y++ {
+		row := dst.Pix[y*dst.Stride:]
+		for x := 0; x < w; x++ {
+			sx, sy := float64(x)+0.5, float64(y)+0.5
+			for i := len(p.stages) - 1; i >= 0; i-- {
+				sx, sy = p.stages[i].inverse(sx, sy, sizes[i][0], sizes[i][1])
+			}
+			c := sample(sx, sy)
+			for _, f := range p.filters {
+				c = f(c)
+			}
+			row[4*x], row[4*x+1], row[4*x+2], row[4*x+3] = c.R, c.G, c.B, c.A
+		}
+	}
+	return dst
+}
+
+// sampler returns a function reading img at continuous coordinates relative
+// to its top left, transparent outside the image.
+func (p *Pipeline) sampler(img image.Image) func(x, y float64) color.RGBA {
+	b := img.Bounds()
+	at := func(x, y int) color.RGBA {
+		if x < 0 || y < 0 || x >= b.Dx() || y >= b.Dy() {
+			return color.RGBA{}
+		}
+		if rgba, ok := img.(*image.RGBA); ok {
+			i := rgba.PixOffset(b.Min.X+x, b.Min.Y+y)
+			return color.RGBA{rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2], rgba.Pix[i+3]}
+		}
+		return color.RGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.RGBA)
+	}
+
+	if !p.bilinear {
+		return func(x, y float64) color.RGBA {
+			return at(int(math.Floor(x)), int(math.Floor(y)))
+		}
+	}
+	return func(x, y float64) color.RGBA {
+		x, y = x-0.5, y-0.5
+		x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+		fx, fy := x-float64(x0), y-float64(y0)
+		// Clamp to the edge so borders are not blended with transparency.
+		clampAt := func(px, py int) color.RGBA {
+			if px >= -1 && px <= b.Dx() && py >= -1 && py <= b.Dy() {
+				px, py = min(max(px, 0), b.Dx()-1), min(max(py, 0), b.Dy()-1)
+			}
+			return at(px, py)
+		}
+		c00, c10 := clampAt(x0, y0), clampAt(x0+1, y0)
+		c01, c11 := clampAt(x0, y0+1), clampAt(x0+1, y0+1)
+		mix := func(a, b, c, d uint8) uint8 {
+			top := float64(a)*(1-fx) + float64(b)*fx
+			bottom := float64(c)*(1-fx) + float64(d)*fx
+			return uint8(math.Round(top*(1-fy) + bottom*fy))
+		}
+		return color.RGBA{
+			mix(c00.R, c10.R, c01.R, c11.R),
+			mix(c00.G, c10.G, c01.G, c11.G),
+			mix(c00.B, c10.B, c01.B, c11.B),
+			mix(c00.A, c10.A, c01.A, c11.A),
+		}
+	}
+}
+
+// Process runs the pipeline on img and encodes the result to w, as PNG
+// unless Encode set another format.
+func (p *Pipeline) Process(img image.Image, w io.Writer) error {
+	return p.encode(p.Apply(img), w, cmp.Or(p.format, "png"))
+}
+
+func (p *Pipeline) encode(img image.Image, w io.Writer, format string) error {
+	switch format {
+	case "jpeg", "jpg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: p.quality})
+	case "png":
+		return png.Encode(w, img)
+	}
+	return fmt.Errorf("unsupported image format %q", format)
+}
+
+// outputFormat returns the format to write path in: the one set by Encode,
+// else the one its extension names.
+func (p *Pipeline) outputFormat(path string) string {
+	if p.format != "" {
+		return p.format
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".jpg" || ext == ".jpeg" {
+		return "jpeg"
+	}
+	return "png"
+}
+
+// ProcessFile loads src, runs the pipeline and writes the result to dst.
+func (p *Pipeline) ProcessFile(src, dst string) error {
+	img, err := LoadImage(src)
+	if err != nil {
+		return fmt.Errorf("could not load %s: %v", src, err)
+	}
+	return p.writeFile(p.Apply(img), dst)
+}
+
+// ProcessDir runs the pipeline on every JPEG and PNG file in srcDir, writing
+// results with the same names to dstDir, which is created if needed. Files
+// are processed by workers goroutines, each reusing its own output buffer.
+// It stops starting new files when ctx is done and returns the errors of all
+// failed files.
+func (p *Pipeline) ProcessDir(ctx context.Context, srcDir, dstDir string, workers int) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return err
+	}
+	workers = max(workers, 1)
+
+	names := make(chan string)
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf *image.RGBA
+			for name := range names {
+				img, err := LoadImage(filepath.Join(srcDir, name))
+				if err == nil {
+					buf = p.applyInto(img, buf)
+					err = p.writeFile(buf, filepath.Join(dstDir, name))
+				}
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("could not process %s: %v", name, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".jpg" && ext != ".jpeg" && ext != ".png") {
+			continue
+		}
+		select {
+		case names <- e.Name():
+		case <-ctx.Done():
+			mu.Lock()
+			errs = append(errs, ctx.Err())
+			mu.Unlock()
+			break feed
+		}
+	}
+	close(names)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (p *Pipeline) writeFile(img image.Image, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := p.encode(img, f, p.outputFormat(path)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
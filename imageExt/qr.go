@@ -0,0 +1,478 @@
+package imageExt
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// QRLevel is the error correction level of a QR code: how much of the code
+// can be damaged or covered and still be read.
+type QRLevel int
+
+const (
+	QRLevelL QRLevel = iota // about 7% recoverable
+	QRLevelM                // about 15% recoverable
+	QRLevelQ                // about 25% recoverable
+	QRLevelH                // about 30% recoverable
+)
+
+// qrLevelBits are the levels as written in the format information.
+var qrLevelBits = [4]int{1, 0, 3, 2}
+
+// Error correction codewords per block and number of blocks by level and
+// version, from table 9 of ISO/IEC 18004. Index 0 is unused.
+var qrECCPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrNumBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// QRCode is a QR code as a square grid of dark and light modules.
+type QRCode struct {
+	size    int
+	modules []bool
+	isFunc  []bool // finder, timing, alignment and format modules
+}
+
+// EncodeQR encodes text as a QR code in byte mode, using the smallest
+// version that fits at the given level.
+func EncodeQR(text string, level QRLevel) (*QRCode, error) {
+	if level < QRLevelL || level > QRLevelH {
+		return nil, errors.New("invalid QR error correction level")
+	}
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		countBits := 8
+		if v > 9 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*qrDataCodewords(v, level) && len(data) < 1<<countBits {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("text too long for a QR code")
+	}
+
+	// Byte mode segment, terminator and padding.
+	capacity := qrDataCodewords(version, level)
+	var bits qrBits
+	bits.append(0x4, 4)
+	if version > 9 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	bits.append(0, min(4, capacity*8-bits.n))
+	bits.append(0, (8-bits.n%8)%8)
+	for pad := 0xec; bits.n < capacity*8; pad ^= 0xec ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	size := version*4 + 17
+	q := &QRCode{size: size, modules: make([]bool, size*size), isFunc: make([]bool, size*size)}
+	q.drawFunctionPatterns(version)
+	q.drawCodewords(qrInterleave(bits.bytes, version, level))
+
+	bestMask, bestPenalty := 0, -1
+	for mask := range 8 {
+		q.applyMask(mask)
+		q.drawFormat(level, mask)
+		if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+			bestMask, bestPenalty = mask, p
+		}
+		q.applyMask(mask) // masking twice undoes it
+	}
+	q.applyMask(bestMask)
+	q.drawFormat(level, bestMask)
+	q.isFunc = nil
+	return q, nil
+}
+
+// Size returns the number of modules along each side.
+func (q *QRCode) Size() int {
+	return q.size
+}
+
+// Dark reports whether the module at column x, row y is dark. Modules
+// outside the code are light.
+func (q *QRCode) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < q.size && y < q.size && q.modules[y*q.size+x]
+}
+
+// Image renders the code with each module scale pixels wide, surrounded by
+// the four module quiet zone scanners need.
+func (q *QRCode) Image(scale int) *image.Gray {
+	scale = max(scale, 1)
+	const border = 4
+	side := (q.size + 2*border) * scale
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := range side {
+		for x := range side {
+			c := color.Gray{Y: 255}
+			if q.Dark(x/scale-border, y/scale-border) {
+				c.Y = 0
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+	return img
+}
+
+// SaveQR encodes text as a QR code and saves it as a PNG.
+func SaveQR(text string, filename string, level QRLevel, scale int) error {
+	q, err := EncodeQR(text, level)
+	if err != nil {
+		return err
+	}
+	return SavePNG(q.Image(scale), filename)
+}
+
+// qrBits is a big-endian bit buffer.
+type qrBits struct {
+	bytes []byte
+	n     int
+}
+
+func (b *qrBits) append(v, count int) {
+	for i := count - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		b.bytes[b.n/8] |= byte(v>>i&1) << (7 - b.n%8)
+		b.n++
+	}
+}
+
+// qrRawModules is the number of modules left for data and error correction
+// in a version once function patterns are placed.
+func qrRawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func qrDataCodewords(version int, level QRLevel) int {
+	return qrRawModules(version)/8 - qrECCPerBlock[level][version]*qrNumBlocks[level][version]
+}
+
+// qrInterleave splits data into blocks, adds Reed-Solomon error correction
+// to each and interleaves the results.
+func qrInterleave(data []byte, version int, level QRLevel) []byte {
+	numBlocks := qrNumBlocks[level][version]
+	eccLen := qrECCPerBlock[level][version]
+	raw := qrRawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := qrRSDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := qrRSRemainder(block, divisor)
+		if i < numShort {
+			block = append(block, 0) // placeholder so all blocks line up
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, raw)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// qrGFMul multiplies in GF(256) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func qrGFMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrRSDivisor returns the generator polynomial of the given degree, highest
+// coefficient first with the leading 1 left out.
+func qrRSDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = qrGFMul(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrGFMul(root, 2)
+	}
+	return result
+}
+
+func qrRSRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= qrGFMul(d, factor)
+		}
+	}
+	return result
+}
+
+func (q *QRCode) setFunc(x, y int, dark bool) {
+	q.modules[y*q.size+x] = dark
+	q.isFunc[y*q.size+x] = true
+}
+
+func (q *QRCode) drawFunctionPatterns(version int) {
+	for i := range q.size {
+		q.setFunc(6, i, i%2 == 0)
+		q.setFunc(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators.
+	for _, c := range [][2]int{{3, 3}, {q.size - 4, 3}, {3, q.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x >= 0 && y >= 0 && x < q.size && y < q.size {
+					d := max(abs(dx), abs(dy))
+					q.setFunc(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+
+	// Alignment patterns, except where they would overlap a finder.
+	if version > 1 {
+		numAlign := version/7 + 2
+		step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+		pos := make([]int, numAlign)
+		pos[0] = 6
+		for i, p := numAlign-1, q.size-7; i >= 1; i, p = i-1, p-step {
+			pos[i] = p
+		}
+		for i, y := range pos {
+			for j, x := range pos {
+				if i == 0 && j == 0 || i == 0 && j == numAlign-1 || i == numAlign-1 && j == 0 {
+					continue
+				}
+				for dy := -2; dy <= 2; dy++ {
+					for dx := -2; dx <= 2; dx++ {
+						q.setFunc(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+					}
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; the mask is picked later.
+	q.drawFormat(QRLevelL, 0)
+
+	if version >= 7 {
+		rem := version
+		for range 12 {
+			rem = rem<<1 ^ (rem>>11)*0x1f25
+		}
+		bits := version<<12 | rem
+		for i := range 18 {
+			dark := bits>>i&1 != 0
+			a, b := q.size-11+i%3, i/3
+			q.setFunc(a, b, dark)
+			q.setFunc(b, a, dark)
+		}
+	}
+}
+
+// drawFormat writes both copies of the format information.
+func (q *QRCode) drawFormat(level QRLevel, mask int) {
+	data := qrLevelBits[level]<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFunc(8, i, bit(i))
+	}
+	q.setFunc(8, 7, bit(6))
+	q.setFunc(8, 8, bit(7))
+	q.setFunc(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunc(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunc(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunc(8, q.size-15+i, bit(i))
+	}
+	q.setFunc(8, q.size-8, true)
+}
+
+// drawCodewords places data in the zig-zag order, two columns at a time from
+// the bottom right, skipping function modules.
+func (q *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := range q.size {
+			y := vert
+			if upward {
+				y = q.size - 1 - vert
+			}
+			for j := range 2 {
+				x := right - j
+				if !q.isFunc[y*q.size+x] && i < len(data)*8 {
+					q.modules[y*q.size+x] = data[i/8]>>(7-i%8)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by mask.
+func (q *QRCode) applyMask(mask int) {
+	for y := range q.size {
+		for x := range q.size {
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip && !q.isFunc[y*q.size+x] {
+				q.modules[y*q.size+x] = !q.modules[y*q.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan, by the rules of section
+// 7.8.3 of ISO/IEC 18004; the mask with the lowest score is used.
+func (q *QRCode) penalty() int {
+	n := q.size
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			x, y = y, x
+		}
+		return q.modules[y*n+x]
+	}
+	finderA := []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderB := []bool{false, false, false, false, true, false, true, true, true, false, true}
+
+	result := 0
+	for _, transpose := range []bool{false, true} {
+		for y := range n {
+			// Runs of five or more modules of one color.
+			run := 1
+			for x := 1; x <= n; x++ {
+				if x < n && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += run - 2
+				}
+				run = 1
+			}
+			// Patterns that look like a finder.
+			for x := 0; x+11 <= n; x++ {
+				matchA, matchB := true, true
+				for k := range 11 {
+					v := at(x+k, y, transpose)
+					matchA = matchA && v == finderA[k]
+					matchB = matchB && v == finderB[k]
+				}
+				if matchA {
+					result += 40
+				}
+				if matchB {
+					result += 40
+				}
+			}
+		}
+	}
+
+	// Two by two blocks of one color.
+	dark := 0
+	for y := range n {
+		for x := range n {
+			v := q.modules[y*n+x]
+			if v {
+				dark++
+			}
+			if x+1 < n && y+1 < n && v == q.modules[y*n+x+1] && v == q.modules[(y+1)*n+x] && v == q.modules[(y+1)*n+x+1] {
+				result += 3
+			}
+		}
+	}
+
+	// Balance of dark and light modules.
+	total := n * n
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
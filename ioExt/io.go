@@ -5,6 +5,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+
+	"github.com/C0d3-5t3w/myT00L5/bytesExt"
 )
 
 // ReadFileString reads the entire contents of a file and returns it as a string.
@@ -32,7 +34,9 @@ func WriteFileWithDirs(path string, data []byte, perm os.FileMode) error {
 // CopyWithProgress copies data from src to dst, reporting progress periodically.
 // It returns the number of bytes copied and the first error encountered, if any.
 func CopyWithProgress(dst io.Writer, src io.Reader, progressFn func(written int64)) (int64, error) {
-	buf := make([]byte, 32*1024)
+	buf := bytesExt.GetBytes(32 * 1024)
+	defer bytesExt.PutBytes(buf)
+	buf = buf[:cap(buf)]
 	var written int64
 
 	for {
@@ -171,3 +171,19 @@ func OrderedMin[T cmp.Ordered](seq iter.Seq[T]) (T, bool) {
 func OrderedMax[T cmp.Ordered](seq iter.Seq[T]) (T, bool) {
 	return Max(seq, func(a, b T) bool { return a < b })
 }
+
+// StopOnError returns the values of a sequence of value and error pairs,
+// stopping at the first non-nil error and storing it in *err.
+func StopOnError[T any](seq iter.Seq2[T, error], err *error) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v, e := range seq {
+			if e != nil {
+				*err = e
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -7,8 +7,13 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/C0d3-5t3w/myT00L5/encodingExt"
 )
 
 // Log levels
@@ -35,6 +40,41 @@ type Logger struct {
 	stdLogger  *log.Logger
 	timeFormat string
 	showCaller bool
+	logfmt     bool
+	name       string      // set for loggers from Named
+	sink       func(Entry) // receives entries instead of the output, if set
+	mu         sync.Mutex  // serializes logfmt writes, which bypass stdLogger
+}
+
+// Entry is a logged message, as passed to the function of NewEntryLogger
+type Entry struct {
+	Time    time.Time
+	Level   int
+	Logger  string // name of the logger from Named, if any
+	Message string
+	Caller  string // file:line, if the logger shows callers
+}
+
+// String formats the entry as LEVEL | logger | message | caller, leaving out
+// the parts that are empty
+func (e Entry) String() string {
+	parts := []string{LevelName(e.Level)}
+	if e.Logger != "" {
+		parts = append(parts, e.Logger)
+	}
+	parts = append(parts, e.Message)
+	if e.Caller != "" {
+		parts = append(parts, e.Caller)
+	}
+	return strings.Join(parts, " | ")
+}
+
+// LevelName returns the name of a level, such as "INFO"
+func LevelName(level int) string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return "LEVEL(" + strconv.Itoa(level) + ")"
 }
 
 // NewLogger creates a new Logger instance
@@ -50,13 +90,25 @@ func NewLogger(out io.Writer, prefix string, flag int, level int) *Logger {
 	}
 }
 
+// NewEntryLogger creates a Logger that passes every entry, from DEBUG up and
+// with its caller, to fn instead of writing it. testingExt uses it to capture
+// logs in tests.
+func NewEntryLogger(fn func(Entry)) *Logger {
+	return &Logger{level: DEBUG, showCaller: true, sink: fn}
+}
+
 // DefaultLogger returns a logger with sensible defaults
 func DefaultLogger() *Logger {
 	return NewLogger(os.Stderr, "", log.LstdFlags, INFO)
 }
 
 // SetLevel changes the current logging level
+// For a logger from Named it sets the override for the logger's name
 func (l *Logger) SetLevel(level int) {
+	if l.name != "" {
+		SetLevelFor(l.name, level)
+		return
+	}
 	l.level = level
 }
 
@@ -70,12 +122,54 @@ func (l *Logger) SetShowCaller(show bool) {
 	l.showCaller = show
 }
 
+// SetLogfmt switches output to logfmt records such as
+// time=2024-01-02T15:04:05Z level=INFO msg="started" caller=main.go:12,
+// written without the standard logger's prefix and flags.
+func (l *Logger) SetLogfmt(enabled bool) {
+	l.logfmt = enabled
+}
+
+// formatLogfmt formats a log message as a logfmt record
+func (l *Logger) formatLogfmt(level int, name, msg string) []byte {
+	fields := []encodingExt.LogfmtField{
+		{Key: "time", Value: time.Now().Format(time.RFC3339)},
+		{Key: "level", Value: levelNames[level]},
+	}
+	if name != "" {
+		fields = append(fields, encodingExt.LogfmtField{Key: "logger", Value: name})
+	}
+	fields = append(fields, encodingExt.LogfmtField{Key: "msg", Value: msg})
+	if l.showCaller {
+		if caller := externalCaller(); caller != "" {
+			fields = append(fields, encodingExt.LogfmtField{Key: "caller", Value: caller})
+		}
+	}
+	return append(encodingExt.AppendLogfmt(nil, fields...), '\n')
+}
+
+// externalCaller returns file:line of the first caller outside this package
+func externalCaller() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/C0d3-5t3w/myT00L5/logExt.") {
+			return fmt.Sprintf("%s:%d", frame.File[strings.LastIndex(frame.File, "/")+1:], frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
 // formatMessage formats a log message with level, timestamp and caller info if enabled
-func (l *Logger) formatMessage(level int, v ...interface{}) string {
+func (l *Logger) formatMessage(level int, name, msg string) string {
 	ts := time.Now().Format(l.timeFormat)
 	levelName := levelNames[level]
-	msg := fmt.Sprint(v...)
 	parts := []string{ts, levelName, msg}
+	if name != "" {
+		parts = []string{ts, levelName, name, msg}
+	}
 
 	if l.showCaller {
 		_, file, line, ok := runtime.Caller(2) // Skip two frames to get the actual caller
@@ -92,15 +186,39 @@ func (l *Logger) formatMessage(level int, v ...interface{}) string {
 
 // log logs a message at the specified level
 func (l *Logger) log(level int, v ...interface{}) {
-	if level >= l.level {
-		l.stdLogger.Println(l.formatMessage(level, v...))
+	if level >= l.effectiveLevel() {
+		l.output(level, fmt.Sprint(v...))
 	}
 }
 
 // logf logs a formatted message at the specified level
 func (l *Logger) logf(level int, format string, v ...interface{}) {
-	if level >= l.level {
-		l.log(level, fmt.Sprintf(format, v...))
+	if level >= l.effectiveLevel() {
+		l.output(level, fmt.Sprintf(format, v...))
+	}
+}
+
+// output writes a message that passed the level check. Loggers from Named
+// write through the current default logger.
+func (l *Logger) output(level int, msg string) {
+	out := l
+	if l.name != "" {
+		out = defaultLogger()
+	}
+	switch {
+	case out.sink != nil:
+		entry := Entry{Time: time.Now(), Level: level, Logger: l.name, Message: msg}
+		if out.showCaller {
+			entry.Caller = externalCaller()
+		}
+		out.sink(entry)
+	case out.logfmt:
+		record := out.formatLogfmt(level, l.name, msg)
+		out.mu.Lock()
+		out.stdLogger.Writer().Write(record)
+		out.mu.Unlock()
+	default:
+		out.stdLogger.Println(out.formatMessage(level, l.name, msg))
 	}
 }
 
@@ -156,77 +274,97 @@ func (l *Logger) Fatalf(format string, v ...interface{}) {
 	os.Exit(1)
 }
 
-// Global logger instance for package-level functions
-var defaultLogger = DefaultLogger()
+// Global logger instance for package-level functions, swapped atomically so
+// tests can install a capturing logger while other goroutines log
+var globalLogger atomic.Pointer[Logger]
+
+func init() {
+	globalLogger.Store(DefaultLogger())
+}
+
+// defaultLogger returns the global default logger
+func defaultLogger() *Logger {
+	return globalLogger.Load()
+}
+
+// GetDefaultLogger returns the global default logger
+func GetDefaultLogger() *Logger {
+	return defaultLogger()
+}
 
 // SetDefaultLogger changes the global default logger
 func SetDefaultLogger(logger *Logger) {
-	defaultLogger = logger
+	globalLogger.Store(logger)
 }
 
 // Global functions that use the default logger
 
 // Debug logs a message at DEBUG level using the default logger
 func Debug(v ...interface{}) {
-	defaultLogger.Debug(v...)
+	defaultLogger().Debug(v...)
 }
 
 // Debugf logs a formatted message at DEBUG level using the default logger
 func Debugf(format string, v ...interface{}) {
-	defaultLogger.Debugf(format, v...)
+	defaultLogger().Debugf(format, v...)
 }
 
 // Info logs a message at INFO level using the default logger
 func Info(v ...interface{}) {
-	defaultLogger.Info(v...)
+	defaultLogger().Info(v...)
 }
 
 // Infof logs a formatted message at INFO level using the default logger
 func Infof(format string, v ...interface{}) {
-	defaultLogger.Infof(format, v...)
+	defaultLogger().Infof(format, v...)
 }
 
 // Warn logs a message at WARN level using the default logger
 func Warn(v ...interface{}) {
-	defaultLogger.Warn(v...)
+	defaultLogger().Warn(v...)
 }
 
 // Warnf logs a formatted message at WARN level using the default logger
 func Warnf(format string, v ...interface{}) {
-	defaultLogger.Warnf(format, v...)
+	defaultLogger().Warnf(format, v...)
 }
 
 // Error logs a message at ERROR level using the default logger
 func Error(v ...interface{}) {
-	defaultLogger.Error(v...)
+	defaultLogger().Error(v...)
 }
 
 // Errorf logs a formatted message at ERROR level using the default logger
 func Errorf(format string, v ...interface{}) {
-	defaultLogger.Errorf(format, v...)
+	defaultLogger().Errorf(format, v...)
 }
 
 // Fatal logs a message at FATAL level using the default logger and then exits
 func Fatal(v ...interface{}) {
-	defaultLogger.Fatal(v...)
+	defaultLogger().Fatal(v...)
 }
 
 // Fatalf logs a formatted message at FATAL level using the default logger and then exits
 func Fatalf(format string, v ...interface{}) {
-	defaultLogger.Fatalf(format, v...)
+	defaultLogger().Fatalf(format, v...)
 }
 
 // SetLevel sets the level of the default logger
 func SetLevel(level int) {
-	defaultLogger.SetLevel(level)
+	defaultLogger().SetLevel(level)
 }
 
 // SetTimeFormat sets the time format of the default logger
 func SetTimeFormat(format string) {
-	defaultLogger.SetTimeFormat(format)
+	defaultLogger().SetTimeFormat(format)
+}
+
+// SetLogfmt switches the default logger to logfmt output
+func SetLogfmt(enabled bool) {
+	defaultLogger().SetLogfmt(enabled)
 }
 
 // SetShowCaller enables/disables showing caller info in the default logger
 func SetShowCaller(show bool) {
-	defaultLogger.SetShowCaller(show)
+	defaultLogger().SetShowCaller(show)
 }
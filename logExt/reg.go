@@ -0,0 +1,157 @@
+package logExt
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LevelsEnv is the environment variable read by SetLevelsFromEnv.
+const LevelsEnv = "LOG_LEVELS"
+
+// Named loggers and their level overrides.
+var registry = struct {
+	sync.RWMutex
+	loggers   map[string]*Logger
+	overrides map[string]int
+}{
+	loggers:   make(map[string]*Logger),
+	overrides: make(map[string]int),
+}
+
+// Named returns the logger for a subsystem, such as "nt" or "nt.http".
+// Its messages carry the name and are written through the current default
+// logger, with its output and settings. It logs at the level set for its
+// name with SetLevelFor, else the level set for the nearest parent name
+// ("nt" for "nt.http"), else the default logger's level.
+func Named(name string) *Logger {
+	registry.RLock()
+	l, ok := registry.loggers[name]
+	registry.RUnlock()
+	if ok {
+		return l
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if l, ok := registry.loggers[name]; ok {
+		return l
+	}
+	l = &Logger{name: name}
+	registry.loggers[name] = l
+	return l
+}
+
+// SetLevelFor sets the level of the named logger and its children, whether
+// or not they exist yet.
+func SetLevelFor(name string, level int) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.overrides[name] = level
+}
+
+// ClearLevelFor removes the level set for name, so the logger follows its
+// parent or the default logger again.
+func ClearLevelFor(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.overrides, name)
+}
+
+// LevelFor returns the level the named logger logs at.
+func LevelFor(name string) int {
+	if level, ok := levelOverride(name); ok {
+		return level
+	}
+	return defaultLogger().level
+}
+
+// levelOverride returns the level set for name or its nearest parent.
+func levelOverride(name string) (int, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for {
+		if level, ok := registry.overrides[name]; ok {
+			return level, true
+		}
+		i := strings.LastIndexAny(name, "./")
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// effectiveLevel returns the level the logger logs at
+func (l *Logger) effectiveLevel() int {
+	if l.name == "" {
+		return l.level
+	}
+	return LevelFor(l.name)
+}
+
+// ParseLevel parses a level name such as "debug" or "WARN", or a level
+// number.
+func ParseLevel(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	for level, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return level, nil
+		}
+	}
+	if strings.EqualFold(s, "warning") {
+		return WARN, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= DEBUG && n <= FATAL {
+		return n, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+// SetLevels applies a comma-separated list of levels such as
+// "nt=debug,imageExt=warn". An entry without a name, such as "info", sets
+// the default logger's level. Nothing is applied if any entry is invalid.
+func SetLevels(spec string) error {
+	defaultLevel := -1
+	overrides := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, levelName, found := strings.Cut(entry, "=")
+		if !found {
+			name, levelName = "", name
+		}
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return fmt.Errorf("could not parse log levels %q: %v", spec, err)
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			defaultLevel = level
+			continue
+		}
+		overrides[name] = level
+	}
+
+	if defaultLevel >= 0 {
+		defaultLogger().SetLevel(defaultLevel)
+	}
+	for name, level := range overrides {
+		SetLevelFor(name, level)
+	}
+	return nil
+}
+
+// SetLevelsFromEnv applies SetLevels to the LOG_LEVELS environment
+// variable, if it is set.
+func SetLevelsFromEnv() error {
+	spec, ok := os.LookupEnv(LevelsEnv)
+	if !ok {
+		return nil
+	}
+	return SetLevels(spec)
+}
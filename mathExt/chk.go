@@ -0,0 +1,101 @@
+package mathExt
+
+import "unsafe"
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// AddChecked returns a + b and whether the result fits in T without overflow.
+func AddChecked[T Integer](a, b T) (T, bool) {
+	c := a + b
+	if isSigned[T]() {
+		// Overflow iff both operands have the same sign and the result's sign differs
+		return c, (c > a) == (b > 0)
+	}
+	return c, c >= a
+}
+
+// SubChecked returns a - b and whether the result fits in T without overflow.
+func SubChecked[T Integer](a, b T) (T, bool) {
+	c := a - b
+	if isSigned[T]() {
+		return c, (c < a) == (b > 0)
+	}
+	return c, a >= b
+}
+
+// MulChecked returns a * b and whether the result fits in T without overflow.
+func MulChecked[T Integer](a, b T) (T, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if isSigned[T]() {
+		// minInt / -1 wraps silently in Go, so check it explicitly
+		lo := minOf[T]()
+		if (a == lo && b == ^T(0)) || (b == lo && a == ^T(0)) {
+			return c, false
+		}
+	}
+	return c, c/b == a
+}
+
+// AddSaturating returns a + b, clamped to the range of T.
+func AddSaturating[T Integer](a, b T) T {
+	if c, ok := AddChecked(a, b); ok {
+		return c
+	}
+	if isSigned[T]() && b < 0 {
+		return minOf[T]()
+	}
+	return maxOf[T]()
+}
+
+// SubSaturating returns a - b, clamped to the range of T.
+func SubSaturating[T Integer](a, b T) T {
+	if c, ok := SubChecked(a, b); ok {
+		return c
+	}
+	if !isSigned[T]() || b > 0 {
+		return minOf[T]()
+	}
+	return maxOf[T]()
+}
+
+// MulSaturating returns a * b, clamped to the range of T.
+func MulSaturating[T Integer](a, b T) T {
+	if c, ok := MulChecked(a, b); ok {
+		return c
+	}
+	if isSigned[T]() && (a < 0) != (b < 0) {
+		return minOf[T]()
+	}
+	return maxOf[T]()
+}
+
+// isSigned reports whether T is a signed integer type.
+func isSigned[T Integer]() bool {
+	var zero T
+	return ^zero < 0
+}
+
+// maxOf returns the largest value representable by T.
+func maxOf[T Integer]() T {
+	if isSigned[T]() {
+		return ^minOf[T]()
+	}
+	var zero T
+	return ^zero
+}
+
+// minOf returns the smallest value representable by T.
+func minOf[T Integer]() T {
+	if !isSigned[T]() {
+		return 0
+	}
+	var zero T
+	return T(1) << (8*unsafe.Sizeof(zero) - 1)
+}
@@ -0,0 +1,172 @@
+package mathExt
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Sampler draws values from common probability distributions. Tests and
+// simulations can inject a seeded source for reproducible results.
+// A Sampler is not safe for concurrent use unless its source is.
+type Sampler struct {
+	rng *rand.Rand
+}
+
+// NewSampler creates a Sampler that draws from src. A nil src uses the
+// goroutine-safe, randomly seeded global source.
+func NewSampler(src rand.Source) *Sampler {
+	if src == nil {
+		return &Sampler{}
+	}
+	return &Sampler{rng: rand.New(src)}
+}
+
+// NewSeededSampler creates a Sampler with a deterministic PCG source.
+func NewSeededSampler(seed uint64) *Sampler {
+	return NewSampler(rand.NewPCG(seed, seed))
+}
+
+func (s *Sampler) float64() float64 {
+	if s.rng == nil {
+		return rand.Float64()
+	}
+	return s.rng.Float64()
+}
+
+func (s *Sampler) uint64() uint64 {
+	if s.rng == nil {
+		return rand.Uint64()
+	}
+	return s.rng.Uint64()
+}
+
+// Uniform returns a value uniformly distributed in [min, max).
+func (s *Sampler) Uniform(min, max float64) float64 {
+	return min + s.float64()*(max-min)
+}
+
+// IntBetween returns an integer uniformly distributed in [min, max].
+// It panics if max < min.
+func (s *Sampler) IntBetween(min, max int) int {
+	if max < min {
+		panic("mathExt: IntBetween max is less than min")
+	}
+	// Work in uint64 so that the full int range does not overflow
+	span := uint64(max) - uint64(min)
+	var n uint64
+	switch {
+	case span == math.MaxUint64:
+		n = s.uint64()
+	case s.rng == nil:
+		n = rand.Uint64N(span + 1)
+	default:
+		n = s.rng.Uint64N(span + 1)
+	}
+	return int(uint64(min) + n)
+}
+
+// Normal returns a normally distributed value with the given mean and standard deviation.
+func (s *Sampler) Normal(mean, stddev float64) float64 {
+	if s.rng == nil {
+		return mean + stddev*rand.NormFloat64()
+	}
+	return mean + stddev*s.rng.NormFloat64()
+}
+
+// Exponential returns an exponentially distributed value with the given rate
+// (events per unit time), so the mean is 1/rate. It panics if rate <= 0.
+func (s *Sampler) Exponential(rate float64) float64 {
+	if rate <= 0 {
+		panic("mathExt: Exponential rate must be positive")
+	}
+	if s.rng == nil {
+		return rand.ExpFloat64() / rate
+	}
+	return s.rng.ExpFloat64() / rate
+}
+
+// Poisson returns a Poisson distributed count with mean lambda.
+// It panics if lambda < 0.
+func (s *Sampler) Poisson(lambda float64) int {
+	switch {
+	case lambda < 0 || math.IsNaN(lambda):
+		panic("mathExt: Poisson lambda must be non-negative")
+	case lambda == 0:
+		return 0
+	case lambda < 10:
+		return s.poissonSmall(lambda)
+	default:
+		return s.poissonLarge(lambda)
+	}
+}
+
+// poissonSmall uses Knuth's multiplication method, which needs about lambda
+// uniform draws per sample.
+func (s *Sampler) poissonSmall(lambda float64) int {
+	limit := math.Exp(-lambda)
+	k := 0
+	for p := s.float64(); p > limit; p *= s.float64() {
+		k++
+	}
+	return k
+}
+
+// poissonLarge uses Hörmann's transformed rejection with squeeze (PTRS),
+// which runs in constant expected time regardless of lambda.
+func (s *Sampler) poissonLarge(lambda float64) int {
+	slam := math.Sqrt(lambda)
+	logLam := math.Log(lambda)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+
+	for {
+		u := s.float64() - 0.5
+		v := s.float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + 0.43)
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invAlpha)-math.Log(a/(us*us)+b) <= -lambda+k*logLam-lg {
+			return int(k)
+		}
+	}
+}
+
+// defaultSampler draws from the global source.
+var defaultSampler = NewSampler(nil)
+
+// RandomFloatBetween returns a value uniformly distributed in [min, max)
+// using the global random source.
+func RandomFloatBetween(min, max float64) float64 {
+	return defaultSampler.Uniform(min, max)
+}
+
+// RandomIntBetween returns an integer uniformly distributed in [min, max]
+// using the global random source. It panics if max < min.
+func RandomIntBetween(min, max int) int {
+	return defaultSampler.IntBetween(min, max)
+}
+
+// RandomNormal returns a normally distributed value using the global random source.
+func RandomNormal(mean, stddev float64) float64 {
+	return defaultSampler.Normal(mean, stddev)
+}
+
+// RandomExponential returns an exponentially distributed value with the given
+// rate using the global random source.
+func RandomExponential(rate float64) float64 {
+	return defaultSampler.Exponential(rate)
+}
+
+// RandomPoisson returns a Poisson distributed count with mean lambda using
+// the global random source.
+func RandomPoisson(lambda float64) int {
+	return defaultSampler.Poisson(lambda)
+}
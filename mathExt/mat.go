@@ -0,0 +1,385 @@
+package mathExt
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Vector is a dense column vector.
+type Vector []float64
+
+// Add returns v + w.
+func (v Vector) Add(w Vector) (Vector, error) {
+	if len(v) != len(w) {
+		return nil, fmt.Errorf("%w: vectors of length %d and %d", ErrDimensionMismatch, len(v), len(w))
+	}
+	out := make(Vector, len(v))
+	for i := range v {
+		out[i] = v[i] + w[i]
+	}
+	return out, nil
+}
+
+// Sub returns v - w.
+func (v Vector) Sub(w Vector) (Vector, error) {
+	if len(v) != len(w) {
+		return nil, fmt.Errorf("%w: vectors of length %d and %d", ErrDimensionMismatch, len(v), len(w))
+	}
+	out := make(Vector, len(v))
+	for i := range v {
+		out[i] = v[i] - w[i]
+	}
+	return out, nil
+}
+
+// Scale returns v multiplied by s.
+func (v Vector) Scale(s float64) Vector {
+	out := make(Vector, len(v))
+	for i := range v {
+		out[i] = v[i] * s
+	}
+	return out
+}
+
+// Dot returns the dot product of v and w.
+func (v Vector) Dot(w Vector) (float64, error) {
+	if len(v) != len(w) {
+		return 0, fmt.Errorf("%w: vectors of length %d and %d", ErrDimensionMismatch, len(v), len(w))
+	}
+	var sum float64
+	for i := range v {
+		sum += v[i] * w[i]
+	}
+	return sum, nil
+}
+
+// Norm returns the Euclidean length of v.
+func (v Vector) Norm() float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// Matrix is a dense row-major matrix of float64 values.
+type Matrix struct {
+	rows, cols int
+	data       []float64
+}
+
+// NewMatrix creates a rows×cols matrix of zeros.
+func NewMatrix(rows, cols int) *Matrix {
+	if rows < 0 || cols < 0 {
+		panic("mathExt: negative matrix dimension")
+	}
+	return &Matrix{rows: rows, cols: cols, data: make([]float64, rows*cols)}
+}
+
+// MatrixFromRows creates a matrix from a slice of rows, which must all have
+// the same length. The values are copied.
+func MatrixFromRows(rows [][]float64) (*Matrix, error) {
+	if len(rows) == 0 {
+		return NewMatrix(0, 0), nil
+	}
+	m := NewMatrix(len(rows), len(rows[0]))
+	for i, row := range rows {
+		if len(row) != m.cols {
+			return nil, fmt.Errorf("%w: row %d has %d columns, expected %d", ErrDimensionMismatch, i, len(row), m.cols)
+		}
+		copy(m.data[i*m.cols:], row)
+	}
+	return m, nil
+}
+
+// Identity creates the n×n identity matrix.
+func Identity(n int) *Matrix {
+	m := NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+// Dims returns the number of rows and columns.
+func (m *Matrix) Dims() (rows, cols int) {
+	return m.rows, m.cols
+}
+
+// At returns the element at row i, column j.
+func (m *Matrix) At(i, j int) float64 {
+	m.checkIndex(i, j)
+	return m.data[i*m.cols+j]
+}
+
+// Set sets the element at row i, column j.
+func (m *Matrix) Set(i, j int, v float64) {
+	m.checkIndex(i, j)
+	m.data[i*m.cols+j] = v
+}
+
+func (m *Matrix) checkIndex(i, j int) {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("mathExt: index (%d, %d) out of range for %dx%d matrix", i, j, m.rows, m.cols))
+	}
+}
+
+// Row returns a copy of row i.
+func (m *Matrix) Row(i int) Vector {
+	m.checkIndex(i, 0)
+	out := make(Vector, m.cols)
+	copy(out, m.data[i*m.cols:(i+1)*m.cols])
+	return out
+}
+
+// Col returns a copy of column j.
+func (m *Matrix) Col(j int) Vector {
+	m.checkIndex(0, j)
+	out := make(Vector, m.rows)
+	for i := range out {
+		out[i] = m.data[i*m.cols+j]
+	}
+	return out
+}
+
+// Clone returns a deep copy of m.
+func (m *Matrix) Clone() *Matrix {
+	c := NewMatrix(m.rows, m.cols)
+	copy(c.data, m.data)
+	return c
+}
+
+// Add returns m + n.
+func (m *Matrix) Add(n *Matrix) (*Matrix, error) {
+	if m.rows != n.rows || m.cols != n.cols {
+		return nil, fmt.Errorf("%w: cannot add %dx%d and %dx%d", ErrDimensionMismatch, m.rows, m.cols, n.rows, n.cols)
+	}
+	out := NewMatrix(m.rows, m.cols)
+	for i := range m.data {
+		out.data[i] = m.data[i] + n.data[i]
+	}
+	return out, nil
+}
+
+// Sub returns m - n.
+func (m *Matrix) Sub(n *Matrix) (*Matrix, error) {
+	if m.rows != n.rows || m.cols != n.cols {
+		return nil, fmt.Errorf("%w: cannot subtract %dx%d and %dx%d", ErrDimensionMismatch, m.rows, m.cols, n.rows, n.cols)
+	}
+	out := NewMatrix(m.rows, m.cols)
+	for i := range m.data {
+		out.data[i] = m.data[i] - n.data[i]
+	}
+	return out, nil
+}
+
+// Scale returns m multiplied by s.
+func (m *Matrix) Scale(s float64) *Matrix {
+	out := NewMatrix(m.rows, m.cols)
+	for i := range m.data {
+		out.data[i] = m.data[i] * s
+	}
+	return out
+}
+
+// Mul returns the matrix product m·n.
+func (m *Matrix) Mul(n *Matrix) (*Matrix, error) {
+	if m.cols != n.rows {
+		return nil, fmt.Errorf("%w: cannot multiply %dx%d by %dx%d", ErrDimensionMismatch, m.rows, m.cols, n.rows, n.cols)
+	}
+	out := NewMatrix(m.rows, n.cols)
+	for i := 0; i < m.rows; i++ {
+		for k := 0; k < m.cols; k++ {
+			a := m.data[i*m.cols+k]
+			if a == 0 {
+				continue
+			}
+			for j := 0; j < n.cols; j++ {
+				out.data[i*n.cols+j] += a * n.data[k*n.cols+j]
+			}
+		}
+	}
+	return out, nil
+}
+
+// MulVec returns the matrix-vector product m·v.
+func (m *Matrix) MulVec(v Vector) (Vector, error) {
+	if m.cols != len(v) {
+		return nil, fmt.Errorf("%w: cannot multiply %dx%d by vector of length %d", ErrDimensionMismatch, m.rows, m.cols, len(v))
+	}
+	out := make(Vector, m.rows)
+	for i := range out {
+		var sum float64
+		for j, x := range v {
+			sum += m.data[i*m.cols+j] * x
+		}
+		out[i] = sum
+	}
+	return out, nil
+}
+
+// Transpose returns the transpose of m.
+func (m *Matrix) Transpose() *Matrix {
+	out := NewMatrix(m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			out.data[j*m.rows+i] = m.data[i*m.cols+j]
+		}
+	}
+	return out
+}
+
+// Determinant returns the determinant of a square matrix.
+func (m *Matrix) Determinant() (float64, error) {
+	if m.rows != m.cols {
+		return 0, fmt.Errorf("%w: determinant of non-square %dx%d matrix", ErrDimensionMismatch, m.rows, m.cols)
+	}
+	f, err := m.decompose()
+	if err == ErrSingular {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	det := float64(f.sign)
+	for i := 0; i < m.rows; i++ {
+		det *= f.lu.data[i*m.cols+i]
+	}
+	return det, nil
+}
+
+// Inverse returns the inverse of a square matrix, or ErrSingular if it has none.
+func (m *Matrix) Inverse() (*Matrix, error) {
+	if m.rows != m.cols {
+		return nil, fmt.Errorf("%w: inverse of non-square %dx%d matrix", ErrDimensionMismatch, m.rows, m.cols)
+	}
+	f, err := m.decompose()
+	if err != nil {
+		return nil, err
+	}
+
+	n := m.rows
+	out := NewMatrix(n, n)
+	e := make(Vector, n)
+	for j := 0; j < n; j++ {
+		clear(e)
+		e[j] = 1
+		col := f.solve(e)
+		for i := 0; i < n; i++ {
+			out.data[i*n+j] = col[i]
+		}
+	}
+	return out, nil
+}
+
+// Solve solves m·x = b for x using Gaussian elimination with partial pivoting.
+// m must be square; ErrSingular is returned if there is no unique solution.
+func (m *Matrix) Solve(b Vector) (Vector, error) {
+	if m.rows != m.cols {
+		return nil, fmt.Errorf("%w: cannot solve non-square %dx%d system", ErrDimensionMismatch, m.rows, m.cols)
+	}
+	if len(b) != m.rows {
+		return nil, fmt.Errorf("%w: %dx%d system with %d right-hand values", ErrDimensionMismatch, m.rows, m.cols, len(b))
+	}
+	f, err := m.decompose()
+	if err != nil {
+		return nil, err
+	}
+	return f.solve(b), nil
+}
+
+// String formats the matrix one row per line.
+func (m *Matrix) String() string {
+	var sb strings.Builder
+	for i := 0; i < m.rows; i++ {
+		sb.WriteByte('[')
+		for j := 0; j < m.cols; j++ {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprintf(&sb, "%g", m.data[i*m.cols+j])
+		}
+		sb.WriteString("]\n")
+	}
+	return sb.String()
+}
+
+// luFactors holds an LU decomposition PA = LU, with L's unit diagonal implied.
+type luFactors struct {
+	lu   *Matrix
+	perm []int // perm[i] is the row of A that ended up in row i
+	sign int   // +1 or -1 depending on the parity of row swaps
+}
+
+// decompose factors a square matrix using partial pivoting.
+func (m *Matrix) decompose() (*luFactors, error) {
+	n := m.rows
+	lu := m.Clone()
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign := 1
+
+	// Pivots at or below n·ε·max|a_ij| are rounding noise, so the matrix is
+	// treated as singular. Scaling by the entries keeps small but well
+	// conditioned matrices, such as 1e-7·I, usable.
+	var norm float64
+	for _, v := range m.data {
+		norm = math.Max(norm, math.Abs(v))
+	}
+	tol := float64(n) * 0x1p-52 * norm
+
+	for col := 0; col < n; col++ {
+		// Pick the row with the largest pivot for numerical stability
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(lu.data[row*n+col]) > math.Abs(lu.data[pivot*n+col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(lu.data[pivot*n+col]) <= tol {
+			return nil, ErrSingular
+		}
+		if pivot != col {
+			for k := 0; k < n; k++ {
+				lu.data[col*n+k], lu.data[pivot*n+k] = lu.data[pivot*n+k], lu.data[col*n+k]
+			}
+			perm[col], perm[pivot] = perm[pivot], perm[col]
+			sign = -sign
+		}
+
+		for row := col + 1; row < n; row++ {
+			f := lu.data[row*n+col] / lu.data[col*n+col]
+			lu.data[row*n+col] = f
+			for k := col + 1; k < n; k++ {
+				lu.data[row*n+k] -= f * lu.data[col*n+k]
+			}
+		}
+	}
+	return &luFactors{lu: lu, perm: perm, sign: sign}, nil
+}
+
+// solve performs forward and back substitution for one right-hand side.
+func (f *luFactors) solve(b Vector) Vector {
+	n := f.lu.rows
+	a := f.lu.data
+	x := make(Vector, n)
+	for i := 0; i < n; i++ {
+		sum := b[f.perm[i]]
+		for j := 0; j < i; j++ {
+			sum -= a[i*n+j] * x[j]
+		}
+		x[i] = sum
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for j := i + 1; j < n; j++ {
+			sum -= a[i*n+j] * x[j]
+		}
+		x[i] = sum / a[i*n+i]
+	}
+	return x
+}
@@ -0,0 +1,59 @@
+package mathExt
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDeterminantScale(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix
+		want float64
+	}{
+		{"identity", Identity(3), 1},
+		{"small identity", Identity(3).Scale(1e-7), 1e-21},
+		{"small", mustMatrix(t, [][]float64{{2e-9, 1e-9}, {1e-9, 3e-9}}), 5e-18},
+		{"large", mustMatrix(t, [][]float64{{2e9, 1e9}, {1e9, 3e9}}), 5e18},
+		{"singular", mustMatrix(t, [][]float64{{1, 2}, {2, 4}}), 0},
+		{"small singular", mustMatrix(t, [][]float64{{1e-9, 2e-9}, {2e-9, 4e-9}}), 0},
+		{"zero", NewMatrix(2, 2), 0},
+	}
+	for _, tt := range tests {
+		got, err := tt.m.Determinant()
+		if err != nil {
+			t.Errorf("%s: Determinant(): %v", tt.name, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9*math.Abs(tt.want) {
+			t.Errorf("%s: Determinant() = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSolveSmallScale(t *testing.T) {
+	m := Identity(3).Scale(1e-7)
+	x, err := m.Solve(Vector{1e-7, 2e-7, 3e-7})
+	if err != nil {
+		t.Fatalf("Solve on 1e-7·I: %v", err)
+	}
+	for i, want := range []float64{1, 2, 3} {
+		if math.Abs(x[i]-want) > 1e-12 {
+			t.Errorf("x[%d] = %g, want %g", i, x[i], want)
+		}
+	}
+
+	if _, err := mustMatrix(t, [][]float64{{1e-9, 2e-9}, {2e-9, 4e-9}}).Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("Inverse of a small singular matrix: error = %v, want ErrSingular", err)
+	}
+}
+
+func mustMatrix(t *testing.T, rows [][]float64) *Matrix {
+	t.Helper()
+	m, err := MatrixFromRows(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
@@ -61,7 +61,8 @@ func StandardDeviation(values []float64) float64 {
 	return math.Sqrt(sum / float64(len(values)))
 }
 
-// Factorial calculates the factorial of n.
+// Factorial calculates the factorial of n. The result wraps silently for
+// n > 20; use FactorialChecked to detect overflow.
 func Factorial(n int) int {
 	if n <= 0 {
 		return 1
@@ -74,6 +75,7 @@ func Factorial(n int) int {
 }
 
 // Combination calculates the number of ways to choose k items from n items without repetition and without order.
+// Large inputs overflow silently; use CombinationChecked to detect overflow.
 func Combination(n, k int) int {
 	if k > n || k < 0 {
 		return 0
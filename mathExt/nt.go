@@ -0,0 +1,259 @@
+package mathExt
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/bits"
+)
+
+// ErrOverflow is returned when a result does not fit in the target integer type.
+var ErrOverflow = errors.New("mathExt: integer overflow")
+
+// FactorialChecked calculates n! and returns ErrOverflow if it does not fit
+// in an int. Factorial wraps silently instead.
+func FactorialChecked(n int) (int, error) {
+	result := 1
+	for i := 2; i <= n; i++ {
+		var ok bool
+		if result, ok = MulChecked(result, i); !ok {
+			return 0, fmt.Errorf("%w: %d!", ErrOverflow, n)
+		}
+	}
+	return result, nil
+}
+
+// CombinationChecked calculates n choose k and returns ErrOverflow if the
+// result does not fit in an int. Intermediate products are reduced so that
+// only a result that really overflows is reported.
+func CombinationChecked(n, k int) (int, error) {
+	if k > n || k < 0 {
+		return 0, nil
+	}
+	if k > n-k {
+		k = n - k
+	}
+
+	result := 1
+	for i := 1; i <= k; i++ {
+		// result·(n-k+i)/i is always exact; divide out common factors first
+		g := GCD(result, i)
+		factor := (n - k + i) / (i / g)
+		var ok bool
+		if result, ok = MulChecked(result/g, factor); !ok {
+			return 0, fmt.Errorf("%w: C(%d, %d)", ErrOverflow, n, k)
+		}
+	}
+	return result, nil
+}
+
+// PrimesUpTo returns all primes less than or equal to limit using the sieve of Eratosthenes.
+func PrimesUpTo(limit int) []int {
+	if limit < 2 {
+		return nil
+	}
+
+	composite := make([]bool, limit+1)
+	primes := make([]int, 0, primeCountEstimate(limit))
+	for i := 2; i <= limit; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for j := i * i; j <= limit && j > 0; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+// primeCountEstimate approximates the number of primes up to n (n/ln n),
+// used to size result slices.
+func primeCountEstimate(n int) int {
+	if n < 17 {
+		return 6
+	}
+	return int(float64(n) / (math.Log(float64(n)) - 1.1))
+}
+
+// Primes returns an unbounded sequence of primes in ascending order, generated
+// by an incremental sieve that uses memory proportional to the primes found.
+func Primes() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		if !yield(2) {
+			return
+		}
+		// next maps an upcoming odd composite to the stride (2p) of a prime dividing it
+		next := make(map[int64]int64)
+		for n := int64(3); n > 0; n += 2 {
+			step, ok := next[n]
+			if !ok {
+				if !yield(n) {
+					return
+				}
+				if n <= math.MaxInt64/n {
+					next[n*n] = 2 * n
+				}
+				continue
+			}
+
+			delete(next, n)
+			m := n + step
+			for {
+				if _, taken := next[m]; !taken {
+					break
+				}
+				m += step
+			}
+			if m > 0 {
+				next[m] = step
+			}
+		}
+	}
+}
+
+// PrimeFactors returns the prime factorization of n in ascending order, with
+// each factor repeated according to its multiplicity. It returns nil for n < 2.
+func PrimeFactors(n int64) []int64 {
+	if n < 2 {
+		return nil
+	}
+
+	var factors []int64
+	for _, p := range []int64{2, 3} {
+		for n%p == 0 {
+			factors = append(factors, p)
+			n /= p
+		}
+	}
+	for p := int64(5); p <= n/p; p += 6 {
+		for _, q := range []int64{p, p + 2} {
+			for n%q == 0 {
+				factors = append(factors, q)
+				n /= q
+			}
+		}
+	}
+	if n > 1 {
+		factors = append(factors, n)
+	}
+	return factors
+}
+
+// ModPow calculates base^exp mod m without intermediate overflow.
+// The result is in [0, m). It panics if m <= 0 or exp < 0.
+func ModPow(base, exp, m int64) int64 {
+	if m <= 0 {
+		panic("mathExt: ModPow modulus must be positive")
+	}
+	if exp < 0 {
+		panic("mathExt: ModPow exponent must be non-negative")
+	}
+
+	mod := uint64(m)
+	b := uint64(nonNegativeMod(base, m))
+	result := uint64(1) % mod
+	for e := uint64(exp); e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = mulMod(result, b, mod)
+		}
+		b = mulMod(b, b, mod)
+	}
+	return int64(result)
+}
+
+// mulMod calculates a·b mod m using a 128-bit intermediate product.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+// ModInverse returns x such that a·x ≡ 1 (mod m), in [0, m).
+// An error is returned if m <= 0 or a and m are not coprime.
+func ModInverse(a, m int64) (int64, error) {
+	if m <= 0 {
+		return 0, fmt.Errorf("mathExt: modulus must be positive, got %d", m)
+	}
+
+	// Extended Euclid on (a mod m, m); coefficients stay within ±m
+	oldR, r := nonNegativeMod(a, m), m
+	oldS, s := int64(1), int64(0)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, oldS-q*s
+	}
+	if oldR != 1 {
+		return 0, fmt.Errorf("mathExt: %d has no inverse modulo %d", a, m)
+	}
+	return nonNegativeMod(oldS, m), nil
+}
+
+// nonNegativeMod returns a mod m in [0, m) for positive m, without the
+// overflow of the usual ((a % m) + m) % m.
+func nonNegativeMod(a, m int64) int64 {
+	r := a % m
+	if r < 0 {
+		r += m
+	}
+	return r
+}
+
+// IsPrime64 determines if n is prime using a deterministic Miller-Rabin test,
+// which is exact for every int64 and much faster than trial division for large n.
+func IsPrime64(n int64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37} {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	// n-1 = d·2^s with d odd
+	u := uint64(n)
+	d := u - 1
+	s := bits.TrailingZeros64(d)
+	d >>= s
+
+	// These bases are sufficient for all n < 3.3·10^24
+	for _, a := range []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37} {
+		x := uint64(ModPow(int64(a), int64(d), n))
+		if x == 1 || x == u-1 {
+			continue
+		}
+		composite := true
+		for i := 1; i < s; i++ {
+			x = mulMod(x, x, u)
+			if x == u-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// NextPrime returns the smallest prime strictly greater than n, or
+// ErrOverflow if that prime does not fit in an int64.
+func NextPrime(n int64) (int64, error) {
+	if n < 2 {
+		return 2, nil
+	}
+
+	c := n + 1
+	if c%2 == 0 && c != 2 {
+		c++
+	}
+	for ; c > 0; c += 2 {
+		if IsPrime64(c) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no int64 prime after %d", ErrOverflow, n)
+}
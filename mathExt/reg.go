@@ -0,0 +1,162 @@
+package mathExt
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDimensionMismatch is returned when inputs have incompatible sizes.
+	ErrDimensionMismatch = errors.New("mathExt: dimension mismatch")
+	// ErrSingular is returned when a system of equations has no unique solution.
+	ErrSingular = errors.New("mathExt: matrix is singular")
+)
+
+// Regression is a fitted linear model.
+type Regression struct {
+	Coefficients []float64 // Intercept first, then one coefficient per feature (or power of x)
+	RSquared     float64   // Coefficient of determination
+	Residuals    []float64 // Observed minus fitted value for each sample
+	degree       int       // Polynomial degree, or 0 for a plain linear model
+}
+
+// Intercept returns the constant term of the model.
+func (r *Regression) Intercept() float64 {
+	return r.Coefficients[0]
+}
+
+// Slope returns the coefficient of the first feature, which for a simple
+// linear fit is the slope of the line.
+func (r *Regression) Slope() float64 {
+	if len(r.Coefficients) < 2 {
+		return 0
+	}
+	return r.Coefficients[1]
+}
+
+// Predict evaluates the model. Linear models take one value per feature;
+// polynomial models take a single x.
+func (r *Regression) Predict(x ...float64) float64 {
+	if r.degree > 0 && len(x) == 1 {
+		x = powers(x[0], r.degree)
+	}
+
+	y := r.Coefficients[0]
+	for i, v := range x {
+		if i+1 < len(r.Coefficients) {
+			y += r.Coefficients[i+1] * v
+		}
+	}
+	return y
+}
+
+// Fit performs simple linear regression of y on x (y = a + b·x).
+func Fit(x, y []float64) (*Regression, error) {
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("%w: %d x values, %d y values", ErrDimensionMismatch, len(x), len(y))
+	}
+	rows := make([][]float64, len(x))
+	for i, v := range x {
+		rows[i] = []float64{v}
+	}
+	return FitMultiple(rows, y)
+}
+
+// FitMultiple performs ordinary least squares regression of y on several
+// features, where xs[i] holds the features of sample i.
+func FitMultiple(xs [][]float64, y []float64) (*Regression, error) {
+	if len(xs) != len(y) {
+		return nil, fmt.Errorf("%w: %d samples, %d y values", ErrDimensionMismatch, len(xs), len(y))
+	}
+	if len(xs) == 0 {
+		return nil, fmt.Errorf("%w: no samples", ErrDimensionMismatch)
+	}
+
+	features := len(xs[0])
+	for i, row := range xs {
+		if len(row) != features {
+			return nil, fmt.Errorf("%w: sample %d has %d features, expected %d", ErrDimensionMismatch, i, len(row), features)
+		}
+	}
+	if len(xs) <= features {
+		return nil, fmt.Errorf("%w: need more samples than features", ErrDimensionMismatch)
+	}
+
+	// Build the normal equations (XᵀX)β = Xᵀy with a leading column of ones
+	design := NewMatrix(len(xs), features+1)
+	for i, sample := range xs {
+		design.Set(i, 0, 1)
+		for j, v := range sample {
+			design.Set(i, j+1, v)
+		}
+	}
+	xt := design.Transpose()
+	xtx, err := xt.Mul(design)
+	if err != nil {
+		return nil, err
+	}
+	xty, err := xt.MulVec(y)
+	if err != nil {
+		return nil, err
+	}
+
+	coef, err := xtx.Solve(xty)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Regression{Coefficients: coef}
+	r.evaluate(xs, y)
+	return r, nil
+}
+
+// FitPolynomial fits y = c0 + c1·x + c2·x² + ... + cd·x^d by least squares.
+func FitPolynomial(x, y []float64, degree int) (*Regression, error) {
+	if degree < 1 {
+		return nil, fmt.Errorf("%w: polynomial degree must be at least 1", ErrDimensionMismatch)
+	}
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("%w: %d x values, %d y values", ErrDimensionMismatch, len(x), len(y))
+	}
+
+	rows := make([][]float64, len(x))
+	for i, v := range x {
+		rows[i] = powers(v, degree)
+	}
+
+	r, err := FitMultiple(rows, y)
+	if err != nil {
+		return nil, err
+	}
+	r.degree = degree
+	return r, nil
+}
+
+// evaluate fills in residuals and R² for the fitted coefficients.
+func (r *Regression) evaluate(xs [][]float64, y []float64) {
+	mean := Mean(y)
+	var ssRes, ssTot float64
+	r.Residuals = make([]float64, len(y))
+	for i := range y {
+		r.Residuals[i] = y[i] - r.Predict(xs[i]...)
+		ssRes += r.Residuals[i] * r.Residuals[i]
+		ssTot += (y[i] - mean) * (y[i] - mean)
+	}
+
+	if ssTot == 0 {
+		r.RSquared = 1
+		return
+	}
+	r.RSquared = 1 - ssRes/ssTot
+}
+
+// powers returns [x, x², ..., x^degree].
+func powers(x float64, degree int) []float64 {
+	p := make([]float64, degree)
+	v := 1.0
+	for i := range p {
+		v *= x
+		p[i] = v
+	}
+	return p
+}
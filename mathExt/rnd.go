@@ -0,0 +1,126 @@
+package mathExt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// roundMode selects how roundDecimal treats discarded digits.
+type roundMode int
+
+const (
+	roundHalfAway roundMode = iota
+	roundHalfEven
+	roundFloor
+	roundCeil
+	roundTrunc
+)
+
+// RoundTo rounds v to the given number of decimal places, with halves rounded
+// away from zero. Negative places round to tens, hundreds and so on.
+// Rounding works on the shortest decimal representation of v, so
+// RoundTo(1.005, 2) is 1.01 rather than the 1.0 produced by math.Round(v*100)/100.
+func RoundTo(v float64, places int) float64 {
+	return roundDecimal(v, places, roundHalfAway)
+}
+
+// RoundHalfEven rounds v to the given number of decimal places, with halves
+// rounded to the nearest even digit (banker's rounding).
+func RoundHalfEven(v float64, places int) float64 {
+	return roundDecimal(v, places, roundHalfEven)
+}
+
+// FloorTo rounds v down (towards negative infinity) to the given number of decimal places.
+func FloorTo(v float64, places int) float64 {
+	return roundDecimal(v, places, roundFloor)
+}
+
+// CeilTo rounds v up (towards positive infinity) to the given number of decimal places.
+func CeilTo(v float64, places int) float64 {
+	return roundDecimal(v, places, roundCeil)
+}
+
+// TruncTo drops all digits of v beyond the given number of decimal places.
+func TruncTo(v float64, places int) float64 {
+	return roundDecimal(v, places, roundTrunc)
+}
+
+// SignificantFigures rounds v to n significant digits, with halves rounded away from zero.
+func SignificantFigures(v float64, n int) float64 {
+	if n <= 0 || v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	_, exp := decimalDigits(v)
+	return roundDecimal(v, n-exp-1, roundHalfAway)
+}
+
+// decimalDigits returns the shortest decimal digits of |v| and the exponent
+// of the first digit, so that |v| = 0.d1d2d3... × 10^(exp+1).
+func decimalDigits(v float64) (string, int) {
+	s := strconv.FormatFloat(math.Abs(v), 'e', -1, 64)
+	mantissa, expText, _ := strings.Cut(s, "e")
+	exp, _ := strconv.Atoi(expText)
+	return strings.Replace(mantissa, ".", "", 1), exp
+}
+
+func roundDecimal(v float64, places int, mode roundMode) float64 {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+
+	digits, exp := decimalDigits(v)
+	keep := exp + 1 + places
+	if keep < 0 {
+		// Pad with leading zeros so the rounding position falls inside the digits
+		digits = strings.Repeat("0", -keep) + digits
+		keep = 0
+	}
+	if keep >= len(digits) {
+		return v
+	}
+
+	kept, rest := digits[:keep], digits[keep:]
+	nonZeroRest := strings.TrimRight(rest, "0") != ""
+	negative := v < 0
+
+	var up bool
+	switch mode {
+	case roundHalfAway:
+		up = rest[0] >= '5'
+	case roundHalfEven:
+		lastOdd := keep > 0 && (kept[keep-1]-'0')%2 == 1
+		up = rest[0] > '5' || (rest[0] == '5' && (strings.TrimRight(rest[1:], "0") != "" || lastOdd))
+	case roundFloor:
+		up = negative && nonZeroRest
+	case roundCeil:
+		up = !negative && nonZeroRest
+	}
+
+	if up {
+		kept = incrementDecimal(kept)
+	}
+	if kept == "" {
+		kept = "0"
+	}
+
+	// The kept digits are an integer count of units of 10^-places
+	result, _ := strconv.ParseFloat(kept+"e"+strconv.Itoa(-places), 64)
+	if negative {
+		result = -result
+	}
+	return result
+}
+
+// incrementDecimal adds one to a string of decimal digits.
+func incrementDecimal(digits string) string {
+	b := []byte(digits)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '9' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b)
+}
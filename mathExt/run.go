@@ -0,0 +1,198 @@
+package mathExt
+
+import (
+	"math"
+	"sort"
+)
+
+// RunningStats accumulates count, mean, variance, min and max over a stream
+// of values in O(1) memory using Welford's algorithm. The zero value is ready
+// to use. RunningStats is not safe for concurrent use.
+type RunningStats struct {
+	n    int64
+	mean float64
+	m2   float64
+	min  float64
+	max  float64
+}
+
+// Add includes a value in the statistics.
+func (s *RunningStats) Add(x float64) {
+	s.n++
+	if s.n == 1 {
+		s.min, s.max = x, x
+	} else {
+		s.min = math.Min(s.min, x)
+		s.max = math.Max(s.max, x)
+	}
+
+	delta := x - s.mean
+	s.mean += delta / float64(s.n)
+	s.m2 += delta * (x - s.mean)
+}
+
+// Merge combines the statistics of other into s, as if every value added to
+// other had been added to s. Useful for aggregating per-worker statistics.
+func (s *RunningStats) Merge(other *RunningStats) {
+	if other.n == 0 {
+		return
+	}
+	if s.n == 0 {
+		*s = *other
+		return
+	}
+
+	n := s.n + other.n
+	delta := other.mean - s.mean
+	s.m2 += other.m2 + delta*delta*float64(s.n)*float64(other.n)/float64(n)
+	s.mean += delta * float64(other.n) / float64(n)
+	s.min = math.Min(s.min, other.min)
+	s.max = math.Max(s.max, other.max)
+	s.n = n
+}
+
+// Reset clears all accumulated statistics.
+func (s *RunningStats) Reset() {
+	*s = RunningStats{}
+}
+
+// Count returns the number of values added.
+func (s *RunningStats) Count() int64 { return s.n }
+
+// Mean returns the arithmetic mean, or 0 if no values were added.
+func (s *RunningStats) Mean() float64 { return s.mean }
+
+// Sum returns the sum of all values added.
+func (s *RunningStats) Sum() float64 { return s.mean * float64(s.n) }
+
+// Min returns the smallest value added, or 0 if none.
+func (s *RunningStats) Min() float64 { return s.min }
+
+// Max returns the largest value added, or 0 if none.
+func (s *RunningStats) Max() float64 { return s.max }
+
+// Variance returns the population variance.
+func (s *RunningStats) Variance() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return s.m2 / float64(s.n)
+}
+
+// SampleVariance returns the sample variance (n-1 denominator).
+func (s *RunningStats) SampleVariance() float64 {
+	if s.n <= 1 {
+		return 0
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+// StdDev returns the population standard deviation.
+func (s *RunningStats) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// QuantileEstimator estimates a single quantile of a stream in O(1) memory
+// using the P² algorithm (Jain & Chlamtac, 1985). It is exact for the first
+// five values and an approximation afterwards.
+// QuantileEstimator is not safe for concurrent use.
+type QuantileEstimator struct {
+	p       float64
+	count   int
+	heights [5]float64 // Marker heights
+	pos     [5]float64 // Actual marker positions
+	desired [5]float64 // Desired marker positions
+	incr    [5]float64 // Desired position increments
+}
+
+// NewQuantileEstimator creates an estimator for quantile p (between 0 and 1).
+func NewQuantileEstimator(p float64) *QuantileEstimator {
+	p = math.Max(0, math.Min(1, p))
+	return &QuantileEstimator{
+		p:       p,
+		pos:     [5]float64{1, 2, 3, 4, 5},
+		desired: [5]float64{1, 1 + 2*p, 1 + 4*p, 3 + 2*p, 5},
+		incr:    [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}
+}
+
+// Add includes a value in the estimate.
+func (q *QuantileEstimator) Add(x float64) {
+	if q.count < 5 {
+		q.heights[q.count] = x
+		q.count++
+		if q.count == 5 {
+			sort.Float64s(q.heights[:])
+		}
+		return
+	}
+	q.count++
+
+	// Find the cell k containing x, adjusting extreme markers
+	var k int
+	switch {
+	case x < q.heights[0]:
+		q.heights[0] = x
+		k = 0
+	case x >= q.heights[4]:
+		q.heights[4] = x
+		k = 3
+	default:
+		for k = 0; k < 3 && x >= q.heights[k+1]; k++ {
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		q.pos[i]++
+	}
+	for i := range q.desired {
+		q.desired[i] += q.incr[i]
+	}
+
+	// Adjust the heights of the middle markers if necessary
+	for i := 1; i <= 3; i++ {
+		d := q.desired[i] - q.pos[i]
+		if (d >= 1 && q.pos[i+1]-q.pos[i] > 1) || (d <= -1 && q.pos[i-1]-q.pos[i] < -1) {
+			sign := math.Copysign(1, d)
+			h := q.parabolic(i, sign)
+			if q.heights[i-1] < h && h < q.heights[i+1] {
+				q.heights[i] = h
+			} else {
+				q.heights[i] = q.linear(i, sign)
+			}
+			q.pos[i] += sign
+		}
+	}
+}
+
+func (q *QuantileEstimator) parabolic(i int, d float64) float64 {
+	n, h := q.pos, q.heights
+	return h[i] + d/(n[i+1]-n[i-1])*
+		((n[i]-n[i-1]+d)*(h[i+1]-h[i])/(n[i+1]-n[i])+
+			(n[i+1]-n[i]-d)*(h[i]-h[i-1])/(n[i]-n[i-1]))
+}
+
+func (q *QuantileEstimator) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return q.heights[i] + d*(q.heights[j]-q.heights[i])/(q.pos[j]-q.pos[i])
+}
+
+// Count returns the number of values added.
+func (q *QuantileEstimator) Count() int {
+	return q.count
+}
+
+// Value returns the current quantile estimate, or 0 if no values were added.
+func (q *QuantileEstimator) Value() float64 {
+	if q.count == 0 {
+		return 0
+	}
+	// Until a sixth value arrives the markers are just the sorted values
+	if q.count <= 5 {
+		sorted := make([]float64, q.count)
+		copy(sorted, q.heights[:q.count])
+		sort.Float64s(sorted)
+		return percentileSorted(sorted, q.p*100)
+	}
+	return q.heights[2]
+}
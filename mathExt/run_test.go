@@ -0,0 +1,42 @@
+package mathExt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantileEstimatorExact(t *testing.T) {
+	tests := []struct {
+		p    float64
+		n    int
+		want float64
+	}{
+		{0.5, 1, 1},
+		{0.5, 4, 2.5},
+		{0.5, 5, 3},
+		{0, 5, 1},
+		{0.25, 5, 2},
+		{0.99, 5, 4.96},
+		{1, 5, 5},
+	}
+	for _, tt := range tests {
+		q := NewQuantileEstimator(tt.p)
+		// Add in reverse so the estimator has to sort
+		for x := tt.n; x >= 1; x-- {
+			q.Add(float64(x))
+		}
+		if got := q.Value(); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("p=%v over 1..%d: Value() = %v, want %v", tt.p, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestQuantileEstimatorStream(t *testing.T) {
+	q := NewQuantileEstimator(0.9)
+	for i := 0; i < 10000; i++ {
+		q.Add(float64(i % 1000))
+	}
+	if got := q.Value(); math.Abs(got-900) > 20 {
+		t.Errorf("p=0.9 over a uniform stream: Value() = %v, want about 900", got)
+	}
+}
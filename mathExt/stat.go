@@ -0,0 +1,225 @@
+package mathExt
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary holds descriptive statistics for a data set, as returned by Describe.
+type Summary struct {
+	Count    int
+	Mean     float64
+	StdDev   float64 // Population standard deviation
+	Variance float64 // Population variance
+	Min      float64
+	Q1       float64 // 25th percentile
+	Median   float64
+	Q3       float64 // 75th percentile
+	Max      float64
+	Skewness float64
+	Kurtosis float64 // Excess kurtosis
+}
+
+// Describe computes a full set of descriptive statistics in one call.
+func Describe(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+
+	sorted := sortedCopy(values)
+	variance := Variance(values)
+	return Summary{
+		Count:    len(values),
+		Mean:     Mean(values),
+		StdDev:   math.Sqrt(variance),
+		Variance: variance,
+		Min:      sorted[0],
+		Q1:       percentileSorted(sorted, 25),
+		Median:   percentileSorted(sorted, 50),
+		Q3:       percentileSorted(sorted, 75),
+		Max:      sorted[len(sorted)-1],
+		Skewness: Skewness(values),
+		Kurtosis: Kurtosis(values),
+	}
+}
+
+// Percentile returns the p-th percentile (0-100) of values using linear
+// interpolation between closest ranks. p is clamped to [0, 100].
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return percentileSorted(sortedCopy(values), p)
+}
+
+// Quantiles returns the quantiles (each 0-1) of values, sorting the data only once.
+func Quantiles(values []float64, qs ...float64) []float64 {
+	result := make([]float64, len(qs))
+	if len(values) == 0 {
+		return result
+	}
+
+	sorted := sortedCopy(values)
+	for i, q := range qs {
+		result[i] = percentileSorted(sorted, q*100)
+	}
+	return result
+}
+
+func percentileSorted(sorted []float64, p float64) float64 {
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	frac := rank - float64(lower)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + frac*(sorted[lower+1]-sorted[lower])
+}
+
+func sortedCopy(values []float64) []float64 {
+	c := make([]float64, len(values))
+	copy(c, values)
+	sort.Float64s(c)
+	return c
+}
+
+// Variance calculates the population variance.
+func Variance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return sumSquaredDeviations(values) / float64(len(values))
+}
+
+// SampleVariance calculates the sample variance using Bessel's correction (n-1).
+func SampleVariance(values []float64) float64 {
+	if len(values) <= 1 {
+		return 0
+	}
+	return sumSquaredDeviations(values) / float64(len(values)-1)
+}
+
+func sumSquaredDeviations(values []float64) float64 {
+	m := Mean(values)
+	var sum float64
+	for _, v := range values {
+		d := v - m
+		sum += d * d
+	}
+	return sum
+}
+
+// Mode returns the most frequent value(s) in ascending order. Every value is
+// returned when all occur equally often.
+func Mode(values []float64) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	counts := make(map[float64]int)
+	maxCount := 0
+	for _, v := range values {
+		counts[v]++
+		if counts[v] > maxCount {
+			maxCount = counts[v]
+		}
+	}
+
+	var modes []float64
+	for v, c := range counts {
+		if c == maxCount {
+			modes = append(modes, v)
+		}
+	}
+	sort.Float64s(modes)
+	return modes
+}
+
+// Skewness calculates the population skewness (third standardized moment).
+// Returns 0 for fewer than two values or zero variance.
+func Skewness(values []float64) float64 {
+	m2, m3, _ := centralMoments(values)
+	if m2 == 0 {
+		return 0
+	}
+	return m3 / math.Pow(m2, 1.5)
+}
+
+// Kurtosis calculates the population excess kurtosis (fourth standardized
+// moment minus 3, so a normal distribution scores 0).
+// Returns 0 for fewer than two values or zero variance.
+func Kurtosis(values []float64) float64 {
+	m2, _, m4 := centralMoments(values)
+	if m2 == 0 {
+		return 0
+	}
+	return m4/(m2*m2) - 3
+}
+
+// centralMoments returns the second, third and fourth central moments.
+func centralMoments(values []float64) (m2, m3, m4 float64) {
+	if len(values) < 2 {
+		return 0, 0, 0
+	}
+
+	m := Mean(values)
+	for _, v := range values {
+		d := v - m
+		d2 := d * d
+		m2 += d2
+		m3 += d2 * d
+		m4 += d2 * d2
+	}
+	n := float64(len(values))
+	return m2 / n, m3 / n, m4 / n
+}
+
+// Covariance calculates the population covariance of x and y.
+// Returns 0 if the slices are empty or differ in length.
+func Covariance(x, y []float64) float64 {
+	if len(x) == 0 || len(x) != len(y) {
+		return 0
+	}
+	return sumCoDeviations(x, y) / float64(len(x))
+}
+
+// SampleCovariance calculates the sample covariance of x and y (n-1 denominator).
+// Returns 0 if there are fewer than two pairs or the slices differ in length.
+func SampleCovariance(x, y []float64) float64 {
+	if len(x) <= 1 || len(x) != len(y) {
+		return 0
+	}
+	return sumCoDeviations(x, y) / float64(len(x)-1)
+}
+
+func sumCoDeviations(x, y []float64) float64 {
+	mx, my := Mean(x), Mean(y)
+	var sum float64
+	for i := range x {
+		sum += (x[i] - mx) * (y[i] - my)
+	}
+	return sum
+}
+
+// PearsonCorrelation calculates the Pearson correlation coefficient of x and y,
+// in the range [-1, 1]. Returns 0 if either series has zero variance or the
+// slices differ in length.
+func PearsonCorrelation(x, y []float64) float64 {
+	if len(x) == 0 || len(x) != len(y) {
+		return 0
+	}
+
+	sxx := sumSquaredDeviations(x)
+	syy := sumSquaredDeviations(y)
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sumCoDeviations(x, y) / math.Sqrt(sxx*syy)
+}
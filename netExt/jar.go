@@ -0,0 +1,163 @@
+package netExt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/C0d3-5t3w/myT00L5/cryptoExt"
+	"github.com/C0d3-5t3w/myT00L5/ioExt"
+)
+
+// NewCookieJar returns an in-memory cookie jar that uses the public suffix
+// list, so a site cannot set cookies for a whole top-level domain.
+func NewCookieJar() http.CookieJar {
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	return jar
+}
+
+// WithCookieJar sets the jar the client stores cookies in and returns the
+// client. Clients have no jar unless one is set.
+func (c *Client) WithCookieJar(jar http.CookieJar) *Client {
+	c.Jar = jar
+	return c
+}
+
+// FileJar is a cookie jar that can be saved to and loaded from a file,
+// encrypted with AES when a key is given. Session cookies, which have no
+// expiry, are saved too, so a saved login survives a restart.
+type FileJar struct {
+	mu      sync.Mutex
+	jar     http.CookieJar
+	path    string
+	key     []byte
+	entries map[string]storedCookie
+}
+
+type storedCookie struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// NewFileJar returns a jar saved at path, loading its cookies if the file
+// exists. A nil key stores the cookies as plain JSON; otherwise it must be an
+// AES key of 16, 24 or 32 bytes.
+func NewFileJar(path string, key []byte) (*FileJar, error) {
+	j := &FileJar{
+		jar:     NewCookieJar(),
+		path:    path,
+		key:     key,
+		entries: make(map[string]storedCookie),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read cookie jar: %v", err)
+	}
+	if key != nil {
+		if data, err = cryptoExt.DecryptAES(data, key); err != nil {
+			return nil, fmt.Errorf("could not decrypt cookie jar: %v", err)
+		}
+	}
+	var stored []storedCookie
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("could not decode cookie jar: %v", err)
+	}
+	for _, sc := range stored {
+		u, err := url.Parse(sc.URL)
+		if err != nil || sc.Cookie == nil {
+			continue
+		}
+		j.SetCookies(u, []*http.Cookie{sc.Cookie})
+	}
+	return j, nil
+}
+
+// SetCookies implements http.CookieJar.
+func (j *FileJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(u, cookies)
+
+	now := time.Now()
+	origin := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+	for _, c := range cookies {
+		key := cookieKey(u, c)
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+			delete(j.entries, key)
+			continue
+		}
+		stored := *c
+		if c.MaxAge > 0 {
+			// Max-Age is relative to when the cookie was set; keep it
+			// as an absolute expiry so it survives saving.
+			stored.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+			stored.MaxAge = 0
+		}
+		j.entries[key] = storedCookie{URL: origin, Cookie: &stored}
+	}
+}
+
+// cookieKey identifies a cookie the way net/http/cookiejar does, by domain,
+// path and name, so a domain cookie set from two subdomains is stored once.
+func cookieKey(u *url.URL, c *http.Cookie) string {
+	domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+	if domain == "" {
+		domain = strings.ToLower(u.Hostname())
+	}
+	path := c.Path
+	if path == "" || path[0] != '/' {
+		// The default path is the directory of the request path
+		path = "/"
+		if i := strings.LastIndex(u.Path, "/"); i > 0 && u.Path[0] == '/' {
+			path = u.Path[:i]
+		}
+	}
+	return domain + "|" + path + "|" + c.Name
+}
+
+// Cookies implements http.CookieJar.
+func (j *FileJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.jar.Cookies(u)
+}
+
+// Save writes the unexpired cookies to the jar's file, creating its directory
+// if needed.
+func (j *FileJar) Save() error {
+	j.mu.Lock()
+	now := time.Now()
+	stored := make([]storedCookie, 0, len(j.entries))
+	for key, sc := range j.entries {
+		if !sc.Cookie.Expires.IsZero() && sc.Cookie.Expires.Before(now) {
+			delete(j.entries, key)
+			continue
+		}
+		stored = append(stored, sc)
+	}
+	j.mu.Unlock()
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("could not encode cookie jar: %v", err)
+	}
+	if j.key != nil {
+		if data, err = cryptoExt.EncryptAES(data, j.key); err != nil {
+			return fmt.Errorf("could not encrypt cookie jar: %v", err)
+		}
+	}
+	return ioExt.WriteFileWithDirs(j.path, data, 0600)
+}
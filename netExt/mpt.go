@@ -0,0 +1,155 @@
+package netExt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/C0d3-5t3w/myT00L5/encodingExt"
+)
+
+// MultipartFile is a file part of a multipart upload.
+type MultipartFile struct {
+	FieldName   string    // form field name
+	FileName    string    // file name sent to the server
+	ContentType string    // detected from FileName or the content if empty
+	Reader      io.Reader // file content, read once while uploading
+	Size        int64     // content length for progress reporting, or 0 if unknown
+}
+
+// MultipartOption configures PostMultipart.
+type MultipartOption func(*multipartOptions)
+
+type multipartOptions struct {
+	progress func(sent, total int64)
+}
+
+// UploadProgress sets a callback reporting the file bytes sent so far and
+// the total, which is -1 unless every file has a Size. It is called from the
+// goroutine writing the request body.
+func UploadProgress(fn func(sent, total int64)) MultipartOption {
+	return func(o *multipartOptions) {
+		o.progress = fn
+	}
+}
+
+// PostMultipart performs a multipart/form-data POST of fields and files with
+// default headers. Files are streamed from their readers as the request is
+// sent, so they are never held in memory; for the same reason the request is
+// not retried.
+func (c *Client) PostMultipart(ctx context.Context, url string, fields map[string]string, files []MultipartFile, opts ...MultipartOption) (*http.Response, error) {
+	var o multipartOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, pr)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+
+	// Apply default headers
+	for key, value := range c.DefaultHeaders {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, fields, files, o.progress))
+	}()
+
+	resp, err := c.Do(req)
+	if err != nil {
+		pr.CloseWithError(err)
+	}
+	return resp, err
+}
+
+// writeMultipart writes the form to mw, fields first in sorted order.
+func writeMultipart(mw *multipart.Writer, fields map[string]string, files []MultipartFile, progress func(sent, total int64)) error {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := mw.WriteField(key, fields[key]); err != nil {
+			return err
+		}
+	}
+
+	total := int64(0)
+	for _, f := range files {
+		if f.Size <= 0 {
+			total = -1
+			break
+		}
+		total += f.Size
+	}
+
+	var sent int64
+	for _, f := range files {
+		r := bufio.NewReader(f.Reader)
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(f.FileName))
+		}
+		if contentType == "" {
+			head, _ := r.Peek(512)
+			contentType = encodingExt.DetectContentType(head)
+		}
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(f.FieldName), escapeQuotes(f.FileName)))
+		h.Set("Content-Type", contentType)
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = part
+		if progress != nil {
+			w = &progressWriter{w: part, sent: &sent, total: total, fn: progress}
+		}
+		if _, err := io.Copy(w, r); err != nil {
+			return fmt.Errorf("could not upload %s: %v", f.FileName, err)
+		}
+	}
+	return mw.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// progressWriter reports the bytes written through it across all files.
+type progressWriter struct {
+	w     io.Writer
+	sent  *int64
+	total int64
+	fn    func(sent, total int64)
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	if n > 0 {
+		*pw.sent += int64(n)
+		pw.fn(*pw.sent, pw.total)
+	}
+	return n, err
+}
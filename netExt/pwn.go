@@ -0,0 +1,75 @@
+package netExt
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PwnedPasswordsURL is the Have I Been Pwned password range API.
+const PwnedPasswordsURL = "https://api.pwnedpasswords.com/range/"
+
+// PwnedPasswords checks passwords against the Have I Been Pwned breach
+// corpus using k-anonymity: only the first five hex characters of the
+// password's SHA-1 are sent, and the match is found locally among the
+// returned suffixes. It implements cryptoExt.BreachChecker.
+type PwnedPasswords struct {
+	client  *Client
+	baseURL string
+}
+
+// NewPwnedPasswords returns a checker that sends its requests with c.
+func (c *Client) NewPwnedPasswords() *PwnedPasswords {
+	return &PwnedPasswords{client: c, baseURL: PwnedPasswordsURL}
+}
+
+// WithBaseURL points the checker at another server with the same API, such
+// as a local mirror.
+func (p *PwnedPasswords) WithBaseURL(baseURL string) *PwnedPasswords {
+	p.baseURL = baseURL
+	return p
+}
+
+// BreachCount returns how many times password appears in known breaches.
+func (p *PwnedPasswords) BreachCount(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	for key, value := range p.client.DefaultHeaders {
+		req.Header.Set(key, value)
+	}
+	// Padding hides the size of the response, and with it the prefix.
+	req.Header.Set("Add-Padding", "true")
+	resp, err := p.client.DoWithRetries(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("could not check password: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		s, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(s, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return 0, fmt.Errorf("could not parse breach count: %v", err)
+		}
+		return n, nil
+	}
+	return 0, scanner.Err()
+}
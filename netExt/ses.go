@@ -0,0 +1,125 @@
+package netExt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Session runs a login flow once and reuses the cookies it sets for later
+// requests. If a request comes back 401 Unauthorized, the session logs in
+// again and retries it once.
+//
+//	s := client.NewSession(func(ctx context.Context, c *netExt.Client) error {
+//		resp, err := c.Post(ctx, loginURL, "application/x-www-form-urlencoded", form)
+//		...
+//	})
+//	resp, err := s.Get(ctx, apiURL)
+type Session struct {
+	client   *Client
+	login    func(ctx context.Context, c *Client) error
+	mu       sync.Mutex
+	loggedIn bool
+}
+
+// NewSession returns a session that logs in with login. If the client has no
+// cookie jar, an in-memory one is set.
+func (c *Client) NewSession(login func(ctx context.Context, c *Client) error) *Session {
+	if c.Jar == nil {
+		c.WithCookieJar(NewCookieJar())
+	}
+	return &Session{client: c, login: login}
+}
+
+// Client returns the client the session sends requests with.
+func (s *Session) Client() *Client {
+	return s.client
+}
+
+// Login runs the login flow unless the session is already logged in.
+func (s *Session) Login(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loggedIn {
+		return nil
+	}
+	if err := s.login(ctx, s.client); err != nil {
+		return err
+	}
+	s.loggedIn = true
+	return nil
+}
+
+// Invalidate marks the session as logged out, so the next request logs in
+// again.
+func (s *Session) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loggedIn = false
+}
+
+// Do logs in if needed and sends req with the client's retries. A request
+// with a body is only retried after a 401 if its GetBody is set, as it is for
+// requests built from in-memory bodies by http.NewRequest.
+func (s *Session) Do(req *http.Request) (*http.Response, error) {
+	if err := s.Login(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := s.client.DoWithRetries(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	// http.Client added the jar's cookies to req; drop them so the retry
+	// picks up the ones the new login set.
+	retry := req.Clone(req.Context())
+	retry.Header.Del("Cookie")
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	s.Invalidate()
+	if err := s.Login(req.Context()); err != nil {
+		return nil, err
+	}
+	return s.client.DoWithRetries(retry)
+}
+
+// Get performs an HTTP GET request within the session
+func (s *Session) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := s.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.Do(req)
+}
+
+// Post performs an HTTP POST request within the session
+func (s *Session) Post(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := s.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return s.Do(req)
+}
+
+// newRequest builds a request with the client's default headers.
+func (s *Session) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range s.client.DefaultHeaders {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
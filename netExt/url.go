@@ -0,0 +1,269 @@
+package netExt
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// URLBuilder builds URLs piece by piece, escaping each part, so request URLs
+// are not put together by string concatenation. Errors, such as a bad base
+// URL or a missing template parameter, are reported by Build.
+//
+//	u, err := netExt.NewURLBuilder("https://api.example.com/v1").
+//		PathTemplate("/users/{id}/posts", "id", userID).
+//		Query("tag", "go & more").
+//		Build()
+type URLBuilder struct {
+	u     url.URL
+	path  []string // escaped segments added to the base path
+	query url.Values
+	err   error
+}
+
+// NewURLBuilder returns a builder starting from base, which may be empty or
+// may already hold a path and query.
+func NewURLBuilder(base string) *URLBuilder {
+	b := &URLBuilder{query: url.Values{}}
+	u, err := url.Parse(base)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.u = *u
+	b.query = u.Query()
+	b.u.RawQuery = ""
+	return b
+}
+
+func (b *URLBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Scheme sets the scheme, such as https.
+func (b *URLBuilder) Scheme(scheme string) *URLBuilder {
+	b.u.Scheme = scheme
+	return b
+}
+
+// Host sets the host, with an optional port.
+func (b *URLBuilder) Host(host string) *URLBuilder {
+	b.u.Host = host
+	return b
+}
+
+// Path appends segments to the path, escaping each one, so a segment
+// containing a slash stays a single segment. "." and ".." are rejected, as
+// escaping leaves them able to change the path.
+func (b *URLBuilder) Path(segments ...string) *URLBuilder {
+	for _, s := range segments {
+		if isDotSegment(s) {
+			b.setErr(fmt.Errorf("could not add path segment %q", s))
+			return b
+		}
+		b.path = append(b.path, url.PathEscape(s))
+	}
+	return b
+}
+
+func isDotSegment(s string) bool {
+	return s == "." || s == ".."
+}
+
+// PathTemplate appends a path such as /users/{id}, replacing each {name}
+// with the escaped value that follows that name in params. Segments that
+// expand to "." or ".." are rejected.
+func (b *URLBuilder) PathTemplate(template string, params ...any) *URLBuilder {
+	if len(params)%2 != 0 {
+		b.setErr(fmt.Errorf("could not expand %s: odd number of params", template))
+		return b
+	}
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[fmt.Sprint(params[i])] = fmt.Sprint(params[i+1])
+	}
+
+	for _, seg := range strings.Split(strings.Trim(template, "/"), "/") {
+		if seg == "" {
+			continue
+		}
+		var sb strings.Builder
+		for seg != "" {
+			start := strings.IndexByte(seg, '{')
+			if start < 0 {
+				sb.WriteString(url.PathEscape(seg))
+				break
+			}
+			end := strings.IndexByte(seg[start:], '}')
+			if end < 0 {
+				b.setErr(fmt.Errorf("could not expand %s: unclosed {", template))
+				return b
+			}
+			name := seg[start+1 : start+end]
+			value, ok := values[name]
+			if !ok {
+				b.setErr(fmt.Errorf("could not expand %s: missing param %s", template, name))
+				return b
+			}
+			sb.WriteString(url.PathEscape(seg[:start]))
+			sb.WriteString(url.PathEscape(value))
+			seg = seg[start+end+1:]
+		}
+		if isDotSegment(sb.String()) {
+			b.setErr(fmt.Errorf("could not expand %s: segment %q", template, sb.String()))
+			return b
+		}
+		b.path = append(b.path, sb.String())
+	}
+	return b
+}
+
+// Query adds values for key to the query string.
+func (b *URLBuilder) Query(key string, values ...string) *URLBuilder {
+	for _, v := range values {
+		b.query.Add(key, v)
+	}
+	return b
+}
+
+// SetQuery replaces the values of key in the query string.
+func (b *URLBuilder) SetQuery(key string, value string) *URLBuilder {
+	b.query.Set(key, value)
+	return b
+}
+
+// QueryStruct adds the fields of a struct to the query string, as encoded by
+// EncodeQuery.
+func (b *URLBuilder) QueryStruct(v interface{}) *URLBuilder {
+	values, err := EncodeQuery(v)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	for key, vs := range values {
+		b.Query(key, vs...)
+	}
+	return b
+}
+
+// Fragment sets the fragment, without the leading #.
+func (b *URLBuilder) Fragment(fragment string) *URLBuilder {
+	b.u.Fragment = fragment
+	return b
+}
+
+// URL returns the built URL, or the first error met while building it.
+func (b *URLBuilder) URL() (*url.URL, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	u := b.u
+	if len(b.path) > 0 {
+		escaped := strings.TrimSuffix(u.EscapedPath(), "/") + "/" + strings.Join(b.path, "/")
+		path, err := url.PathUnescape(escaped)
+		if err != nil {
+			return nil, err
+		}
+		u.Path, u.RawPath = path, escaped
+	}
+	u.RawQuery = b.query.Encode()
+	return &u, nil
+}
+
+// Build returns the built URL as a string.
+func (b *URLBuilder) Build() (string, error) {
+	u, err := b.URL()
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// EncodeQuery encodes the exported fields of a struct, or a pointer to one,
+// as query values. Keys come from `url:"name"` tags, falling back to the
+// field name; "-" skips a field and omitempty skips zero values. Slices add
+// a value per element, []byte adds its contents as a string and times use
+// RFC 3339.
+func EncodeQuery(v interface{}) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("could not encode query: need a struct, got %v", rv.Type())
+	}
+
+	values := url.Values{}
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("url"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fv := rv.Field(i)
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatQueryValue(fv.Index(j))
+				if err != nil {
+					return nil, fmt.Errorf("could not encode query field %s: %v", name, err)
+				}
+				values.Add(name, s)
+			}
+			continue
+		}
+		s, err := formatQueryValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode query field %s: %v", name, err)
+		}
+		values.Add(name, s)
+	}
+	return values, nil
+}
+
+func formatQueryValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.Format(time.RFC3339), nil
+	case fmt.Stringer:
+		return x.String(), nil
+	case encoding.TextMarshaler:
+		text, err := x.MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface()), nil
+	}
+	return "", fmt.Errorf("unsupported type %v", v.Type())
+}
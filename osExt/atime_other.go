@@ -0,0 +1,13 @@
+//go:build !(linux || openbsd || dragonfly || solaris || darwin || freebsd || netbsd)
+
+package osExt
+
+import (
+	"io/fs"
+	"time"
+)
+
+// accessTime is not supported on this platform.
+func accessTime(info fs.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,257 @@
+package osExt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// outputTailSize is how much trailing output an ExitError keeps for its message.
+const outputTailSize = 2048
+
+// waitDelay bounds how long Run waits for output pipes to close after the
+// process exits or is killed, in case it left children holding them open.
+const waitDelay = 5 * time.Second
+
+// Result holds the outcome of a command that ran to completion.
+type Result struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+	Duration time.Duration
+}
+
+// ExitError is returned when a command could not start, exited with a
+// non-zero status, or was stopped by its timeout or context.
+type ExitError struct {
+	Command  string        // Command line, for messages
+	ExitCode int           // Exit status, or -1 if the process did not exit normally
+	Duration time.Duration // How long the command ran
+	Stdout   []byte        // Last bytes of standard output
+	Stderr   []byte        // Last bytes of standard error
+	Err      error         // Underlying error from os/exec or the context
+}
+
+func (e *ExitError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "command %q failed after %v", e.Command, e.Duration.Round(time.Millisecond))
+	if e.ExitCode >= 0 {
+		fmt.Fprintf(&sb, " with exit code %d", e.ExitCode)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&sb, ": %v", e.Err)
+	}
+	if tail := bytes.TrimSpace(e.Stderr); len(tail) > 0 {
+		fmt.Fprintf(&sb, "\nstderr: %s", tail)
+	}
+	return sb.String()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Cmd is a fluent builder for running external commands.
+//
+//	res, err := osExt.Command("git", "status", "--short").
+//		Dir(repo).
+//		Timeout(10 * time.Second).
+//		OnStdout(func(line string) { log.Println(line) }).
+//		Run(ctx)
+type Cmd struct {
+	name     string
+	args     []string
+	dir      string
+	env      []string
+	cleanEnv bool
+	timeout  time.Duration
+	stdin    io.Reader
+	onStdout func(line string)
+	onStderr func(line string)
+}
+
+// Command creates a builder for running name with the given arguments.
+func Command(name string, args ...string) *Cmd {
+	return &Cmd{name: name, args: args}
+}
+
+// Dir sets the working directory of the command.
+func (c *Cmd) Dir(dir string) *Cmd {
+	c.dir = dir
+	return c
+}
+
+// Env adds an environment variable, overriding any inherited value.
+func (c *Cmd) Env(key, value string) *Cmd {
+	c.env = append(c.env, key+"="+value)
+	return c
+}
+
+// CleanEnv stops the command from inheriting the current process environment,
+// so it only sees variables added with Env.
+func (c *Cmd) CleanEnv() *Cmd {
+	c.cleanEnv = true
+	return c
+}
+
+// Timeout kills the command if it runs longer than d.
+func (c *Cmd) Timeout(d time.Duration) *Cmd {
+	c.timeout = d
+	return c
+}
+
+// Stdin sets the command's standard input.
+func (c *Cmd) Stdin(r io.Reader) *Cmd {
+	c.stdin = r
+	return c
+}
+
+// OnStdout calls fn with each line of standard output as it is produced.
+// Output is still captured in the Result.
+func (c *Cmd) OnStdout(fn func(line string)) *Cmd {
+	c.onStdout = fn
+	return c
+}
+
+// OnStderr calls fn with each line of standard error as it is produced.
+// Output is still captured in the Result.
+func (c *Cmd) OnStderr(fn func(line string)) *Cmd {
+	c.onStderr = fn
+	return c
+}
+
+// String returns the command line.
+func (c *Cmd) String() string {
+	return strings.Join(append([]string{c.name}, c.args...), " ")
+}
+
+// Run starts the command and waits for it to finish. A non-zero exit status,
+// a timeout or a cancelled context is reported as an *ExitError; the Result
+// is returned in every case where the process started.
+func (c *Cmd) Run(ctx context.Context) (*Result, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Dir = c.dir
+	cmd.Stdin = c.stdin
+	cmd.WaitDelay = waitDelay
+	if c.cleanEnv {
+		// A nil Env inherits the environment, so it must be empty instead.
+		cmd.Env = append([]string{}, c.env...)
+	} else if len(c.env) > 0 {
+		cmd.Env = append(os.Environ(), c.env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	outLines := newLineWriter(c.onStdout)
+	errLines := newLineWriter(c.onStderr)
+	cmd.Stdout = io.MultiWriter(&stdout, outLines)
+	cmd.Stderr = io.MultiWriter(&stderr, errLines)
+
+	start := time.Now()
+	err := cmd.Run()
+	outLines.flush()
+	errLines.flush()
+
+	res := &Result{
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		ExitCode: -1,
+		Duration: time.Since(start),
+	}
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if err == nil {
+		return res, nil
+	}
+
+	exitErr := &ExitError{
+		Command:  c.String(),
+		ExitCode: res.ExitCode,
+		Duration: res.Duration,
+		Stdout:   tail(res.Stdout, outputTailSize),
+		Stderr:   tail(res.Stderr, outputTailSize),
+		Err:      err,
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		exitErr.Err = ctxErr
+	} else if _, ok := err.(*exec.ExitError); ok {
+		// The exit code already says everything "exit status N" would
+		exitErr.Err = nil
+	}
+	if cmd.ProcessState == nil {
+		return nil, exitErr
+	}
+	return res, exitErr
+}
+
+// RunCommand runs name with args and returns its captured output.
+func RunCommand(ctx context.Context, name string, args ...string) (*Result, error) {
+	return Command(name, args...).Run(ctx)
+}
+
+// tail returns the last n bytes of b.
+func tail(b []byte, n int) []byte {
+	if len(b) <= n {
+		return b
+	}
+	return b[len(b)-n:]
+}
+
+// lineWriter splits written data into lines and passes each to a callback.
+type lineWriter struct {
+	mu  sync.Mutex
+	fn  func(string)
+	buf []byte
+}
+
+func newLineWriter(fn func(string)) *lineWriter {
+	return &lineWriter{fn: fn}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if w.fn == nil {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.fn(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush delivers a final line that had no trailing newline.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.fn != nil && len(w.buf) > 0 {
+		w.fn(string(w.buf))
+	}
+	w.buf = nil
+}
+
+// IsExitCode reports whether err is an *ExitError with the given exit code.
+func IsExitCode(err error, code int) bool {
+	var exitErr *ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode == code
+}
@@ -0,0 +1,271 @@
+package osExt
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CopyOption configures CopyDir.
+type CopyOption func(*copyOptions)
+
+// SymlinkMode controls how CopyDir treats symbolic links.
+type SymlinkMode int
+
+const (
+	// SymlinkFollow copies the file or directory a link points to. This is
+	// the default, matching the behaviour of earlier versions of CopyDir.
+	SymlinkFollow SymlinkMode = iota
+	// SymlinkCopy recreates the link itself, pointing at the same target.
+	SymlinkCopy
+	// SymlinkSkip leaves links out of the copy.
+	SymlinkSkip
+)
+
+// SpecialFileMode controls how CopyDir treats entries that are neither
+// regular files, directories nor symlinks, such as FIFOs, sockets and devices.
+type SpecialFileMode int
+
+const (
+	// SpecialCopy reads the entry and writes what it reads to a regular
+	// file, as earlier versions of CopyDir did. This is the default. Reading
+	// a FIFO blocks until it has a writer.
+	SpecialCopy SpecialFileMode = iota
+	// SpecialSkip leaves special files out of the copy.
+	SpecialSkip
+	// SpecialError stops the copy with an error at the first special file.
+	SpecialError
+)
+
+type copyOptions struct {
+	preserveTimes bool
+	preserveOwner bool
+	symlinks      SymlinkMode
+	special       SpecialFileMode
+	ignore        *IgnoreMatcher
+	exclude       func(rel string, d fs.DirEntry) bool
+	dryRun        bool
+	report        *CopyReport
+	err           error
+}
+
+// CopyReport summarises what CopyDir copied, or would copy in a dry run.
+type CopyReport struct {
+	Files    int      // Regular files copied
+	Dirs     int      // Directories created, including the root
+	Symlinks int      // Links recreated with SymlinkCopy
+	Bytes    int64    // Total size of the files copied
+	Copied   []string // Relative paths copied, in walk order
+	Excluded []string // Relative paths skipped by filters, SymlinkSkip or SpecialSkip
+}
+
+// PreserveTimes keeps the access and modification times of files and
+// directories. Times on recreated symlinks are not preserved.
+func PreserveTimes() CopyOption {
+	return func(o *copyOptions) {
+		o.preserveTimes = true
+	}
+}
+
+// PreserveOwner keeps the user and group of each entry where the platform
+// and the caller's privileges allow it; failures to change owner are ignored.
+func PreserveOwner() CopyOption {
+	return func(o *copyOptions) {
+		o.preserveOwner = true
+	}
+}
+
+// Symlinks sets how symbolic links are handled.
+func Symlinks(mode SymlinkMode) CopyOption {
+	return func(o *copyOptions) {
+		o.symlinks = mode
+	}
+}
+
+// SpecialFiles sets how FIFOs, sockets, devices and other special files are handled.
+func SpecialFiles(mode SpecialFileMode) CopyOption {
+	return func(o *copyOptions) {
+		o.special = mode
+	}
+}
+
+// Exclude skips entries matching any of the .gitignore-style patterns, which
+// are matched against paths relative to the source root.
+func Exclude(patterns ...string) CopyOption {
+	return func(o *copyOptions) {
+		m, err := NewIgnoreMatcher(patterns...)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.ignore = m
+	}
+}
+
+// ExcludeFunc skips entries for which fn returns true. rel is the
+// slash-separated path relative to the source root.
+func ExcludeFunc(fn func(rel string, d fs.DirEntry) bool) CopyOption {
+	return func(o *copyOptions) {
+		o.exclude = fn
+	}
+}
+
+// DryRun walks the source tree and fills in the report without writing anything.
+func DryRun() CopyOption {
+	return func(o *copyOptions) {
+		o.dryRun = true
+	}
+}
+
+// Report records what was copied into r.
+func Report(r *CopyReport) CopyOption {
+	return func(o *copyOptions) {
+		o.report = r
+	}
+}
+
+// copyMeta applies the preserved attributes of info to dst.
+func (o *copyOptions) copyMeta(dst string, info fs.FileInfo) error {
+	if o.preserveOwner {
+		if uid, gid, ok := fileOwner(info); ok {
+			_ = os.Lchown(dst, uid, gid)
+		}
+	}
+	if o.preserveTimes && info.Mode()&fs.ModeSymlink == 0 {
+		if err := os.Chtimes(dst, fileAccessTime(info), info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excluded reports whether the entry at rel is filtered out.
+func (o *copyOptions) excluded(rel string, d fs.DirEntry) bool {
+	if o.ignore != nil && o.ignore.Match(rel, d.IsDir()) {
+		return true
+	}
+	return o.exclude != nil && o.exclude(rel, d)
+}
+
+// copyTree copies the directory src to dst; rel is src relative to the copy root.
+// active holds the real paths of the directories currently being copied, to
+// detect cycles when following links.
+func (o *copyOptions) copyTree(src, dst, rel string, info fs.FileInfo, active map[string]bool) error {
+	real, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+	if active[real] {
+		return fmt.Errorf("could not copy %s: symlink cycle", src)
+	}
+	active[real] = true
+	defer delete(active, real)
+
+	if !o.dryRun {
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	o.report.Dirs++
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		sourcePath := filepath.Join(src, entry.Name())
+		destPath := filepath.Join(dst, entry.Name())
+		entryRel := entry.Name()
+		if rel != "" {
+			entryRel = rel + "/" + entry.Name()
+		}
+
+		if o.excluded(entryRel, entry) {
+			o.report.Excluded = append(o.report.Excluded, entryRel)
+			continue
+		}
+		if err := o.copyEntry(sourcePath, destPath, entryRel, entry, active); err != nil {
+			return err
+		}
+	}
+
+	if o.dryRun {
+		return nil
+	}
+	// Directory times change as children are written, so set them last
+	return o.copyMeta(dst, info)
+}
+
+// copyEntry copies a single directory entry.
+func (o *copyOptions) copyEntry(src, dst, rel string, entry fs.DirEntry, active map[string]bool) error {
+	info, err := entry.Info()
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		switch o.symlinks {
+		case SymlinkSkip:
+			o.report.Excluded = append(o.report.Excluded, rel)
+			return nil
+		case SymlinkCopy:
+			return o.copySymlink(src, dst, rel, info)
+		}
+		if info, err = os.Stat(src); err != nil {
+			return err
+		}
+	}
+
+	if info.IsDir() {
+		o.report.Copied = append(o.report.Copied, rel)
+		return o.copyTree(src, dst, rel, info, active)
+	}
+	if !info.Mode().IsRegular() {
+		switch o.special {
+		case SpecialSkip:
+			o.report.Excluded = append(o.report.Excluded, rel)
+			return nil
+		case SpecialError:
+			return fmt.Errorf("could not copy %s: unsupported file type %v", src, info.Mode().Type())
+		}
+	}
+
+	o.report.Files++
+	o.report.Bytes += info.Size()
+	o.report.Copied = append(o.report.Copied, rel)
+	if o.dryRun {
+		return nil
+	}
+	if err := CopyFile(src, dst); err != nil {
+		return err
+	}
+	return o.copyMeta(dst, info)
+}
+
+func (o *copyOptions) copySymlink(src, dst, rel string, info fs.FileInfo) error {
+	o.report.Symlinks++
+	o.report.Copied = append(o.report.Copied, rel)
+	if o.dryRun {
+		return nil
+	}
+
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	if err := os.Symlink(target, dst); err != nil {
+		return err
+	}
+	return o.copyMeta(dst, info)
+}
+
+// fileAccessTime returns the access time if known, else the modification time.
+func fileAccessTime(info fs.FileInfo) time.Time {
+	if atime, ok := accessTime(info); ok {
+		return atime
+	}
+	return info.ModTime()
+}
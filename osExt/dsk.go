@@ -0,0 +1,71 @@
+package osExt
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// DiskSpace describes the capacity of a filesystem.
+type DiskSpace struct {
+	Total     uint64 // Size of the filesystem in bytes
+	Free      uint64 // Free bytes, including any reserved for the superuser
+	Available uint64 // Free bytes available to the current user
+}
+
+// Used returns the number of bytes in use.
+func (d DiskSpace) Used() uint64 {
+	return d.Total - d.Free
+}
+
+// UsedPercent returns the percentage of the filesystem in use, as reported by df.
+func (d DiskSpace) UsedPercent() float64 {
+	used := d.Used()
+	if used+d.Available == 0 {
+		return 0
+	}
+	return float64(used) / float64(used+d.Available) * 100
+}
+
+// DiskUsage returns the capacity of the filesystem containing path.
+func DiskUsage(path string) (DiskSpace, error) {
+	return diskUsage(path)
+}
+
+// DirSizeProgress reports the running totals of a DirSize walk.
+type DirSizeProgress struct {
+	Path  string // Entry just counted
+	Files int64  // Regular files seen so far
+	Dirs  int64  // Directories seen so far, including the root
+	Bytes int64  // Total size of the files seen so far
+}
+
+// DirSize returns the total size in bytes of all regular files under path.
+// Symlinks are not followed. If progress is non-nil it is called after each
+// entry with the running totals.
+func DirSize(path string, progress func(DirSizeProgress)) (int64, error) {
+	var p DirSizeProgress
+	err := filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			p.Dirs++
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			p.Files++
+			p.Bytes += info.Size()
+		}
+
+		if progress != nil {
+			p.Path = name
+			progress(p)
+		}
+		return nil
+	})
+	return p.Bytes, err
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || dragonfly || windows)
+
+package osExt
+
+import (
+	"errors"
+	"os"
+)
+
+func diskUsage(path string) (DiskSpace, error) {
+	return DiskSpace{}, &os.PathError{Op: "statfs", Path: path, Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd || dragonfly
+
+package osExt
+
+import (
+	"os"
+	"syscall"
+)
+
+func diskUsage(path string) (DiskSpace, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return DiskSpace{}, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+
+	bsize := uint64(st.Bsize)
+	return DiskSpace{
+		Total:     uint64(st.Blocks) * bsize,
+		Free:      uint64(st.Bfree) * bsize,
+		Available: uint64(st.Bavail) * bsize,
+	}, nil
+}
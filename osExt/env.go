@@ -0,0 +1,231 @@
+package osExt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrUndefinedVariable is wrapped by ExpandEnvStrict when a variable is not set.
+var ErrUndefinedVariable = errors.New("undefined variable")
+
+// ParseDotenv parses KEY=VALUE lines in the .env format:
+//
+//	# comment
+//	export NAME=value        # trailing comment
+//	GREETING="hello\nworld"  # escapes and ${VAR} expansion
+//	RAW='no $expansion here'
+//	MULTI="first line
+//	second line"
+//
+// Unquoted and double-quoted values expand $VAR and ${VAR} from earlier keys
+// in the file, then from the process environment.
+func ParseDotenv(r io.Reader) (map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+	lookup := func(name string) string {
+		if v, ok := env[name]; ok {
+			return v
+		}
+		return os.Getenv(name)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !validEnvName(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE, got %q", lineNo, lines[i])
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value string
+		switch {
+		case strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'"):
+			quote := rest[0]
+			body := rest[1:]
+			end := closingQuote(body, quote)
+			// Quoted values may continue over following lines
+			for end < 0 && i+1 < len(lines) {
+				i++
+				body += "\n" + lines[i]
+				end = closingQuote(body, quote)
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quoted value for %s", lineNo, key)
+			}
+			if trailing := strings.TrimSpace(body[end+1:]); trailing != "" && !strings.HasPrefix(trailing, "#") {
+				return nil, fmt.Errorf("line %d: unexpected text after quoted value for %s", lineNo, key)
+			}
+			value = body[:end]
+			if quote == '"' {
+				value = expandDouble(value, lookup)
+			}
+		default:
+			if strings.HasPrefix(rest, "#") {
+				rest = ""
+			} else if j := strings.Index(rest, " #"); j >= 0 {
+				rest = rest[:j]
+			} else if j := strings.Index(rest, "\t#"); j >= 0 {
+				rest = rest[:j]
+			}
+			value = os.Expand(strings.TrimSpace(rest), lookup)
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// closingQuote returns the index of the first unescaped quote in s, or -1.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// expandDouble processes backslash escapes in a double-quoted value and
+// expands the variables outside them, so \$ is a literal dollar sign while
+// \\$VAR is a backslash followed by the value of VAR.
+func expandDouble(s string, lookup func(string) string) string {
+	if !strings.Contains(s, `\`) {
+		return os.Expand(s, lookup)
+	}
+
+	var sb strings.Builder
+	start := 0
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] != '\\' {
+			continue
+		}
+		sb.WriteString(os.Expand(s[start:i], lookup))
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		default:
+			sb.WriteByte(s[i])
+		}
+		start = i + 1
+	}
+	sb.WriteString(os.Expand(s[start:], lookup))
+	return sb.String()
+}
+
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ReadDotenv parses the .env file at path without touching the process environment.
+func ReadDotenv(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env, err := ParseDotenv(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", path, err)
+	}
+	return env, nil
+}
+
+// LoadDotenv applies the .env files at paths to the process environment, in
+// order. Variables that are already set are left alone, so real environment
+// settings take precedence over files. With no paths, ".env" is loaded.
+func LoadDotenv(paths ...string) error {
+	return loadDotenv(false, paths)
+}
+
+// OverloadDotenv is like LoadDotenv but overrides variables that are already set.
+func OverloadDotenv(paths ...string) error {
+	return loadDotenv(true, paths)
+}
+
+func loadDotenv(override bool, paths []string) error {
+	if len(paths) == 0 {
+		paths = []string{".env"}
+	}
+
+	for _, path := range paths {
+		env, err := ReadDotenv(path)
+		if err != nil {
+			return err
+		}
+		for key, value := range env {
+			if _, set := os.LookupEnv(key); set && !override {
+				continue
+			}
+			if err := os.Setenv(key, value); err != nil {
+				return fmt.Errorf("could not set %s from %s: %v", key, path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ExpandEnvStrict replaces $VAR and ${VAR} in s with values from the process
+// environment, like os.ExpandEnv, but returns an error naming every variable
+// that is not set instead of substituting empty strings.
+func ExpandEnvStrict(s string) (string, error) {
+	return ExpandStrict(s, os.LookupEnv)
+}
+
+// ExpandStrict is like ExpandEnvStrict but resolves variables with lookup.
+func ExpandStrict(s string, lookup func(string) (string, bool)) (string, error) {
+	missing := make(map[string]bool)
+	result := os.Expand(s, func(name string) string {
+		v, ok := lookup(name)
+		if !ok {
+			missing[name] = true
+		}
+		return v
+	})
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("%w: %s", ErrUndefinedVariable, strings.Join(names, ", "))
+}
@@ -0,0 +1,36 @@
+package osExt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	tests := []struct {
+		name string
+		in   string
+		want map[string]string
+	}{
+		{"plain", "A=1\nexport B=two # comment\n", map[string]string{"A": "1", "B": "two"}},
+		{"expand", "A=x\nB=${A}y\nC=\"$HOME\"\n", map[string]string{"A": "x", "B": "xy", "C": "/home/me"}},
+		{"single quoted", "A='$HOME\\n'\n", map[string]string{"A": `$HOME\n`}},
+		{"escapes", "A=\"a\\tb\\nc\"\n", map[string]string{"A": "a\tb\nc"}},
+		{"escaped dollar", "A=\"\\$HOME\"\n", map[string]string{"A": "$HOME"}},
+		{"escaped backslash", "A=\"\\\\$HOME\"\n", map[string]string{"A": `\/home/me`}},
+		{"escaped quote", "A=\"say \\\"$HOME\\\"\"\n", map[string]string{"A": `say "/home/me"`}},
+		{"multiline", "A=\"one\ntwo\"\n", map[string]string{"A": "one\ntwo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("ParseDotenv(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDotenv(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package osExt
+
+import (
+	"io/fs"
+	"iter"
+	"path/filepath"
+	"strings"
+
+	"github.com/C0d3-5t3w/myT00L5/pathExt"
+)
+
+// WalkGlob returns the paths under root that match pattern, in lexical order.
+// Patterns use forward slashes and path.Match syntax, plus "**" to match
+// any number of directories and braces for alternatives: "**/*.{go,mod}"
+// matches Go source and module files at any depth.
+// Paths matching any ignore pattern are skipped; see NewIgnoreMatcher for the
+// supported .gitignore syntax. The returned paths are joined to root.
+func WalkGlob(root, pattern string, ignore ...string) ([]string, error) {
+	var matches []string
+	for p, err := range GlobSeq(root, pattern, ignore...) {
+		if err != nil {
+			return matches, err
+		}
+		matches = append(matches, p)
+	}
+	return matches, nil
+}
+
+// GlobSeq is like WalkGlob but yields matches as they are found. Walking
+// stops at the first error, which is yielded with an empty path.
+func GlobSeq(root, pattern string, ignore ...string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		pat, err := pathExt.CompilePattern(pattern)
+		if err != nil {
+			yield("", err)
+			return
+		}
+		ignorer, err := NewIgnoreMatcher(ignore...)
+		if err != nil {
+			yield("", err)
+			return
+		}
+
+		// Without "**" nothing deeper than the pattern can match
+		maxDepth := pat.MaxDepth()
+
+		stopped := false
+		err = filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, name)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				return nil
+			}
+
+			rel = filepath.ToSlash(rel)
+			if ignorer.Match(rel, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if pat.Match(rel) && !yield(name, nil) {
+				stopped = true
+				return filepath.SkipAll
+			}
+			if d.IsDir() && maxDepth >= 0 && strings.Count(rel, "/")+1 >= maxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil && !stopped {
+			yield("", err)
+		}
+	}
+}
+
+// IgnoreMatcher matches slash-separated relative paths against a list of
+// .gitignore-style patterns. It is pathExt.Matcher, so matchers built by
+// either package can be shared.
+type IgnoreMatcher = pathExt.Matcher
+
+// NewIgnoreMatcher compiles .gitignore-style patterns; see pathExt.NewMatcher
+// for the supported syntax.
+func NewIgnoreMatcher(patterns ...string) (*IgnoreMatcher, error) {
+	return pathExt.NewMatcher(patterns...)
+}
+
+// ReadIgnoreFile reads patterns from a .gitignore-style file.
+func ReadIgnoreFile(name string) (*IgnoreMatcher, error) {
+	return pathExt.ReadMatcherFile(name)
+}
@@ -0,0 +1,108 @@
+package osExt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// MoveFile moves the regular file src to dst. When a plain rename is not
+// possible because src and dst are on different filesystems, the file is
+// copied to a temporary file next to dst, synced to disk, renamed into place,
+// and only then is src removed. Permissions and modification time are kept.
+func MoveFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("could not move %s across filesystems: not a regular file", src)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := writeAtomic(dst, in, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("could not move %s to %s: %w", src, dst, err)
+	}
+	// Best effort: a lost timestamp is not worth failing a completed move
+	_ = os.Chtimes(dst, info.ModTime(), info.ModTime())
+
+	in.Close()
+	return os.Remove(src)
+}
+
+// ReplaceFile atomically replaces the contents of path with the data read
+// from r: readers see either the old file or the new one, never a partial
+// write. An existing file's permissions are preserved; a new file gets 0644.
+func ReplaceFile(path string, r io.Reader) error {
+	perm := fs.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return writeAtomic(path, r, perm)
+}
+
+// WriteFileAtomic is like os.WriteFile but replaces path atomically.
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	return writeAtomic(path, bytes.NewReader(data), perm)
+}
+
+// writeAtomic writes r to a temporary file in the same directory as path,
+// syncs it, and renames it over path.
+func writeAtomic(path string, r io.Reader, perm fs.FileMode) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes a directory entry so a rename survives a crash. Not every
+// platform supports syncing directories, so failures are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	_ = d.Sync()
+}
@@ -0,0 +1,9 @@
+//go:build !(unix || js || wasip1 || windows)
+
+package osExt
+
+// isCrossDevice is not supported on this platform: rename errors are
+// returned as they are.
+func isCrossDevice(err error) bool {
+	return false
+}
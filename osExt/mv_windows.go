@@ -0,0 +1,13 @@
+package osExt
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDevice reports whether a rename failed because source and
+// destination are on different volumes.
+func isCrossDevice(err error) bool {
+	// ERROR_NOT_SAME_DEVICE
+	return errors.Is(err, syscall.EXDEV) || errors.Is(err, syscall.Errno(17))
+}
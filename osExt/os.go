@@ -49,37 +49,29 @@ func CopyFile(src, dst string) error {
 	return os.Chmod(dst, sourceInfo.Mode())
 }
 
-// CopyDir recursively copies a directory tree
-func CopyDir(src, dst string) error {
-	sourceInfo, err := os.Stat(src)
-	if err != nil {
-		return err
+// CopyDir recursively copies a directory tree. By default symlinks are
+// followed, special files such as FIFOs are copied by reading their contents,
+// and only permissions are kept; options can preserve timestamps, ownership
+// and links, skip or reject special files, exclude entries, or report what
+// would be copied.
+func CopyDir(src, dst string, opts ...CopyOption) error {
+	o := &copyOptions{}
+	for _, opt := range opts {
+		opt(o)
 	}
-
-	if err = os.MkdirAll(dst, sourceInfo.Mode()); err != nil {
-		return err
+	if o.err != nil {
+		return o.err
 	}
+	if o.report == nil {
+		o.report = &CopyReport{}
+	}
+	*o.report = CopyReport{}
 
-	entries, err := os.ReadDir(src)
+	sourceInfo, err := os.Stat(src)
 	if err != nil {
 		return err
 	}
-
-	for _, entry := range entries {
-		sourcePath := filepath.Join(src, entry.Name())
-		destPath := filepath.Join(dst, entry.Name())
-
-		if entry.IsDir() {
-			if err = CopyDir(sourcePath, destPath); err != nil {
-				return err
-			}
-		} else {
-			if err = CopyFile(sourcePath, destPath); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	return o.copyTree(src, dst, "", sourceInfo, make(map[string]bool))
 }
 
 // HumanFileSize returns a human-readable file size
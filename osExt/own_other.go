@@ -0,0 +1,10 @@
+//go:build !unix
+
+package osExt
+
+import "io/fs"
+
+// fileOwner is not supported on this platform.
+func fileOwner(info fs.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
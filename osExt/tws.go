@@ -0,0 +1,138 @@
+package osExt
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+)
+
+// TempWorkspace is a temporary directory with helpers for building paths and
+// files inside it, replacing the usual os.MkdirTemp + defer os.RemoveAll pair.
+//
+//	ws, err := osExt.NewTempWorkspace("build")
+//	if err != nil {
+//		return err
+//	}
+//	defer ws.Cleanup()
+type TempWorkspace struct {
+	dir string
+
+	mu       sync.Mutex
+	kept     bool
+	removed  bool
+	stopOnce sync.Once
+	stop     chan struct{} // Closed to end the signal watcher, if any
+}
+
+// NewTempWorkspace creates a new temporary directory whose name starts with prefix.
+func NewTempWorkspace(prefix string) (*TempWorkspace, error) {
+	dir, err := os.MkdirTemp("", prefix+"-*")
+	if err != nil {
+		return nil, err
+	}
+	return &TempWorkspace{dir: dir}, nil
+}
+
+// Dir returns the workspace directory.
+func (w *TempWorkspace) Dir() string {
+	return w.dir
+}
+
+// Path joins elem onto the workspace directory.
+func (w *TempWorkspace) Path(elem ...string) string {
+	return filepath.Join(append([]string{w.dir}, elem...)...)
+}
+
+// Mkdir creates a directory, and any missing parents, inside the workspace
+// and returns its full path.
+func (w *TempWorkspace) Mkdir(rel string) (string, error) {
+	path := w.Path(rel)
+	return path, os.MkdirAll(path, 0755)
+}
+
+// WriteFile writes data to a file inside the workspace, creating parent
+// directories as needed, and returns its full path.
+func (w *TempWorkspace) WriteFile(rel string, data []byte) (string, error) {
+	path := w.Path(rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// ReadFile reads a file inside the workspace.
+func (w *TempWorkspace) ReadFile(rel string) ([]byte, error) {
+	return os.ReadFile(w.Path(rel))
+}
+
+// Size returns the total size in bytes of the files in the workspace.
+func (w *TempWorkspace) Size() (int64, error) {
+	return DirSize(w.dir, nil)
+}
+
+// Keep stops Cleanup from removing the workspace, so its contents can be
+// inspected after a failure. It returns the directory path for logging.
+func (w *TempWorkspace) Keep() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.kept = true
+	return w.dir
+}
+
+// Cleanup removes the workspace and everything in it unless Keep was called.
+// It is safe to call more than once.
+func (w *TempWorkspace) Cleanup() error {
+	w.stopWatching()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.kept || w.removed {
+		return nil
+	}
+	w.removed = true
+	return os.RemoveAll(w.dir)
+}
+
+// CleanupOnSignal removes the workspace if the process receives one of sigs
+// (os.Interrupt by default) before Cleanup is called. It only cleans up and
+// never re-delivers the signal. Go hands each signal to every channel passed
+// to signal.Notify, so a program that watches sigs itself, for example with
+// signal.NotifyContext, still sees the same signal and shuts down as usual.
+// A program that does not watch them loses Go's default of exiting on the
+// first one: that signal is consumed by the cleanup, and only a second
+// signal, after the watcher has stopped, terminates the process.
+func (w *TempWorkspace) CleanupOnSignal(sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt}
+	}
+
+	w.mu.Lock()
+	if w.stop != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.stop = make(chan struct{})
+	stop := w.stop
+	w.mu.Unlock()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	go func() {
+		defer signal.Stop(ch)
+		select {
+		case <-stop:
+		case <-ch:
+			w.Cleanup()
+		}
+	}()
+}
+
+func (w *TempWorkspace) stopWatching() {
+	w.mu.Lock()
+	stop := w.stop
+	w.mu.Unlock()
+	if stop != nil {
+		w.stopOnce.Do(func() { close(stop) })
+	}
+}
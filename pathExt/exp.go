@@ -0,0 +1,99 @@
+package pathExt
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ExpandUser replaces a leading "~" with the current user's home directory
+// and "~name" with the home directory of user name, as a shell does.
+// Paths without a leading tilde are returned unchanged.
+func ExpandUser(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	name, rest := path[1:], ""
+	if i := strings.IndexAny(name, `/\`); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+
+	var home string
+	if name == "" {
+		h, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not expand %s: %v", path, err)
+		}
+		home = h
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", fmt.Errorf("could not expand %s: %v", path, err)
+		}
+		home = u.HomeDir
+	}
+	return home + rest, nil
+}
+
+// ExpandVars replaces $VAR and ${VAR} with the values of environment
+// variables, and on Windows also %VAR%. Undefined variables expand to the
+// empty string, except that unmatched %...% text is left alone.
+func ExpandVars(path string) string {
+	if runtime.GOOS == "windows" {
+		path = expandPercentVars(path)
+	}
+	return os.ExpandEnv(path)
+}
+
+// expandPercentVars replaces %VAR% with the value of VAR when it is set
+func expandPercentVars(s string) string {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(s, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+
+		name := s[start+1 : end]
+		if value, ok := os.LookupEnv(name); ok && name != "" {
+			sb.WriteString(s[:start])
+			sb.WriteString(value)
+			s = s[end+1:]
+		} else {
+			// Keep the first % and retry from the second, which may open a variable
+			sb.WriteString(s[:end])
+			s = s[end:]
+		}
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// NormalizeSlashes converts both forward slashes and backslashes to the
+// platform's separator, so paths written for either platform in config
+// files work on both. On Unix this means a backslash can no longer be part
+// of a file name.
+func NormalizeSlashes(path string) string {
+	sep := string(filepath.Separator)
+	return strings.NewReplacer("/", sep, `\`, sep).Replace(path)
+}
+
+// Abs expands a leading tilde and environment variables, normalises slashes
+// and returns the cleaned absolute path, so that a config value such as
+// "~/data" or "$XDG_DATA_HOME/app" resolves the way users expect.
+func Abs(path string) (string, error) {
+	expanded, err := ExpandUser(ExpandVars(path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(NormalizeSlashes(expanded))
+}
@@ -0,0 +1,135 @@
+package pathExt
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAttempts bounds the search for a free name in NextAvailable and
+// ExpandPathTemplate
+const maxAttempts = 100000
+
+var copySuffix = regexp.MustCompile(`\((\d+)\)$`)
+
+// NextAvailable returns path if nothing exists there, or otherwise the first
+// free name of the form "report(2).pdf", "report(3).pdf", ... If path already
+// carries such a suffix, numbering continues from it. Another process may
+// create the file before the caller does, so create it with O_EXCL when that
+// matters.
+func NextAvailable(path string) (string, error) {
+	if free, err := isFree(path); free {
+		return path, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	n := 2
+	if m := copySuffix.FindStringSubmatchIndex(stem); m != nil && m[0] > len(filepath.Dir(stem)) {
+		current, _ := strconv.Atoi(stem[m[2]:m[3]])
+		stem, n = stem[:m[0]], current+1
+	}
+
+	for i := 0; i < maxAttempts; i, n = i+1, n+1 {
+		candidate := fmt.Sprintf("%s(%d)%s", stem, n, ext)
+		if free, err := isFree(candidate); free {
+			return candidate, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("could not find a free name for %s", path)
+}
+
+// isFree reports whether nothing, not even a broken symlink, exists at path
+func isFree(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	return false, err
+}
+
+// TimestampedPath returns a path in dir named after prefix and the current
+// local time, such as "backup-20240131-154502.tar", which sorts
+// chronologically. The extension may be given with or without its dot.
+func TimestampedPath(dir, prefix, ext string) string {
+	name := time.Now().Format("20060102-150405")
+	if prefix != "" {
+		name = prefix + "-" + name
+	}
+	return filepath.Join(dir, ChangeExt(name, ext))
+}
+
+var placeholder = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)(?::([^}]*))?\}`)
+
+// ExpandPathTemplate builds a path from a template such as
+// "{date}/{name}-{seq}{ext}". Placeholders are replaced by entries of vars,
+// or by these built-ins:
+//
+//	{date}       current date, 2006-01-02
+//	{time}       current time, 150405
+//	{timestamp}  Unix time in seconds
+//	{seq}        the lowest number from 1 that gives a path that does not exist
+//
+// {date} and {time} accept a time layout, as in {date:2006/01}, and {seq} a
+// zero-padded width, as in {seq:3}. Unknown placeholders are an error.
+// Slashes in the template are converted to the platform's separator.
+func ExpandPathTemplate(template string, vars map[string]string) (string, error) {
+	now := time.Now()
+	hasSeq := false
+	var expandErr error
+
+	render := func(seq int) string {
+		return placeholder.ReplaceAllStringFunc(template, func(m string) string {
+			parts := placeholder.FindStringSubmatch(m)
+			name, arg := parts[1], parts[2]
+			if v, ok := vars[name]; ok {
+				return v
+			}
+			switch name {
+			case "date":
+				return now.Format(cmp.Or(arg, "2006-01-02"))
+			case "time":
+				return now.Format(cmp.Or(arg, "150405"))
+			case "timestamp":
+				return strconv.FormatInt(now.Unix(), 10)
+			case "seq":
+				hasSeq = true
+				width, err := strconv.Atoi(cmp.Or(arg, "0"))
+				if err != nil {
+					expandErr = fmt.Errorf("invalid width in %s", m)
+				}
+				return fmt.Sprintf("%0*d", width, seq)
+			}
+			expandErr = fmt.Errorf("unknown placeholder %s in path template", m)
+			return m
+		})
+	}
+
+	path := filepath.FromSlash(render(1))
+	if expandErr != nil {
+		return "", expandErr
+	}
+	if !hasSeq {
+		return path, nil
+	}
+	for seq := 1; seq <= maxAttempts; seq++ {
+		path = filepath.FromSlash(render(seq))
+		if free, err := isFree(path); free {
+			return path, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("could not find a free sequence number for %s", template)
+}
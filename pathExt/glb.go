@@ -0,0 +1,336 @@
+package pathExt
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Pattern is a compiled glob pattern for slash-separated paths. It supports
+// path.Match syntax in each segment, "**" for any number of directories and
+// brace alternatives such as "*.{go,mod}".
+type Pattern struct {
+	alts [][]string // Segments of each brace alternative
+}
+
+// CompilePattern compiles a glob pattern. Leading and trailing slashes are
+// ignored, so patterns are always relative.
+func CompilePattern(pattern string) (*Pattern, error) {
+	p := &Pattern{}
+	for _, alt := range ExpandBraces(filepath.ToSlash(pattern)) {
+		segs, err := splitPattern(alt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+		p.alts = append(p.alts, segs)
+	}
+	return p, nil
+}
+
+// splitPattern splits a pattern into segments and validates each one
+func splitPattern(pattern string) ([]string, error) {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	segs := strings.Split(pattern, "/")
+	for _, s := range segs {
+		if s == "**" {
+			continue
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, err
+		}
+	}
+	return segs, nil
+}
+
+// Match reports whether the slash-separated relative path matches the pattern
+func (p *Pattern) Match(rel string) bool {
+	segs := strings.Split(strings.Trim(rel, "/"), "/")
+	for _, alt := range p.alts {
+		if matchSegments(alt, segs) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxDepth returns the deepest number of path segments the pattern can match,
+// or -1 if it contains "**" and can match at any depth. Walkers use it to
+// avoid descending into directories that cannot contain matches.
+func (p *Pattern) MaxDepth() int {
+	depth := 0
+	for _, alt := range p.alts {
+		if slices.Contains(alt, "**") {
+			return -1
+		}
+		depth = max(depth, len(alt))
+	}
+	return depth
+}
+
+// Match reports whether the slash-separated path name matches pattern, which
+// may use "**" and brace alternatives; see Pattern.
+func Match(pattern, name string) (bool, error) {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return p.Match(name), nil
+}
+
+// matchSegments reports whether the path segments match the pattern segments,
+// with "**" standing for zero or more segments.
+func matchSegments(pat, segs []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchSegments(pat[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], segs[0]); !ok {
+			return false
+		}
+		pat, segs = pat[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// ExpandBraces expands brace alternatives in a pattern, as a shell does:
+// "a/{b,c{d,e}}.go" gives "a/b.go", "a/cd.go" and "a/ce.go". Braces without
+// a comma and unbalanced braces are left as they are, and a backslash
+// escapes the next character.
+func ExpandBraces(pattern string) []string {
+	open, close, commas := findBraces(pattern)
+	if open < 0 {
+		return []string{pattern}
+	}
+
+	prefix, suffix := pattern[:open], pattern[close+1:]
+	var result []string
+	start := open + 1
+	for _, end := range append(commas, close) {
+		for _, alt := range ExpandBraces(prefix + pattern[start:end] + suffix) {
+			if !slices.Contains(result, alt) {
+				result = append(result, alt)
+			}
+		}
+		start = end + 1
+	}
+	return result
+}
+
+// findBraces locates the first balanced brace group containing a top-level
+// comma, returning its bounds and the positions of those commas.
+func findBraces(s string) (open, close int, commas []int) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth, cs := 0, []int(nil)
+			for j := i; j < len(s); j++ {
+				switch s[j] {
+				case '\\':
+					j++
+				case '{':
+					depth++
+				case ',':
+					if depth == 1 {
+						cs = append(cs, j)
+					}
+				case '}':
+					depth--
+				}
+				if depth == 0 {
+					if len(cs) > 0 {
+						return i, j, cs
+					}
+					break
+				}
+			}
+		}
+	}
+	return -1, -1, nil
+}
+
+// hasMeta reports whether a pattern segment contains glob syntax
+func hasMeta(seg string) bool {
+	return seg == "**" || strings.ContainsAny(seg, `*?[\`)
+}
+
+// Glob returns the files and directories matching pattern in lexical order.
+// Unlike filepath.Glob it supports "**" to match any number of directories
+// and brace alternatives, as in "src/**/*.{go,mod}". Relative patterns are
+// resolved against the working directory and return relative paths.
+func Glob(pattern string) ([]string, error) {
+	var matches []string
+	for _, alt := range ExpandBraces(filepath.ToSlash(pattern)) {
+		found, err := globOne(alt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+		for _, m := range found {
+			if !slices.Contains(matches, m) {
+				matches = append(matches, m)
+			}
+		}
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// globOne matches a single brace-free pattern by walking from its longest
+// literal directory prefix.
+func globOne(pattern string) ([]string, error) {
+	segs := strings.Split(pattern, "/")
+	k := 0
+	for k < len(segs) && !hasMeta(segs[k]) {
+		k++
+	}
+
+	root := filepath.FromSlash(strings.Join(segs[:k], "/"))
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(pattern, "/") {
+			root = string(filepath.Separator)
+		}
+	}
+	if k == len(segs) {
+		if _, err := os.Lstat(root); err != nil {
+			return nil, nil
+		}
+		return []string{root}, nil
+	}
+
+	rest, err := splitPattern(strings.Join(segs[k:], "/"))
+	if err != nil {
+		return nil, err
+	}
+	maxDepth := len(rest)
+	if slices.Contains(rest, "**") {
+		maxDepth = -1
+	}
+
+	var matches []string
+	err = filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped, as with filepath.Glob
+			if name == root {
+				return filepath.SkipAll
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, name)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		relSegs := strings.Split(filepath.ToSlash(rel), "/")
+		if matchSegments(rest, relSegs) {
+			matches = append(matches, name)
+		}
+		if d.IsDir() && maxDepth >= 0 && len(relSegs) >= maxDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// Matcher matches slash-separated relative paths against a list of
+// .gitignore-style patterns.
+type Matcher struct {
+	rules []matchRule
+}
+
+type matchRule struct {
+	segs    []string // Pattern segments
+	negate  bool     // Pattern started with "!"
+	dirOnly bool     // Pattern ended with "/"
+}
+
+// NewMatcher compiles .gitignore-style patterns. Blank lines and lines
+// starting with "#" are skipped. A leading "!" re-includes paths excluded by
+// an earlier pattern, a trailing "/" matches only directories, and a pattern
+// containing a slash other than a trailing one is anchored to the root; any
+// other pattern matches a file or directory name at any depth. When several
+// patterns match, the last one wins. As in git, braces are not special.
+func NewMatcher(patterns ...string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		var r matchRule
+		if strings.HasPrefix(p, "!") {
+			r.negate = true
+			p = p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			r.dirOnly = true
+			p = strings.TrimRight(p, "/")
+		}
+		anchored := strings.Contains(p, "/")
+
+		segs, err := splitPattern(filepath.ToSlash(p))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+		if !anchored {
+			segs = append([]string{"**"}, segs...)
+		}
+		r.segs = segs
+		m.rules = append(m.rules, r)
+	}
+	return m, nil
+}
+
+// ReadMatcherFile reads patterns from a .gitignore-style file.
+func ReadMatcherFile(name string) (*Matcher, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read ignore file %s: %v", name, err)
+	}
+	return NewMatcher(patterns...)
+}
+
+// Match reports whether the relative path rel should be ignored.
+func (m *Matcher) Match(rel string, isDir bool) bool {
+	segs := strings.Split(strings.Trim(rel, "/"), "/")
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if matchSegments(r.segs, segs) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
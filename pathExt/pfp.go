@@ -81,7 +81,8 @@ func GetRelativePath(basePath, targetPath string) (string, error) {
 	return filepath.Rel(basePath, targetPath)
 }
 
-// IsSubPath checks if a path is a subpath of another path
+// IsSubPath checks if a path is a subpath of another path. The comparison is
+// purely lexical; use Within to also resolve symlinks.
 func IsSubPath(basePath, targetPath string) (bool, error) {
 	if basePath == targetPath {
 		return true, nil
@@ -92,5 +93,5 @@ func IsSubPath(basePath, targetPath string) (bool, error) {
 		return false, err
 	}
 
-	return !strings.HasPrefix(rel, ".."), nil
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
 }
@@ -0,0 +1,123 @@
+package pathExt
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// elements splits a cleaned path into its elements. An absolute path starts
+// with an empty element (or the volume name on Windows), so that prefixes
+// keep their absoluteness when joined again.
+func elements(path string) []string {
+	path = filepath.Clean(path)
+	if path == "." {
+		return nil
+	}
+	elems := strings.Split(path, string(filepath.Separator))
+	if len(elems) > 1 && elems[len(elems)-1] == "" {
+		elems = elems[:len(elems)-1] // The root itself
+	}
+	return elems
+}
+
+// joinElements is the inverse of elements
+func joinElements(elems []string) string {
+	if len(elems) == 1 && elems[0] == "" {
+		return string(filepath.Separator)
+	}
+	joined := strings.Join(elems, string(filepath.Separator))
+	if strings.HasSuffix(joined, ":") {
+		joined += string(filepath.Separator) // Root of a Windows drive
+	}
+	return joined
+}
+
+// CommonPrefix returns the longest directory path shared by all paths,
+// comparing whole elements so "/a/bc" and "/a/bd" share "/a", not "/a/b".
+// It returns "" if the paths share nothing, for example when one is
+// relative and another absolute.
+func CommonPrefix(paths ...string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	prefix := elements(paths[0])
+	for _, p := range paths[1:] {
+		elems := elements(p)
+		n := 0
+		for n < len(prefix) && n < len(elems) && prefix[n] == elems[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	if len(prefix) == 0 {
+		return ""
+	}
+	return joinElements(prefix)
+}
+
+// CommonSuffix returns the longest run of trailing elements shared by all
+// paths, such as "cmd/main.go" for "a/cmd/main.go" and "b/cmd/main.go",
+// or "" if the last elements differ.
+func CommonSuffix(paths ...string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	suffix := elements(paths[0])
+	for _, p := range paths[1:] {
+		elems := elements(p)
+		n := 0
+		for n < len(suffix) && n < len(elems) && suffix[len(suffix)-1-n] == elems[len(elems)-1-n] {
+			n++
+		}
+		suffix = suffix[len(suffix)-n:]
+	}
+	if len(suffix) == 0 || suffix[0] == "" {
+		// A whole absolute path in common is the path itself
+		return strings.Join(suffix, string(filepath.Separator))
+	}
+	return filepath.Join(suffix...)
+}
+
+// Depth returns the number of elements in the cleaned path, not counting the
+// root: "." and "/" have depth 0, "a/b" and "/a/b" depth 2. Leading ".."
+// elements are counted.
+func Depth(path string) int {
+	elems := elements(path)
+	if len(elems) > 0 && (elems[0] == "" || filepath.VolumeName(path) == elems[0]) {
+		elems = elems[1:]
+	}
+	return len(elems)
+}
+
+// Within reports whether p is base or inside it, after making both absolute
+// and resolving symlinks in the parts of them that exist, so a link inside
+// base that points elsewhere, even one whose target does not exist yet, is
+// correctly reported as outside.
+func Within(base, p string) (bool, error) {
+	resolved := make([]string, 2)
+	for i, path := range []string{base, p} {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return false, err
+		}
+		if resolved[i], err = resolveExisting(abs); err != nil {
+			return false, err
+		}
+	}
+	return lexicallyWithin(resolved[0], resolved[1]), nil
+}
+
+// ChangeExt replaces the extension of path with ext, which may be given with
+// or without its leading dot; an empty ext removes the extension.
+func ChangeExt(path, ext string) string {
+	path = strings.TrimSuffix(path, filepath.Ext(path))
+	if ext == "" {
+		return path
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return path + ext
+}
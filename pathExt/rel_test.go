@@ -0,0 +1,43 @@
+package pathExt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithin(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(base, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	symlinkOrSkip(t, outside, filepath.Join(base, "out"))
+	symlinkOrSkip(t, filepath.Join(outside, "missing"), filepath.Join(base, "evil"))
+	symlinkOrSkip(t, filepath.Join(base, "dir", "missing"), filepath.Join(base, "good"))
+
+	tests := []struct {
+		p    string
+		want bool
+	}{
+		{base, true},
+		{filepath.Join(base, "dir"), true},
+		{filepath.Join(base, "dir", "new.txt"), true},
+		{filepath.Join(base, "good"), true},
+		{filepath.Join(base, ".."), false},
+		{filepath.Join(base, "out"), false},
+		{filepath.Join(base, "out", "file"), false},
+		{filepath.Join(base, "evil"), false},
+		{filepath.Join(base, "evil", "file"), false},
+	}
+	for _, tt := range tests {
+		got, err := Within(base, tt.p)
+		if err != nil {
+			t.Errorf("Within(base, %q): %v", tt.p, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Within(base, %q) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
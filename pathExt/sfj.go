@@ -0,0 +1,114 @@
+package pathExt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPathEscapes is returned when a joined path would leave its base directory
+var ErrPathEscapes = errors.New("path escapes base directory")
+
+// SafeJoin joins parts onto base and cleans the result, returning an error
+// wrapping ErrPathEscapes if it would lie outside base, as with "../" or a
+// drive letter in a part. Absolute parts are treated as relative to base, so
+// a request path like "/css/site.css" maps into base. Symlinks are not
+// examined; use SafeJoinResolved when base may contain links planted by
+// untrusted users.
+func SafeJoin(base string, parts ...string) (string, error) {
+	for _, part := range parts {
+		if filepath.VolumeName(part) != "" {
+			return "", fmt.Errorf("could not join %q: %w", part, ErrPathEscapes)
+		}
+	}
+
+	base = filepath.Clean(base)
+	joined := filepath.Join(append([]string{base}, parts...)...)
+	if !lexicallyWithin(base, joined) {
+		return "", fmt.Errorf("could not join %q: %w", strings.Join(parts, string(filepath.Separator)), ErrPathEscapes)
+	}
+	return joined, nil
+}
+
+// SafeJoinResolved is like SafeJoin but also resolves symlinks in the part of
+// the result that exists, failing if a link leads outside base. The returned
+// path is the joined path, not the resolved one. Links can still be changed
+// after the check, so this is not a defence against a concurrent attacker.
+func SafeJoinResolved(base string, parts ...string) (string, error) {
+	joined, err := SafeJoin(base, parts...)
+	if err != nil {
+		return "", err
+	}
+
+	realBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %s: %v", base, err)
+	}
+	realJoined, err := resolveExisting(joined)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %s: %v", joined, err)
+	}
+	if !lexicallyWithin(realBase, realJoined) {
+		return "", fmt.Errorf("could not join %q: %w", strings.Join(parts, string(filepath.Separator)), ErrPathEscapes)
+	}
+	return joined, nil
+}
+
+// lexicallyWithin reports whether the clean path p is base or below it
+func lexicallyWithin(base, p string) bool {
+	rel, err := filepath.Rel(base, p)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// maxLinks bounds how many dangling links resolveExisting follows, so a
+// cycle of links fails instead of looping
+const maxLinks = 255
+
+// resolveExisting evaluates symlinks in the longest existing prefix of p and
+// appends the remaining, not yet existing, elements unchanged. A dangling
+// link in p is followed to its target, since creating the path would follow
+// it too.
+func resolveExisting(p string) (string, error) {
+	p = filepath.Clean(p)
+	var missing []string
+	for links := 0; ; {
+		real, err := filepath.EvalSymlinks(p)
+		if err == nil {
+			return filepath.Join(append([]string{real}, missing...)...), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		if fi, lerr := os.Lstat(p); lerr == nil && fi.Mode()&os.ModeSymlink != 0 {
+			if links++; links > maxLinks {
+				return "", fmt.Errorf("could not resolve %s: too many links", p)
+			}
+			target, err := os.Readlink(p)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				dir, err := filepath.EvalSymlinks(filepath.Dir(p))
+				if err != nil {
+					return "", err
+				}
+				target = filepath.Join(dir, target)
+			}
+			p = filepath.Clean(target)
+			continue
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return "", err
+		}
+		missing = append([]string{filepath.Base(p)}, missing...)
+		p = parent
+	}
+}
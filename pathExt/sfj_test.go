@@ -0,0 +1,78 @@
+package pathExt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSafeJoin(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+		err   bool
+	}{
+		{[]string{"a", "b"}, "base/a/b", false},
+		{[]string{"/css/site.css"}, "base/css/site.css", false},
+		{[]string{"a/../b"}, "base/b", false},
+		{[]string{".."}, "", true},
+		{[]string{"a", "../../etc"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := SafeJoin("base", tt.parts...)
+		if tt.err {
+			if !errors.Is(err, ErrPathEscapes) {
+				t.Errorf("SafeJoin(base, %q) error = %v, want ErrPathEscapes", tt.parts, err)
+			}
+			continue
+		}
+		if err != nil || got != filepath.FromSlash(tt.want) {
+			t.Errorf("SafeJoin(base, %q) = %q, %v, want %q", tt.parts, got, err, tt.want)
+		}
+	}
+}
+
+// symlinkOrSkip creates a symlink, skipping the test where that is not allowed
+func symlinkOrSkip(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("could not create symlink: %v", err)
+	}
+}
+
+func TestSafeJoinResolved(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(base, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	symlinkOrSkip(t, outside, filepath.Join(base, "out"))
+	symlinkOrSkip(t, "dir", filepath.Join(base, "in"))
+	symlinkOrSkip(t, filepath.Join(outside, "missing"), filepath.Join(base, "dangling"))
+	symlinkOrSkip(t, "../../"+filepath.Base(outside)+"/missing", filepath.Join(base, "dir", "rel"))
+	symlinkOrSkip(t, "hop", filepath.Join(base, "chain"))
+	symlinkOrSkip(t, "dangling", filepath.Join(base, "hop"))
+	symlinkOrSkip(t, "loop", filepath.Join(base, "loop"))
+
+	tests := []struct {
+		parts []string
+		err   bool
+	}{
+		{[]string{"dir", "new.txt"}, false},
+		{[]string{"in", "new.txt"}, false},
+		{[]string{"new", "deeper"}, false},
+		{[]string{"out", "file"}, true},
+		{[]string{"dangling"}, true},
+		{[]string{"dangling", "file"}, true},
+		{[]string{"dir", "rel"}, true},
+		{[]string{"chain"}, true},
+		{[]string{"loop"}, true},
+	}
+	for _, tt := range tests {
+		_, err := SafeJoinResolved(base, tt.parts...)
+		if (err != nil) != tt.err {
+			t.Errorf("SafeJoinResolved(base, %q) error = %v, want error %v", tt.parts, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package pluginExt
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+	"reflect"
+	"strings"
+)
+
+// Bind populates the function fields of the struct pointed to by target with
+// symbols looked up in p. Each field is bound to the symbol with the same
+// name, or to the name given in a `plugin:"Name"` tag. A tag of "-" skips the
+// field and the ",optional" suffix leaves a field nil when the symbol is
+// missing. Every type mismatch is reported, not just the first.
+//
+//	var api struct {
+//		Hello func(string) string
+//		Add   func(int, int) int `plugin:"Sum"`
+//	}
+//	err := pluginExt.Bind(p, &api)
+func Bind(p *plugin.Plugin, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a non-nil pointer to a struct, got %T", target)
+	}
+	sv := rv.Elem()
+	st := sv.Type()
+
+	var problems []error
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		name, optional, skip := parseBindTag(field)
+		if skip || !field.IsExported() {
+			continue
+		}
+		if field.Type.Kind() != reflect.Func {
+			problems = append(problems, fmt.Errorf("field %s is %v, not a function", field.Name, field.Type))
+			continue
+		}
+
+		sym, err := p.Lookup(name)
+		if err != nil {
+			if !optional {
+				problems = append(problems, fmt.Errorf("symbol %q not found: %w", name, err))
+			}
+			continue
+		}
+
+		fv := reflect.ValueOf(sym)
+		if !fv.Type().AssignableTo(field.Type) {
+			problems = append(problems, fmt.Errorf("symbol %q has type %v, field %s expects %v", name, fv.Type(), field.Name, field.Type))
+			continue
+		}
+		sv.Field(i).Set(fv)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("could not bind plugin to %v: %w", st, errors.Join(problems...))
+	}
+	return nil
+}
+
+// parseBindTag returns the symbol name for a field and whether it is optional or skipped.
+func parseBindTag(field reflect.StructField) (name string, optional, skip bool) {
+	tag, ok := field.Tag.Lookup("plugin")
+	if !ok {
+		return field.Name, false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, opts == "optional", false
+}
+
+// Construct calls the exported constructor symbol in p, which must be a
+// function taking no arguments and returning a single value (optionally
+// followed by an error), and stores the result in the interface pointed to by
+// target. It fails if the returned value does not implement the interface.
+//
+//	var store Store
+//	err := pluginExt.Construct(p, "NewStore", &store)
+func Construct(p *plugin.Plugin, constructor string, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("construct target must be a non-nil pointer to an interface, got %T", target)
+	}
+	iface := rv.Elem().Type()
+
+	sym, err := LookupSymbol(p, constructor)
+	if err != nil {
+		return err
+	}
+	fv := reflect.ValueOf(sym)
+	ft := fv.Type()
+	errorType := reflect.TypeFor[error]()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 0 || ft.NumOut() < 1 || ft.NumOut() > 2 ||
+		(ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return fmt.Errorf("symbol %q has type %v, expected func() T or func() (T, error)", constructor, ft)
+	}
+	if !ft.Out(0).Implements(iface) {
+		return fmt.Errorf("constructor %q returns %v, which does not implement %v", constructor, ft.Out(0), iface)
+	}
+
+	out := fv.Call(nil)
+	if len(out) == 2 && !out[1].IsNil() {
+		return fmt.Errorf("constructor %q failed: %w", constructor, out[1].Interface().(error))
+	}
+	rv.Elem().Set(out[0])
+	return nil
+}
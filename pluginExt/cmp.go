@@ -0,0 +1,222 @@
+package pluginExt
+
+import (
+	"debug/buildinfo"
+	"errors"
+	"fmt"
+	"plugin"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// InfoSymbol is the name of the symbol a plugin exports to take part in the
+// compatibility handshake. It may be a variable of type Info or a function
+// returning Info:
+//
+//	var PluginInfo = pluginExt.Info{Name: "store", Version: "1.4.0", APIVersion: "2.1.0"}
+const InfoSymbol = "PluginInfo"
+
+// ErrIncompatible is wrapped by every error returned from compatibility checks.
+var ErrIncompatible = errors.New("incompatible plugin")
+
+// Info describes a plugin for the compatibility handshake.
+type Info struct {
+	Name       string // Plugin name
+	Version    string // Version of the plugin itself
+	APIVersion string // Version of the host API the plugin was written against
+}
+
+// Constraints describes what the host accepts from a plugin.
+type Constraints struct {
+	MinAPIVersion string            // Lowest accepted API version, inclusive; empty means no lower bound
+	MaxAPIVersion string            // Highest accepted API version, exclusive; empty means no upper bound
+	GoVersion     string            // Required Go toolchain; empty means the host's runtime.Version()
+	BuildSettings map[string]string // Required build settings such as "-tags" or "CGO_ENABLED"
+}
+
+// CompatibilityError lists every reason a plugin was rejected.
+type CompatibilityError struct {
+	Plugin   string   // Path or name of the plugin
+	Problems []string // Human-readable descriptions of each mismatch
+}
+
+func (e *CompatibilityError) Error() string {
+	return fmt.Sprintf("incompatible plugin %s: %s", e.Plugin, strings.Join(e.Problems, "; "))
+}
+
+// Unwrap allows errors.Is(err, ErrIncompatible).
+func (e *CompatibilityError) Unwrap() error {
+	return ErrIncompatible
+}
+
+// CheckFile inspects the build information embedded in the plugin file at
+// path without loading it, verifying the Go version, target platform, build
+// settings and the versions of modules shared with the host. plugin.Open
+// fails with an opaque error, or even panics, on most of these mismatches.
+func CheckFile(path string, c Constraints) error {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read build info of plugin %s: %w", path, err)
+	}
+
+	var problems []string
+	wantGo := c.GoVersion
+	if wantGo == "" {
+		wantGo = runtime.Version()
+	}
+	if info.GoVersion != wantGo {
+		problems = append(problems, fmt.Sprintf("built with %s, host requires %s", info.GoVersion, wantGo))
+	}
+
+	settings := make(map[string]string, len(info.Settings))
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+	for key, want := range map[string]string{"GOOS": runtime.GOOS, "GOARCH": runtime.GOARCH} {
+		if got, ok := settings[key]; ok && got != want {
+			problems = append(problems, fmt.Sprintf("built for %s=%s, host is %s", key, got, want))
+		}
+	}
+	for key, want := range c.BuildSettings {
+		if got := settings[key]; got != want {
+			problems = append(problems, fmt.Sprintf("build setting %s is %q, expected %q", key, got, want))
+		}
+	}
+
+	if host, ok := debug.ReadBuildInfo(); ok {
+		problems = append(problems, moduleMismatches(host, info)...)
+	}
+
+	if len(problems) > 0 {
+		return &CompatibilityError{Plugin: path, Problems: problems}
+	}
+	return nil
+}
+
+// moduleMismatches reports dependencies that the host and plugin both use at different versions.
+func moduleMismatches(host, plug *debug.BuildInfo) []string {
+	hostVersions := make(map[string]string)
+	for _, dep := range host.Deps {
+		hostVersions[dep.Path] = moduleVersion(dep)
+	}
+
+	var problems []string
+	for _, dep := range plug.Deps {
+		want, ok := hostVersions[dep.Path]
+		if got := moduleVersion(dep); ok && got != want {
+			problems = append(problems, fmt.Sprintf("module %s is %s in plugin, %s in host", dep.Path, got, want))
+		}
+	}
+	return problems
+}
+
+func moduleVersion(m *debug.Module) string {
+	if m.Replace != nil {
+		return m.Replace.Path + "@" + m.Replace.Version
+	}
+	return m.Version
+}
+
+// ReadInfo performs the handshake by looking up the plugin's InfoSymbol.
+func ReadInfo(p *plugin.Plugin) (Info, error) {
+	sym, err := LookupSymbol(p, InfoSymbol)
+	if err != nil {
+		return Info{}, fmt.Errorf("%w: %w", ErrIncompatible, err)
+	}
+
+	switch v := sym.(type) {
+	case *Info:
+		return *v, nil
+	case func() Info:
+		return v(), nil
+	default:
+		return Info{}, fmt.Errorf("%w: symbol %q has type %T, expected pluginExt.Info or func() pluginExt.Info", ErrIncompatible, InfoSymbol, sym)
+	}
+}
+
+// CheckCompatibility performs the handshake with a loaded plugin and verifies
+// that its API version satisfies c. The plugin's Info is returned even when
+// it is rejected, so callers can log what was found.
+func CheckCompatibility(p *plugin.Plugin, c Constraints) (Info, error) {
+	info, err := ReadInfo(p)
+	if err != nil {
+		return info, err
+	}
+
+	var problems []string
+	if info.APIVersion == "" {
+		problems = append(problems, "plugin does not declare an API version")
+	} else {
+		if c.MinAPIVersion != "" && compareVersions(info.APIVersion, c.MinAPIVersion) < 0 {
+			problems = append(problems, fmt.Sprintf("API version %s is older than %s", info.APIVersion, c.MinAPIVersion))
+		}
+		if c.MaxAPIVersion != "" && compareVersions(info.APIVersion, c.MaxAPIVersion) >= 0 {
+			problems = append(problems, fmt.Sprintf("API version %s is not below %s", info.APIVersion, c.MaxAPIVersion))
+		}
+	}
+
+	if len(problems) > 0 {
+		name := info.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		return info, &CompatibilityError{Plugin: name, Problems: problems}
+	}
+	return info, nil
+}
+
+// OpenCompatible checks the plugin file, loads it and performs the handshake,
+// so that an incompatible plugin is rejected with a descriptive error instead
+// of failing inside plugin.Open.
+func OpenCompatible(path string, c Constraints) (*plugin.Plugin, Info, error) {
+	if err := CheckFile(path, c); err != nil {
+		return nil, Info{}, err
+	}
+	p, err := LoadOrError(path)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	info, err := CheckCompatibility(p, c)
+	if err != nil {
+		return nil, info, err
+	}
+	return p, info, nil
+}
+
+// compareVersions compares dotted numeric versions such as "1.2.10" or
+// "v2.0", returning -1, 0 or 1. Missing components count as zero and any
+// pre-release or build suffix is ignored.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	parts := make([]int, len(fields))
+	for i, f := range fields {
+		parts[i], _ = strconv.Atoi(f)
+	}
+	return parts
+}
@@ -0,0 +1,155 @@
+package pluginExt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Manifest describes a plugin. It is read from a JSON file next to the
+// shared object with the same base name (foo.so → foo.json).
+type Manifest struct {
+	Name    string   `json:"name"`              // Registry key; defaults to the file name without .so
+	Version string   `json:"version,omitempty"` // Free-form version string
+	Symbols []string `json:"symbols,omitempty"` // Entry symbols that must be exported by the plugin
+}
+
+// LoadedPlugin is a plugin found by Discover.
+type LoadedPlugin struct {
+	Manifest Manifest
+	Path     string
+	Plugin   *plugin.Plugin
+}
+
+// Registry holds discovered plugins keyed by name.
+type Registry struct {
+	mu      sync.RWMutex
+	plugins map[string]*LoadedPlugin
+}
+
+// NewRegistry creates an empty plugin registry.
+func NewRegistry() *Registry {
+	return &Registry{plugins: make(map[string]*LoadedPlugin)}
+}
+
+// Get returns the plugin registered under name.
+func (r *Registry) Get(name string) (*LoadedPlugin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	lp, ok := r.plugins[name]
+	return lp, ok
+}
+
+// Names returns the registered plugin names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.plugins))
+	for name := range r.plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Len returns the number of registered plugins.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.plugins)
+}
+
+// Register adds a plugin to the registry, failing if the name is already taken.
+func (r *Registry) Register(lp *LoadedPlugin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.plugins[lp.Manifest.Name]; ok {
+		return fmt.Errorf("plugin name %q used by both %s and %s", lp.Manifest.Name, existing.Path, lp.Path)
+	}
+	r.plugins[lp.Manifest.Name] = lp
+	return nil
+}
+
+var defaultCache = NewPluginCache()
+
+// Discover scans dir for .so files, loads each one through a shared cache
+// together with its optional manifest, and returns them in a registry keyed
+// by plugin name. Plugins that fail to load are skipped and reported in the
+// returned error; the registry still contains every plugin that loaded.
+func Discover(dir string) (*Registry, error) {
+	return defaultCache.Discover(dir)
+}
+
+// Discover is like the package-level Discover but loads plugins through pc.
+func (pc *PluginCache) Discover(dir string) (*Registry, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("could not scan plugin directory %s: %w", dir, err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return nil, fmt.Errorf("could not scan plugin directory %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	registry := NewRegistry()
+	var errs []error
+	for _, path := range paths {
+		lp, err := pc.loadWithManifest(path)
+		if err == nil {
+			err = registry.Register(lp)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return registry, errors.Join(errs...)
+}
+
+// loadWithManifest loads the plugin at path and checks that it exports the
+// entry symbols listed in its manifest.
+func (pc *PluginCache) loadWithManifest(path string) (*LoadedPlugin, error) {
+	manifest, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := pc.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, sym := range manifest.Symbols {
+		if _, err := LookupSymbol(p, sym); err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", path, err)
+		}
+	}
+	return &LoadedPlugin{Manifest: manifest, Path: path, Plugin: p}, nil
+}
+
+// ReadManifest reads the manifest for the plugin at soPath. When no manifest
+// file exists a default one named after the file is returned.
+func ReadManifest(soPath string) (Manifest, error) {
+	base := strings.TrimSuffix(filepath.Base(soPath), ".so")
+	manifest := Manifest{Name: base}
+
+	manifestPath := strings.TrimSuffix(soPath, ".so") + ".json"
+	data, err := os.ReadFile(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, fmt.Errorf("could not read plugin manifest %s: %w", manifestPath, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("could not parse plugin manifest %s: %w", manifestPath, err)
+	}
+	if manifest.Name == "" {
+		manifest.Name = base
+	}
+	return manifest, nil
+}
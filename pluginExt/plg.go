@@ -63,6 +63,8 @@ func LookupSymbol(p *plugin.Plugin, symbolName string) (plugin.Symbol, error) {
 }
 
 // LookupFunc looks up a symbol and ensures it's a function matching the expected type
+//
+// Deprecated: Use LookupAs, which returns the typed value directly.
 func LookupFunc(p *plugin.Plugin, symbolName string, expectedType interface{}) (reflect.Value, error) {
 	sym, err := LookupSymbol(p, symbolName)
 	if err != nil {
@@ -80,6 +82,27 @@ func LookupFunc(p *plugin.Plugin, symbolName string, expectedType interface{}) (
 	return reflect.ValueOf(sym), nil
 }
 
+// LookupAs looks up a symbol and returns it as a T. Function symbols are
+// returned as-is; exported variables, which plugins expose as pointers, are
+// dereferenced when T is the variable's type. Use LookupAs[*V] to get a
+// pointer that can modify the plugin's variable.
+func LookupAs[T any](p *plugin.Plugin, symbolName string) (T, error) {
+	var zero T
+	sym, err := LookupSymbol(p, symbolName)
+	if err != nil {
+		return zero, err
+	}
+
+	if v, ok := sym.(T); ok {
+		return v, nil
+	}
+	if ptr, ok := sym.(*T); ok {
+		return *ptr, nil
+	}
+	return zero, fmt.Errorf("symbol %q has type %T, expected %v or %v",
+		symbolName, sym, reflect.TypeFor[T](), reflect.TypeFor[*T]())
+}
+
 // LoadAndLookup combines loading a plugin and looking up a symbol
 func LoadAndLookup(path, symbolName string) (plugin.Symbol, error) {
 	p, err := plugin.Open(path)
@@ -0,0 +1,81 @@
+// Package pprofExt serves runtime diagnostics over HTTP. It is separate
+// from runtimeExt because, like importing net/http/pprof and expvar,
+// importing it registers the pprof and expvar handlers on
+// http.DefaultServeMux; servers that mount Handler should use their own mux.
+package pprofExt
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/C0d3-5t3w/myT00L5/debugExt"
+	"github.com/C0d3-5t3w/myT00L5/runtimeExt"
+)
+
+// Handler returns an http.Handler exposing runtime diagnostics under /debug/:
+//
+//	/debug/pprof/      pprof index and profiles
+//	/debug/gc          garbage collector and heap statistics (JSON)
+//	/debug/goroutines  goroutine statistics (JSON, ?traces=1 for stacks)
+//	/debug/buildinfo   module and build information (JSON)
+//	/debug/vars        expvar metrics
+//
+// Mount it with mux.Handle("/debug/", pprofExt.Handler()).
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("/debug/gc", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, runtimeExt.GetGCStats())
+	})
+
+	mux.HandleFunc("/debug/goroutines", func(w http.ResponseWriter, r *http.Request) {
+		traces := r.URL.Query().Get("traces") != ""
+		stats, err := runtimeExt.GetGoroutineStats(traces)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, stats)
+	})
+
+	mux.HandleFunc("/debug/buildinfo", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, debugExt.BuildInfo())
+	})
+
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	mux.HandleFunc("/debug/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/debug/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><body><h1>Debug</h1><ul>
+<li><a href="pprof/">pprof</a></li>
+<li><a href="gc">gc</a></li>
+<li><a href="goroutines">goroutines</a></li>
+<li><a href="buildinfo">buildinfo</a></li>
+<li><a href="vars">vars</a></li>
+</ul></body></html>`)
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,140 @@
+package regexpExt
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+)
+
+// DefaultCacheSize is the capacity of the cache behind the Cached helpers.
+const DefaultCacheSize = 256
+
+// Cache is a size-bounded LRU cache of compiled patterns, safe for
+// concurrent use. Patterns that fail to compile are not cached.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // most recently used at the front
+	entries  map[string]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+type cacheEntry struct {
+	pattern string
+	matcher *Matcher
+}
+
+// NewCache creates a cache holding up to capacity patterns; values below 1
+// are treated as 1.
+func NewCache(capacity int) *Cache {
+	return &Cache{
+		capacity: max(capacity, 1),
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the compiled Matcher for pattern, compiling and caching it
+// on first use and evicting the least recently used pattern when full.
+func (c *Cache) Get(pattern string) (*Matcher, error) {
+	c.mu.Lock()
+	if el, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		c.mu.Unlock()
+		return el.Value.(*cacheEntry).matcher, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Compile outside the lock; a concurrent miss on the same pattern just
+	// compiles it twice
+	m, err := New(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*cacheEntry).matcher, nil
+	}
+	c.entries[pattern] = c.order.PushFront(&cacheEntry{pattern: pattern, matcher: m})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).pattern)
+	}
+	return m, nil
+}
+
+// MustGet is like Get but panics if the pattern does not compile.
+func (c *Cache) MustGet(pattern string) *Matcher {
+	m, err := c.Get(pattern)
+	if err != nil {
+		panic(`regexpExt: Compile(` + strconv.Quote(pattern) + `): ` + err.Error())
+	}
+	return m
+}
+
+// Len returns the number of cached patterns.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the number of lookups served from the cache and the number
+// that had to compile.
+func (c *Cache) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Clear removes every cached pattern.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
+
+var defaultCache = NewCache(DefaultCacheSize)
+
+// Cached returns the compiled Matcher for pattern from the package-level cache.
+func Cached(pattern string) (*Matcher, error) {
+	return defaultCache.Get(pattern)
+}
+
+// CachedMatch reports whether s contains a match of pattern, compiling the
+// pattern at most once while it stays in the package-level cache.
+func CachedMatch(pattern, s string) (bool, error) {
+	m, err := defaultCache.Get(pattern)
+	if err != nil {
+		return false, err
+	}
+	return m.MatchString(s), nil
+}
+
+// CachedFindAll returns up to n matches of pattern in s, or all of them if
+// n is negative, using the package-level cache.
+func CachedFindAll(pattern, s string, n int) ([]string, error) {
+	m, err := defaultCache.Get(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return m.FindAllString(s, n), nil
+}
+
+// CachedReplaceAll replaces matches of pattern in s with repl, which may
+// refer to groups as in regexp.Expand, using the package-level cache.
+func CachedReplaceAll(pattern, s, repl string) (string, error) {
+	m, err := defaultCache.Get(pattern)
+	if err != nil {
+		return "", err
+	}
+	return m.ReplaceAllString(s, repl), nil
+}
@@ -0,0 +1,187 @@
+package regexpExt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FromGlob compiles a path glob into an anchored Matcher. The syntax follows
+// pathExt: "*" and "?" match within a path segment, "**" as a whole segment
+// matches any number of directories, "[a-z]" and "[!a-z]" are character
+// classes, "{a,b}" are alternatives and a backslash escapes the next
+// character. For example "src/**/*.{go,mod}" matches "src/main.go" and
+// "src/a/b/go.mod".
+func FromGlob(glob string) (*Matcher, error) {
+	expr, err := GlobToRegexp(glob)
+	if err != nil {
+		return nil, err
+	}
+	return New(expr)
+}
+
+// FromWildcard compiles a plain wildcard pattern into an anchored Matcher.
+// Unlike FromGlob, slashes are ordinary characters: "*" matches any run of
+// characters and "?" any single character, which suits route patterns and
+// other strings that are not paths. Classes, alternatives and escapes work
+// as in FromGlob.
+func FromWildcard(pattern string) (*Matcher, error) {
+	expr, err := WildcardToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return New(expr)
+}
+
+// GlobToRegexp returns the anchored regular expression FromGlob compiles.
+func GlobToRegexp(glob string) (string, error) {
+	return wildcardToRegexp(glob, true)
+}
+
+// WildcardToRegexp returns the anchored regular expression FromWildcard compiles.
+func WildcardToRegexp(pattern string) (string, error) {
+	return wildcardToRegexp(pattern, false)
+}
+
+func wildcardToRegexp(pattern string, paths bool) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	var braces []int // closing positions of the open alternative groups
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\':
+			if i+1 == len(pattern) {
+				return "", fmt.Errorf("regexpExt: trailing backslash in pattern %q", pattern)
+			}
+			i++
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '*':
+			stars := 1
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				stars++
+			}
+			start := i - stars + 1
+			wholeSegment := (start == 0 || pattern[start-1] == '/') && (i+1 == len(pattern) || pattern[i+1] == '/')
+			switch {
+			case !paths:
+				sb.WriteString(".*")
+			case stars >= 2 && wholeSegment && i+1 < len(pattern):
+				// "**/" matches zero or more directories
+				sb.WriteString("(?:.*/)?")
+				i++
+			case stars >= 2 && wholeSegment:
+				sb.WriteString(".*")
+			default:
+				sb.WriteString("[^/]*")
+			}
+		case c == '?':
+			if paths {
+				sb.WriteString("[^/]")
+			} else {
+				sb.WriteString(".")
+			}
+		case c == '[':
+			class, end, err := globClass(pattern, i, paths)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(class)
+			i = end
+		case c == '{':
+			if end, ok := braceGroupEnd(pattern, i); ok {
+				braces = append(braces, end)
+				sb.WriteString("(?:")
+			} else {
+				sb.WriteString(`\{`)
+			}
+		case c == ',' && len(braces) > 0:
+			sb.WriteString("|")
+		case c == '}' && len(braces) > 0 && braces[len(braces)-1] == i:
+			braces = braces[:len(braces)-1]
+			sb.WriteString(")")
+		default:
+			// Copy a whole UTF-8 sequence at once
+			j := i + 1
+			for j < len(pattern) && pattern[j] >= 0x80 && pattern[j] < 0xC0 {
+				j++
+			}
+			sb.WriteString(regexp.QuoteMeta(pattern[i:j]))
+			i = j - 1
+		}
+	}
+	sb.WriteString("$")
+	return sb.String(), nil
+}
+
+// globClass converts the character class starting at pattern[start] and
+// returns it with the index of its closing bracket. Negated classes in path
+// globs never match a slash.
+func globClass(pattern string, start int, paths bool) (string, int, error) {
+	var sb strings.Builder
+	sb.WriteString("[")
+	i := start + 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		sb.WriteString("^")
+		if paths {
+			sb.WriteString("/")
+		}
+		i++
+	}
+	first := true
+	for ; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == ']' && !first:
+			sb.WriteString("]")
+			return sb.String(), i, nil
+		case c == '\\':
+			if i+1 == len(pattern) {
+				return "", 0, fmt.Errorf("regexpExt: unterminated character class in pattern %q", pattern)
+			}
+			i++
+			sb.WriteString(`\`)
+			sb.WriteByte(pattern[i])
+		case c == '[' || c == ']' || c == '^':
+			sb.WriteString(`\`)
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+		first = false
+	}
+	return "", 0, fmt.Errorf("regexpExt: unterminated character class in pattern %q", pattern)
+}
+
+// braceGroupEnd returns the index of the brace closing the group opened at
+// pattern[open], if the group is balanced and has a top-level comma.
+// Other braces are matched literally, as in pathExt.ExpandBraces.
+func braceGroupEnd(pattern string, open int) (int, bool) {
+	depth := 0
+	hasComma := false
+	for i := open; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			// Braces and commas inside classes are literal
+			if _, end, err := globClass(pattern, i, false); err == nil {
+				i = end
+			}
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				hasComma = true
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, hasComma
+			}
+		}
+	}
+	return 0, false
+}
@@ -78,10 +78,11 @@ func (m *Matcher) ExtractGroups(s string) map[string]string {
 	return result
 }
 
-// MatchAny returns true if the string matches any of the provided patterns
+// MatchAny returns true if the string matches any of the provided patterns.
+// Patterns are compiled through the package-level cache.
 func MatchAny(s string, patterns []string) bool {
 	for _, pattern := range patterns {
-		if matched, _ := regexp.MatchString(pattern, s); matched {
+		if matched, _ := CachedMatch(pattern, s); matched {
 			return true
 		}
 	}
@@ -143,3 +144,51 @@ func (m *Matcher) Replace(s string, replacer func(Match) string) string {
 
 	return result.String()
 }
+
+// ReplaceSubmatches replaces all matches of the regexp with the result of
+// the replacer, which receives the whole match followed by each capture
+// group, with "" for groups that did not participate. Each call gets its own
+// slice.
+func (m *Matcher) ReplaceSubmatches(s string, replacer func(groups []string) string) string {
+	matches := m.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var result strings.Builder
+	lastEnd := 0
+
+	for _, match := range matches {
+		// A fresh slice per match, so the replacer may keep it
+		groups := make([]string, m.NumSubexp()+1)
+		for i := range groups {
+			groups[i] = ""
+			if match[2*i] >= 0 {
+				groups[i] = s[match[2*i]:match[2*i+1]]
+			}
+		}
+		result.WriteString(s[lastEnd:match[0]])
+		result.WriteString(replacer(groups))
+		lastEnd = match[1]
+	}
+
+	result.WriteString(s[lastEnd:])
+	return result.String()
+}
+
+// SplitKeep splits s around the matches of the regexp and keeps the matches:
+// the result alternates tokens and delimiters, starting and ending with a
+// token, so tokens are at even indexes and joining the parts gives back s.
+// Tokens may be empty, as when s starts with a delimiter.
+func (m *Matcher) SplitKeep(s string) []string {
+	matches := m.FindAllStringIndex(s, -1)
+	parts := make([]string, 0, 2*len(matches)+1)
+	lastEnd := 0
+
+	for _, match := range matches {
+		parts = append(parts, s[lastEnd:match[0]], s[match[0]:match[1]])
+		lastEnd = match[1]
+	}
+
+	return append(parts, s[lastEnd:])
+}
@@ -0,0 +1,191 @@
+package regexpExt
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoMatch is returned by Scan when the input does not match.
+var ErrNoMatch = errors.New("regexpExt: no match")
+
+// defaultTimeLayouts are tried for time.Time fields without a layout tag.
+var defaultTimeLayouts = []string{time.RFC3339Nano, time.DateTime, time.DateOnly, time.TimeOnly, "15:04"}
+
+// Scan matches s and stores the named capture groups in the struct dst
+// points to. A field receives the group named by its `re:"name"` tag, or
+// the group whose name equals the field name ignoring case. Values are
+// converted to the field's type: strings, integers, floats, booleans,
+// time.Duration, encoding.TextUnmarshaler and time.Time, parsed with the
+// layout in a `layout:"..."` tag or else RFC 3339 and common date and time
+// forms. Groups that did not take part in the match leave their fields
+// unchanged. Scan returns ErrNoMatch if s does not match.
+func (m *Matcher) Scan(s string, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("regexpExt: Scan needs a non-nil struct pointer, not %T", dst)
+	}
+	loc := m.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return ErrNoMatch
+	}
+	return m.bind(s, loc, v.Elem(), m.scanFields(v.Elem().Type()))
+}
+
+// ScanAll returns a T for every non-overlapping match in s, filled in as by
+// Matcher.Scan. It returns an empty slice if nothing matches.
+func ScanAll[T any](m *Matcher, s string) ([]T, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("regexpExt: ScanAll needs a struct type, not %v", t)
+	}
+	fields := m.scanFields(t)
+	matches := m.FindAllStringSubmatchIndex(s, -1)
+	out := make([]T, len(matches))
+	for i, loc := range matches {
+		if err := m.bind(s, loc, reflect.ValueOf(&out[i]).Elem(), fields); err != nil {
+			return nil, fmt.Errorf("match %d: %w", i+1, err)
+		}
+	}
+	return out, nil
+}
+
+// scanField links a capture group to a struct field.
+type scanField struct {
+	group  int
+	name   string
+	index  []int
+	layout string
+}
+
+// scanFields maps the named groups of m onto the fields of t.
+func (m *Matcher) scanFields(t reflect.Type) []scanField {
+	var fields []scanField
+	for group, name := range m.SubexpNames() {
+		if group == 0 || name == "" {
+			continue
+		}
+		sf, ok := findScanField(t, name)
+		if !ok {
+			continue
+		}
+		fields = append(fields, scanField{group: group, name: name, index: sf.Index, layout: sf.Tag.Get("layout")})
+	}
+	return fields
+}
+
+// findScanField finds the field for group name, preferring tags over names.
+func findScanField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for _, sf := range reflect.VisibleFields(t) {
+		if sf.IsExported() && sf.Tag.Get("re") == name {
+			return sf, true
+		}
+	}
+	for _, sf := range reflect.VisibleFields(t) {
+		if sf.IsExported() && !sf.Anonymous && sf.Tag.Get("re") == "" && strings.EqualFold(sf.Name, name) {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// bind stores the groups of one match in v.
+func (m *Matcher) bind(s string, loc []int, v reflect.Value, fields []scanField) error {
+	for _, f := range fields {
+		start, end := loc[2*f.group], loc[2*f.group+1]
+		if start < 0 {
+			continue
+		}
+		fv := fieldByIndexAlloc(v, f.index)
+		if err := setScanValue(fv, s[start:end], f.layout); err != nil {
+			return fmt.Errorf("regexpExt: group %s: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
+// fieldByIndexAlloc walks index, allocating nil embedded struct pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// setScanValue converts text to the type of v and stores it.
+func setScanValue(v reflect.Value, text, layout string) error {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if err := setScanValue(ptr.Elem(), text, layout); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	switch {
+	case v.Type() == reflect.TypeFor[time.Time]():
+		layouts := defaultTimeLayouts
+		if layout != "" {
+			layouts = []string{layout}
+		}
+		for _, l := range layouts {
+			if t, err := time.Parse(l, text); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot parse %q as a time", text)
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Addr().Type().Implements(reflect.TypeFor[encoding.TextUnmarshaler]()):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %v", v.Type())
+	}
+	return nil
+}
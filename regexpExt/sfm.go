@@ -0,0 +1,189 @@
+package regexpExt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"time"
+)
+
+// Limits applied by NewSafeMatcher unless overridden.
+const (
+	DefaultMaxPatternLength = 1024
+	DefaultMaxProgramSize   = 10000
+	DefaultMaxInputLength   = 1 << 20
+)
+
+var (
+	// ErrPatternTooComplex is returned for patterns over the length or
+	// compiled size limits of a SafeMatcher.
+	ErrPatternTooComplex = errors.New("regexpExt: pattern too complex")
+	// ErrInputTooLong is returned for inputs over a SafeMatcher's length limit.
+	ErrInputTooLong = errors.New("regexpExt: input too long")
+)
+
+// MatchWithTimeout reports whether s contains a match, giving up with the
+// context's error once ctx is done. Go's regexp engine runs in time linear
+// in the input and cannot be interrupted, so an abandoned match finishes in
+// the background; bound the input length to bound that work.
+func (m *Matcher) MatchWithTimeout(ctx context.Context, s string) (bool, error) {
+	return runWithContext(ctx, func() bool {
+		return m.MatchString(s)
+	})
+}
+
+// runWithContext runs fn, returning early with ctx.Err() if ctx ends first.
+func runWithContext[T any](ctx context.Context, fn func() T) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	done := make(chan T, 1)
+	go func() {
+		done <- fn()
+	}()
+	select {
+	case v := <-done:
+		return v, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// SafeOption configures a SafeMatcher.
+type SafeOption func(*SafeMatcher)
+
+// MaxPatternLength limits the length of the pattern in bytes; 0 means no limit.
+func MaxPatternLength(n int) SafeOption {
+	return func(sm *SafeMatcher) {
+		sm.maxPatternLen = n
+	}
+}
+
+// MaxProgramSize limits the number of instructions in the compiled pattern,
+// which catches short patterns that expand, like (hello world){1000}; 0
+// means no limit.
+func MaxProgramSize(n int) SafeOption {
+	return func(sm *SafeMatcher) {
+		sm.maxProgramSize = n
+	}
+}
+
+// MaxInputLength limits the length of matched input in bytes; 0 means no limit.
+func MaxInputLength(n int) SafeOption {
+	return func(sm *SafeMatcher) {
+		sm.maxInputLen = n
+	}
+}
+
+// MatchTimeout bounds every operation by d in addition to the caller's
+// context; 0 means only the context applies.
+func MatchTimeout(d time.Duration) SafeOption {
+	return func(sm *SafeMatcher) {
+		sm.timeout = d
+	}
+}
+
+// SafeMatcher matches user-supplied patterns within fixed bounds: the
+// pattern's length and compiled size are checked up front, inputs over a
+// length cap are rejected, and every call honours a context deadline. Only
+// the bounded methods are exposed.
+type SafeMatcher struct {
+	m              *Matcher
+	maxPatternLen  int
+	maxProgramSize int
+	maxInputLen    int
+	timeout        time.Duration
+}
+
+// NewSafeMatcher compiles pattern with the default limits, adjusted by opts.
+func NewSafeMatcher(pattern string, opts ...SafeOption) (*SafeMatcher, error) {
+	sm := &SafeMatcher{
+		maxPatternLen:  DefaultMaxPatternLength,
+		maxProgramSize: DefaultMaxProgramSize,
+		maxInputLen:    DefaultMaxInputLength,
+	}
+	for _, opt := range opts {
+		opt(sm)
+	}
+
+	if sm.maxPatternLen > 0 && len(pattern) > sm.maxPatternLen {
+		return nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrPatternTooComplex, len(pattern), sm.maxPatternLen)
+	}
+	if sm.maxProgramSize > 0 {
+		re, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		prog, err := syntax.Compile(re.Simplify())
+		if err != nil {
+			return nil, err
+		}
+		if len(prog.Inst) > sm.maxProgramSize {
+			return nil, fmt.Errorf("%w: %d instructions exceeds limit of %d", ErrPatternTooComplex, len(prog.Inst), sm.maxProgramSize)
+		}
+	}
+
+	m, err := New(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sm.m = m
+	return sm, nil
+}
+
+// bound checks s against the input limit and applies the timeout to ctx.
+func (sm *SafeMatcher) bound(ctx context.Context, s string) (context.Context, context.CancelFunc, error) {
+	if sm.maxInputLen > 0 && len(s) > sm.maxInputLen {
+		return nil, nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrInputTooLong, len(s), sm.maxInputLen)
+	}
+	if sm.timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, sm.timeout)
+		return ctx, cancel, nil
+	}
+	return ctx, func() {}, nil
+}
+
+// Match reports whether s contains a match.
+func (sm *SafeMatcher) Match(ctx context.Context, s string) (bool, error) {
+	ctx, cancel, err := sm.bound(ctx, s)
+	if err != nil {
+		return false, err
+	}
+	defer cancel()
+	return sm.m.MatchWithTimeout(ctx, s)
+}
+
+// Find returns the leftmost match in s and whether there was one.
+func (sm *SafeMatcher) Find(ctx context.Context, s string) (string, bool, error) {
+	ctx, cancel, err := sm.bound(ctx, s)
+	if err != nil {
+		return "", false, err
+	}
+	defer cancel()
+	loc, err := runWithContext(ctx, func() []int {
+		return sm.m.FindStringIndex(s)
+	})
+	if err != nil || loc == nil {
+		return "", false, err
+	}
+	return s[loc[0]:loc[1]], true, nil
+}
+
+// FindAll returns up to n matches in s, or all of them if n is negative.
+func (sm *SafeMatcher) FindAll(ctx context.Context, s string, n int) ([]string, error) {
+	ctx, cancel, err := sm.bound(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return runWithContext(ctx, func() []string {
+		return sm.m.FindAllString(s, n)
+	})
+}
+
+// String returns the pattern.
+func (sm *SafeMatcher) String() string {
+	return sm.m.String()
+}
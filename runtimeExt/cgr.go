@@ -0,0 +1,145 @@
+package runtimeExt
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup filesystem is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// memoryLimitHeadroom is the fraction of the container memory limit handed
+// to the Go runtime, leaving room for non-heap memory.
+const memoryLimitHeadroom = 0.9
+
+// EffectiveCPULimit returns the number of CPUs the process may use according
+// to its cgroup (v1 or v2) CPU quota. The second result is false when no
+// quota is set or cgroups are unavailable.
+func EffectiveCPULimit() (float64, bool) {
+	// cgroup v2: "max 100000" or "<quota> <period>"
+	if data, ok := readCgroupFile("", "cpu.max"); ok {
+		fields := strings.Fields(data)
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, err1 := strconv.ParseFloat(fields[0], 64)
+			period, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+				return quota / period, true
+			}
+		}
+		return 0, false
+	}
+
+	// cgroup v1: a quota of -1 means unlimited
+	quotaText, ok1 := readCgroupFile("cpu", "cpu.cfs_quota_us")
+	periodText, ok2 := readCgroupFile("cpu", "cpu.cfs_period_us")
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	quota, err1 := strconv.ParseFloat(quotaText, 64)
+	period, err2 := strconv.ParseFloat(periodText, 64)
+	if err1 != nil || err2 != nil || quota <= 0 || period <= 0 {
+		return 0, false
+	}
+	return quota / period, true
+}
+
+// EffectiveMemoryLimit returns the memory limit in bytes imposed by the
+// process's cgroup (v1 or v2). The second result is false when no limit is
+// set or cgroups are unavailable.
+func EffectiveMemoryLimit() (int64, bool) {
+	if data, ok := readCgroupFile("", "memory.max"); ok {
+		if data == "max" {
+			return 0, false
+		}
+		limit, err := strconv.ParseInt(data, 10, 64)
+		return limit, err == nil && limit > 0
+	}
+
+	data, ok := readCgroupFile("memory", "memory.limit_in_bytes")
+	if !ok {
+		return 0, false
+	}
+	limit, err := strconv.ParseInt(data, 10, 64)
+	// cgroup v1 reports "unlimited" as a huge page-aligned number
+	if err != nil || limit <= 0 || limit >= math.MaxInt64/2 {
+		return 0, false
+	}
+	return limit, true
+}
+
+// TuneForContainer sets GOMAXPROCS and the Go memory limit from the cgroup
+// CPU quota and memory limit. Settings made explicitly through the GOMAXPROCS
+// and GOMEMLIMIT environment variables are left untouched. Returns the
+// resulting GOMAXPROCS and memory limit.
+func TuneForContainer() (int, int64) {
+	if os.Getenv("GOMAXPROCS") == "" {
+		if cpus, ok := EffectiveCPULimit(); ok {
+			procs := int(math.Ceil(cpus))
+			if procs < 1 {
+				procs = 1
+			}
+			if procs < runtime.NumCPU() {
+				runtime.GOMAXPROCS(procs)
+			}
+		}
+	}
+
+	if os.Getenv("GOMEMLIMIT") == "" {
+		if limit, ok := EffectiveMemoryLimit(); ok {
+			debug.SetMemoryLimit(int64(float64(limit) * memoryLimitHeadroom))
+		}
+	}
+
+	return runtime.GOMAXPROCS(0), debug.SetMemoryLimit(-1)
+}
+
+// readCgroupFile reads a cgroup control file for the given v1 controller,
+// or from the unified v2 hierarchy when controller is empty.
+func readCgroupFile(controller, name string) (string, bool) {
+	for _, dir := range cgroupDirs(controller) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return strings.TrimSpace(string(data)), true
+		}
+	}
+	return "", false
+}
+
+// cgroupDirs returns candidate directories for a controller, starting with
+// the process's own cgroup path from /proc/self/cgroup and falling back to
+// the mount root, which is what containers usually see.
+func cgroupDirs(controller string) []string {
+	base := cgroupRoot
+	if controller != "" {
+		base = filepath.Join(cgroupRoot, controller)
+	}
+
+	var dirs []string
+	if data, err := os.ReadFile("/proc/self/cgroup"); err == nil {
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			// Format: hierarchy-ID:controller-list:cgroup-path
+			parts := strings.SplitN(line, ":", 3)
+			if len(parts) != 3 {
+				continue
+			}
+
+			if controller == "" && parts[0] == "0" && parts[1] == "" {
+				dirs = append(dirs, filepath.Join(base, parts[2]))
+			}
+			if controller != "" {
+				for _, c := range strings.Split(parts[1], ",") {
+					if c == controller {
+						dirs = append(dirs, filepath.Join(base, parts[2]))
+					}
+				}
+			}
+		}
+	}
+
+	return append(dirs, base)
+}
@@ -0,0 +1,61 @@
+package runtimeExt
+
+import (
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// GCStats combines garbage collector statistics with the current memory statistics.
+type GCStats struct {
+	NumGC         int64           `json:"num_gc"`
+	LastGC        time.Time       `json:"last_gc"`
+	PauseTotal    time.Duration   `json:"pause_total_ns"`
+	RecentPauses  []time.Duration `json:"recent_pauses_ns"`
+	HeapAlloc     uint64          `json:"heap_alloc"`
+	HeapSys       uint64          `json:"heap_sys"`
+	HeapObjects   uint64          `json:"heap_objects"`
+	NextGC        uint64          `json:"next_gc"`
+	GCCPUFraction float64         `json:"gc_cpu_fraction"`
+	NumGoroutine  int             `json:"num_goroutine"`
+	GOMAXPROCS    int             `json:"gomaxprocs"`
+	MemoryLimit   int64           `json:"memory_limit"`
+	GCPercent     int             `json:"gc_percent"`
+	CollectedAt   time.Time       `json:"collected_at"`
+}
+
+// GetGCStats returns a snapshot of garbage collector and heap statistics.
+func GetGCStats() *GCStats {
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+	mem := GetMemStats()
+
+	recent := gc.Pause
+	if len(recent) > 10 {
+		recent = recent[:10]
+	}
+
+	samples := []metrics.Sample{
+		{Name: "/gc/gogc:percent"},
+		{Name: "/gc/gomemlimit:bytes"},
+	}
+	metrics.Read(samples)
+
+	return &GCStats{
+		NumGC:         gc.NumGC,
+		LastGC:        gc.LastGC,
+		PauseTotal:    gc.PauseTotal,
+		RecentPauses:  recent,
+		HeapAlloc:     mem.HeapAlloc,
+		HeapSys:       mem.HeapSys,
+		HeapObjects:   mem.HeapObjects,
+		NextGC:        mem.NextGC,
+		GCCPUFraction: mem.GCCPUFraction,
+		NumGoroutine:  runtime.NumGoroutine(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		MemoryLimit:   int64(samples[1].Value.Uint64()),
+		GCPercent:     int(samples[0].Value.Uint64()),
+		CollectedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,145 @@
+package runtimeExt
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frame is a single function call in a goroutine's stack.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Goroutine is a parsed record from a runtime stack dump.
+type Goroutine struct {
+	ID        int
+	State     string        // e.g. "running", "chan receive", "IO wait"
+	Wait      time.Duration // Time spent waiting, reported by the runtime in whole minutes
+	Locked    bool          // Locked to an OS thread
+	Frames    []Frame
+	CreatedBy *Frame
+	Stack     string `json:",omitempty"` // Raw stack text for this goroutine
+}
+
+// TopFrame returns the innermost frame, or an empty Frame if none was parsed.
+func (g *Goroutine) TopFrame() Frame {
+	if len(g.Frames) == 0 {
+		return Frame{}
+	}
+	return g.Frames[0]
+}
+
+// IsRunning reports whether the goroutine is executing or ready to execute.
+func (g *Goroutine) IsRunning() bool {
+	switch g.State {
+	case "running", "runnable", "syscall":
+		return true
+	}
+	return false
+}
+
+// GetGoroutines returns a parsed record for every goroutine, including the caller's.
+func GetGoroutines() []Goroutine {
+	return ParseGoroutines(stackDump())
+}
+
+// stackDump returns the stack traces of all goroutines, growing the buffer as needed.
+func stackDump() string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+// ParseGoroutines parses the output of runtime.Stack(buf, true) or a
+// "debug=2" goroutine profile into structured records.
+func ParseGoroutines(dump string) []Goroutine {
+	var goroutines []Goroutine
+	for _, block := range strings.Split(strings.TrimSpace(dump), "\n\n") {
+		if g, ok := parseGoroutine(block); ok {
+			goroutines = append(goroutines, g)
+		}
+	}
+	return goroutines
+}
+
+func parseGoroutine(block string) (Goroutine, bool) {
+	lines := strings.Split(block, "\n")
+	g := Goroutine{Stack: block}
+
+	// Header: "goroutine 7 [chan receive, 3 minutes, locked to thread]:"
+	header := strings.TrimSuffix(lines[0], ":")
+	rest, ok := strings.CutPrefix(header, "goroutine ")
+	if !ok {
+		return g, false
+	}
+	idText, status, _ := strings.Cut(rest, " ")
+	id, err := strconv.Atoi(idText)
+	if err != nil {
+		return g, false
+	}
+	g.ID = id
+
+	status = strings.TrimSuffix(strings.TrimPrefix(status, "["), "]")
+	for i, part := range strings.Split(status, ", ") {
+		switch {
+		case i == 0:
+			g.State = part
+		case part == "locked to thread":
+			g.Locked = true
+		case strings.HasSuffix(part, "minutes") || strings.HasSuffix(part, "minute"):
+			if n, err := strconv.Atoi(strings.Fields(part)[0]); err == nil {
+				g.Wait = time.Duration(n) * time.Minute
+			}
+		}
+	}
+
+	// Body: function lines each followed by a tab-indented "file:line +0x.." line
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "...") {
+			continue
+		}
+
+		frame := Frame{Function: line}
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			frame.File, frame.Line = parseFileLine(lines[i+1])
+			i++
+		}
+
+		if fn, ok := strings.CutPrefix(line, "created by "); ok {
+			fn, _, _ = strings.Cut(fn, " in goroutine ")
+			frame.Function = fn
+			g.CreatedBy = &frame
+			continue
+		}
+
+		// Strip the argument list: "pkg.fn(0x1, 0x2)" -> "pkg.fn"
+		if idx := strings.LastIndex(frame.Function, "("); idx > 0 && strings.HasSuffix(frame.Function, ")") {
+			frame.Function = frame.Function[:idx]
+		}
+		g.Frames = append(g.Frames, frame)
+	}
+
+	return g, true
+}
+
+// parseFileLine parses "\t/path/to/file.go:42 +0x1d".
+func parseFileLine(s string) (string, int) {
+	s = strings.TrimSpace(s)
+	s, _, _ = strings.Cut(s, " ")
+	idx := strings.LastIndex(s, ":")
+	if idx < 0 {
+		return s, 0
+	}
+	line, _ := strconv.Atoi(s[idx+1:])
+	return s[:idx], line
+}
@@ -0,0 +1,122 @@
+package runtimeExt
+
+import (
+	"strings"
+	"time"
+)
+
+// TestingT is the part of testing.TB that VerifyNoLeaks uses. Taking it
+// instead of testing.TB keeps the testing package out of programs that
+// import runtimeExt.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(func())
+}
+
+// LeakOptions configures goroutine leak detection.
+type LeakOptions struct {
+	IgnoreTopFunctions []string      // Goroutines whose top frame starts with one of these are ignored
+	IgnoreAnyFunctions []string      // Goroutines with any frame starting with one of these are ignored
+	Timeout            time.Duration // How long to wait for goroutines to exit (default 1s)
+}
+
+// defaultIgnoredFunctions lists goroutines started by the runtime and the
+// testing package that are never leaks of the code under test.
+var defaultIgnoredFunctions = []string{
+	"testing.(*T).Run",
+	"testing.(*T).Parallel",
+	"testing.runTests",
+	"testing.tRunner.func1",
+	"runtime.goexit",
+	"os/signal.signal_recv",
+	"os/signal.loop",
+	"runtime.ensureSigM",
+	"runtime/trace.Start.func1",
+}
+
+// VerifyNoLeaks snapshots the running goroutines and registers a cleanup
+// that fails t if goroutines started after the call are still running when
+// the test ends. Leaked goroutines are reported with their stack traces.
+func VerifyNoLeaks(t TestingT, opts *LeakOptions) {
+	t.Helper()
+	if opts == nil {
+		opts = &LeakOptions{}
+	}
+
+	baseline := make(map[int]bool)
+	for _, g := range otherGoroutines() {
+		baseline[g.ID] = true
+	}
+
+	t.Cleanup(func() {
+		t.Helper()
+		if leaks := FindLeaks(baseline, opts); len(leaks) > 0 {
+			t.Errorf("found %d leaked goroutine(s):\n\n%s", len(leaks), strings.Join(leaks, "\n\n"))
+		}
+	})
+}
+
+// FindLeaks waits up to opts.Timeout for all goroutines not present in the
+// baseline set of goroutine IDs to exit, and returns the stacks of those that remain.
+func FindLeaks(baseline map[int]bool, opts *LeakOptions) []string {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	delay := time.Millisecond
+
+	for {
+		var leaks []string
+		for _, g := range otherGoroutines() {
+			if baseline[g.ID] || isIgnoredGoroutine(g, opts) {
+				continue
+			}
+			leaks = append(leaks, g.Stack)
+		}
+
+		if len(leaks) == 0 || time.Now().After(deadline) {
+			return leaks
+		}
+
+		time.Sleep(delay)
+		if delay < 100*time.Millisecond {
+			delay *= 2
+		}
+	}
+}
+
+// otherGoroutines returns every goroutine except the caller's.
+func otherGoroutines() []Goroutine {
+	goroutines := GetGoroutines()
+	// The first goroutine in a dump is always the calling one
+	if len(goroutines) > 0 {
+		goroutines = goroutines[1:]
+	}
+	return goroutines
+}
+
+func isIgnoredGoroutine(g Goroutine, opts *LeakOptions) bool {
+	top := g.TopFrame().Function
+	for _, fn := range opts.IgnoreTopFunctions {
+		if strings.HasPrefix(top, fn) {
+			return true
+		}
+	}
+
+	for _, frame := range g.Frames {
+		for _, fn := range opts.IgnoreAnyFunctions {
+			if strings.HasPrefix(frame.Function, fn) {
+				return true
+			}
+		}
+	}
+
+	for _, fn := range defaultIgnoredFunctions {
+		if strings.HasPrefix(top, fn) {
+			return true
+		}
+	}
+	return false
+}
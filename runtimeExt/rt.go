@@ -4,12 +4,16 @@
 package runtimeExt
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"runtime/pprof"
 	"runtime/trace"
+	"sync/atomic"
 	"time"
 )
 
@@ -42,23 +46,39 @@ func ForceGC(wait bool) {
 // GoroutineStats provides information about goroutines.
 type GoroutineStats struct {
 	Count       int
-	Idle        int
-	Running     int
-	BlockedOn   map[string]int
+	Idle        int            // Goroutines waiting on something (not running, runnable or in a syscall)
+	Running     int            // Goroutines running, runnable or in a syscall
+	BlockedOn   map[string]int // Waiting goroutines grouped by wait reason
+	Goroutines  []Goroutine
 	StackTraces []string
 }
 
 // GetGoroutineStats returns statistics about all goroutines.
+// Stack traces are included per goroutine when includeTraces is true;
+// otherwise each goroutine keeps only its top frame and creator.
 func GetGoroutineStats(includeTraces bool) (*GoroutineStats, error) {
+	goroutines := GetGoroutines()
 	stats := &GoroutineStats{
-		Count:     runtime.NumGoroutine(),
-		BlockedOn: make(map[string]int),
+		Count:      len(goroutines),
+		BlockedOn:  make(map[string]int),
+		Goroutines: goroutines,
 	}
 
-	if includeTraces {
-		buf := make([]byte, 1<<20)
-		n := runtime.Stack(buf, true)
-		stats.StackTraces = []string{string(buf[:n])}
+	for i, g := range goroutines {
+		if !includeTraces {
+			goroutines[i].Stack = ""
+			goroutines[i].Frames = g.Frames[:min(len(g.Frames), 1)]
+		}
+		if g.IsRunning() {
+			stats.Running++
+		} else {
+			stats.Idle++
+			stats.BlockedOn[g.State]++
+		}
+
+		if includeTraces {
+			stats.StackTraces = append(stats.StackTraces, g.Stack)
+		}
 	}
 
 	return stats, nil
@@ -83,6 +103,124 @@ func CPUProfileStart(filename string) (func(), error) {
 	}, nil
 }
 
+// HeapProfileWrite runs a garbage collection to get up-to-date statistics
+// and writes a heap profile to the given file.
+func HeapProfileWrite(filename string) error {
+	runtime.GC()
+	return WriteProfile("heap", filename, 0)
+}
+
+// WriteProfile writes the named pprof profile (heap, allocs, goroutine,
+// block, mutex, threadcreate) to the given file using the given debug level.
+func WriteProfile(name, filename string, debugLevel int) error {
+	p := pprof.Lookup(name)
+	if p == nil {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("could not create %s profile: %v", name, err)
+	}
+
+	if err := p.WriteTo(f, debugLevel); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write %s profile: %v", name, err)
+	}
+	return f.Close()
+}
+
+// blockProfileRate is the rate last set by BlockProfileStart. The runtime
+// has no way to read the rate back, unlike the mutex profile fraction.
+var blockProfileRate atomic.Int64
+
+// BlockProfileStart enables block profiling at the given rate (see
+// runtime.SetBlockProfileRate). Returns a stop function that restores the
+// previous rate and writes the collected profile to the given file. The
+// previous rate is only known if it was set by BlockProfileStart; otherwise
+// profiling is disabled on stop.
+func BlockProfileStart(rate int) func(filename string) error {
+	if rate <= 0 {
+		rate = 1
+	}
+	prev := int(blockProfileRate.Swap(int64(rate)))
+	runtime.SetBlockProfileRate(rate)
+
+	return func(filename string) error {
+		defer func() {
+			blockProfileRate.Store(int64(prev))
+			runtime.SetBlockProfileRate(prev)
+		}()
+		return WriteProfile("block", filename, 0)
+	}
+}
+
+// MutexProfileStart enables mutex contention profiling, sampling on average
+// 1/fraction events (see runtime.SetMutexProfileFraction). Returns a stop
+// function that restores the previous fraction and writes the profile to the given file.
+func MutexProfileStart(fraction int) func(filename string) error {
+	if fraction <= 0 {
+		fraction = 1
+	}
+	prev := runtime.SetMutexProfileFraction(fraction)
+
+	return func(filename string) error {
+		defer runtime.SetMutexProfileFraction(prev)
+		return WriteProfile("mutex", filename, 0)
+	}
+}
+
+// ProfileAll captures a full diagnostic bundle into dir: CPU profile, execution
+// trace, block and mutex profiles collected over the given duration, followed by
+// heap, allocs, goroutine and threadcreate profiles and JSON runtime statistics.
+func ProfileAll(dir string, duration time.Duration) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create profile directory: %v", err)
+	}
+
+	stopCPU, err := CPUProfileStart(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return err
+	}
+	stopTrace, err := TraceStart(filepath.Join(dir, "trace.out"))
+	if err != nil {
+		stopCPU()
+		return err
+	}
+	stopBlock := BlockProfileStart(1)
+	stopMutex := MutexProfileStart(1)
+
+	time.Sleep(duration)
+
+	stopCPU()
+	stopTrace()
+
+	var errs []error
+	errs = append(errs,
+		stopBlock(filepath.Join(dir, "block.pprof")),
+		stopMutex(filepath.Join(dir, "mutex.pprof")),
+		HeapProfileWrite(filepath.Join(dir, "heap.pprof")),
+		WriteProfile("allocs", filepath.Join(dir, "allocs.pprof"), 0),
+		WriteProfile("threadcreate", filepath.Join(dir, "threadcreate.pprof"), 0),
+		WriteProfile("goroutine", filepath.Join(dir, "goroutines.txt"), 2),
+		writeJSONFile(filepath.Join(dir, "gc.json"), GetGCStats()),
+	)
+
+	if stats, err := GetGoroutineStats(false); err == nil {
+		errs = append(errs, writeJSONFile(filepath.Join(dir, "goroutines.json"), stats))
+	}
+
+	return errors.Join(errs...)
+}
+
+func writeJSONFile(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
 // TraceStart starts execution tracing and writes to the given file.
 // Returns a stop function that should be called to end tracing.
 func TraceStart(filename string) (func(), error) {
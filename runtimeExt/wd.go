@@ -0,0 +1,203 @@
+package runtimeExt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryLevel describes how close memory usage is to its configured limits.
+type MemoryLevel int
+
+const (
+	MemoryNormal MemoryLevel = iota
+	MemoryWarn
+	MemoryCritical
+)
+
+func (l MemoryLevel) String() string {
+	switch l {
+	case MemoryWarn:
+		return "warn"
+	case MemoryCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// WatchdogConfig configures a memory Watchdog. Zero thresholds are disabled.
+type WatchdogConfig struct {
+	Interval          time.Duration // Sampling interval (default 10s)
+	HeapWarn          uint64        // HeapAlloc bytes that trigger MemoryWarn
+	HeapCritical      uint64        // HeapAlloc bytes that trigger MemoryCritical
+	RSSWarn           uint64        // Resident set size bytes that trigger MemoryWarn
+	RSSCritical       uint64        // Resident set size bytes that trigger MemoryCritical
+	ForceGCOnCritical bool          // Run ForceGC when a critical threshold is crossed
+	HeapProfileDir    string        // If set, write a heap profile here on critical
+}
+
+// MemoryEvent is passed to watchdog callbacks when a metric changes level.
+type MemoryEvent struct {
+	Level       MemoryLevel
+	Metric      string // "heap" or "rss"
+	Value       uint64
+	Threshold   uint64
+	Stats       *MemStats
+	ProfilePath string // Heap profile written for this event, if any
+	Time        time.Time
+}
+
+// Watchdog periodically samples memory statistics and invokes callbacks
+// when heap or RSS usage crosses the configured thresholds.
+type Watchdog struct {
+	cfg       WatchdogConfig
+	mu        sync.Mutex
+	callbacks []func(MemoryEvent)
+	levels    map[string]MemoryLevel
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewWatchdog creates a Watchdog with the given configuration.
+func NewWatchdog(cfg WatchdogConfig) *Watchdog {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	return &Watchdog{
+		cfg:    cfg,
+		levels: make(map[string]MemoryLevel),
+	}
+}
+
+// OnThreshold registers a callback invoked whenever a metric changes level,
+// including when it drops back to MemoryNormal.
+func (w *Watchdog) OnThreshold(fn func(MemoryEvent)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, fn)
+}
+
+// Start begins sampling in a background goroutine. Calling Start on a
+// running Watchdog has no effect.
+func (w *Watchdog) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		return
+	}
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+}
+
+// Stop halts sampling and waits for the background goroutine to exit.
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (w *Watchdog) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	w.Check()
+	for {
+		select {
+		case <-ticker.C:
+			w.Check()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Check samples memory once and fires callbacks for any level changes.
+// It is called automatically by Start but may also be used directly.
+func (w *Watchdog) Check() {
+	stats := GetMemStats()
+	w.evaluate("heap", stats.HeapAlloc, w.cfg.HeapWarn, w.cfg.HeapCritical, stats)
+
+	if w.cfg.RSSWarn > 0 || w.cfg.RSSCritical > 0 {
+		if rss, err := ResidentMemory(); err == nil {
+			w.evaluate("rss", rss, w.cfg.RSSWarn, w.cfg.RSSCritical, stats)
+		}
+	}
+}
+
+func (w *Watchdog) evaluate(metric string, value, warn, critical uint64, stats *MemStats) {
+	level, threshold := MemoryNormal, uint64(0)
+	switch {
+	case critical > 0 && value >= critical:
+		level, threshold = MemoryCritical, critical
+	case warn > 0 && value >= warn:
+		level, threshold = MemoryWarn, warn
+	}
+
+	w.mu.Lock()
+	prev := w.levels[metric]
+	w.levels[metric] = level
+	callbacks := append([]func(MemoryEvent){}, w.callbacks...)
+	w.mu.Unlock()
+
+	if level == prev {
+		return
+	}
+
+	event := MemoryEvent{
+		Level:     level,
+		Metric:    metric,
+		Value:     value,
+		Threshold: threshold,
+		Stats:     stats,
+		Time:      time.Now(),
+	}
+
+	if level == MemoryCritical {
+		if w.cfg.HeapProfileDir != "" {
+			path := filepath.Join(w.cfg.HeapProfileDir,
+				fmt.Sprintf("heap-%s-%d.pprof", metric, event.Time.UnixNano()))
+			if err := HeapProfileWrite(path); err == nil {
+				event.ProfilePath = path
+			}
+		}
+		if w.cfg.ForceGCOnCritical {
+			ForceGC(true)
+		}
+	}
+
+	for _, fn := range callbacks {
+		fn(event)
+	}
+}
+
+// ResidentMemory returns the resident set size of the current process in bytes.
+// On systems without /proc it falls back to the memory obtained from the OS by the Go runtime.
+func ResidentMemory() (uint64, error) {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return GetMemStats().Sys, nil
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected statm format: %q", data)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse statm: %v", err)
+	}
+	return pages * uint64(os.Getpagesize()), nil
+}
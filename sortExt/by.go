@@ -0,0 +1,94 @@
+package sortExt
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortBuilder composes multi-key comparators. Build one with By or ByFunc,
+// add further keys with ThenBy, and set each key's direction with Asc or Desc:
+//
+//	By(func(p Person) string { return p.Last }).Asc().
+//		ThenBy(Key(func(p Person) int { return p.Age })).Desc().
+//		Sort(people)
+type SortBuilder[T any] struct {
+	keys []sortKey[T]
+}
+
+type sortKey[T any] struct {
+	compare func(a, b T) int
+	desc    bool
+}
+
+// By starts a builder that sorts by the given key, ascending by default.
+func By[T any, K Ordered](key func(T) K) *SortBuilder[T] {
+	return ByFunc(Key(key))
+}
+
+// ByFunc starts a builder from a three-way comparison function that returns
+// a negative number when a < b, zero when equal and a positive number when a > b.
+func ByFunc[T any](compare func(a, b T) int) *SortBuilder[T] {
+	return &SortBuilder[T]{keys: []sortKey[T]{{compare: compare}}}
+}
+
+// Key converts a key extractor into a three-way comparison function for use with ThenBy.
+func Key[T any, K Ordered](key func(T) K) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// ThenBy adds a tie-breaking comparison, used when all previous keys are equal.
+func (b *SortBuilder[T]) ThenBy(compare func(a, b T) int) *SortBuilder[T] {
+	b.keys = append(b.keys, sortKey[T]{compare: compare})
+	return b
+}
+
+// Asc makes the most recently added key sort in ascending order.
+func (b *SortBuilder[T]) Asc() *SortBuilder[T] {
+	b.keys[len(b.keys)-1].desc = false
+	return b
+}
+
+// Desc makes the most recently added key sort in descending order.
+func (b *SortBuilder[T]) Desc() *SortBuilder[T] {
+	b.keys[len(b.keys)-1].desc = true
+	return b
+}
+
+// Compare returns the composed three-way comparison function.
+func (b *SortBuilder[T]) Compare() func(x, y T) int {
+	keys := slices.Clone(b.keys)
+	return func(x, y T) int {
+		for _, k := range keys {
+			c := k.compare(x, y)
+			if c == 0 {
+				continue
+			}
+			if k.desc {
+				return -c
+			}
+			return c
+		}
+		return 0
+	}
+}
+
+// Less returns the composed comparator as a less function, compatible with
+// SortBy, ParallelSort and the other helpers in this package.
+func (b *SortBuilder[T]) Less() func(x, y T) bool {
+	compare := b.Compare()
+	return func(x, y T) bool {
+		return compare(x, y) < 0
+	}
+}
+
+// Sort sorts data in place using the composed comparator.
+func (b *SortBuilder[T]) Sort(data []T) {
+	slices.SortFunc(data, b.Compare())
+}
+
+// SortStable sorts data in place, keeping equal elements in their original order.
+func (b *SortBuilder[T]) SortStable(data []T) {
+	slices.SortStableFunc(data, b.Compare())
+}
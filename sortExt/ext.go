@@ -0,0 +1,285 @@
+package sortExt
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// Codec serializes elements to and from the spill files used by ExternalSort.
+// Each factory is called once per file, so stateful stream encoders work.
+// Decoders must return io.EOF once the stream is exhausted.
+type Codec[T any] struct {
+	NewEncoder func(w io.Writer) func(v T) error
+	NewDecoder func(r io.Reader) func() (T, error)
+}
+
+// GobCodec returns a Codec using encoding/gob.
+func GobCodec[T any]() Codec[T] {
+	return Codec[T]{
+		NewEncoder: func(w io.Writer) func(T) error {
+			enc := gob.NewEncoder(w)
+			return func(v T) error { return enc.Encode(v) }
+		},
+		NewDecoder: func(r io.Reader) func() (T, error) {
+			dec := gob.NewDecoder(r)
+			return func() (T, error) {
+				var v T
+				err := dec.Decode(&v)
+				return v, err
+			}
+		},
+	}
+}
+
+// JSONCodec returns a Codec writing one JSON value per line.
+func JSONCodec[T any]() Codec[T] {
+	return Codec[T]{
+		NewEncoder: func(w io.Writer) func(T) error {
+			enc := json.NewEncoder(w)
+			return func(v T) error { return enc.Encode(v) }
+		},
+		NewDecoder: func(r io.Reader) func() (T, error) {
+			dec := json.NewDecoder(r)
+			return func() (T, error) {
+				var v T
+				err := dec.Decode(&v)
+				return v, err
+			}
+		},
+	}
+}
+
+// ExternalSortOptions configures ExternalSort.
+type ExternalSortOptions struct {
+	ChunkSize int    // Elements sorted in memory per run (default 1,000,000)
+	TempDir   string // Directory for spill files (default os.TempDir())
+}
+
+// SortedSeq is the result of ExternalSort. Iterate it with All, then check Err.
+// Spill files are removed once iteration finishes; call Close to remove them
+// if iteration is abandoned early or never started.
+type SortedSeq[T any] struct {
+	less   func(a, b T) bool
+	codec  Codec[T]
+	dir    string
+	runs   []string
+	memory []T
+	err    error
+}
+
+// ExternalSort sorts a sequence that may not fit in memory. Elements are
+// collected into chunks of opts.ChunkSize, each chunk is sorted and spilled
+// to a temporary file using codec, and the runs are merged lazily when the
+// returned SortedSeq is iterated. Inputs that fit in a single chunk never touch disk.
+// The sort is stable.
+func ExternalSort[T any](seq iter.Seq[T], less func(a, b T) bool, codec Codec[T], opts *ExternalSortOptions) (*SortedSeq[T], error) {
+	chunkSize := 1_000_000
+	tempDir := ""
+	if opts != nil {
+		if opts.ChunkSize > 0 {
+			chunkSize = opts.ChunkSize
+		}
+		tempDir = opts.TempDir
+	}
+
+	s := &SortedSeq[T]{less: less, codec: codec}
+	chunk := make([]T, 0, min(chunkSize, 1024))
+
+	for v := range seq {
+		chunk = append(chunk, v)
+		if len(chunk) < chunkSize {
+			continue
+		}
+		if err := s.spill(chunk, tempDir); err != nil {
+			s.Close()
+			return nil, err
+		}
+		chunk = chunk[:0]
+	}
+
+	if len(s.runs) == 0 {
+		// Everything fit in memory
+		ParallelSortStable(chunk, less, 0)
+		s.memory = chunk
+		return s, nil
+	}
+
+	if len(chunk) > 0 {
+		if err := s.spill(chunk, tempDir); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// spill sorts chunk and writes it to a new run file.
+func (s *SortedSeq[T]) spill(chunk []T, tempDir string) error {
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(tempDir, "extsort-")
+		if err != nil {
+			return err
+		}
+		s.dir = dir
+	}
+
+	ParallelSortStable(chunk, s.less, 0)
+
+	path := filepath.Join(s.dir, "run-"+strconv.Itoa(len(s.runs)))
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	encode := s.codec.NewEncoder(w)
+	for _, v := range chunk {
+		if err := encode(v); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	s.runs = append(s.runs, path)
+	return nil
+}
+
+// All returns an iterator over the sorted elements. It can be used once.
+func (s *SortedSeq[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		defer s.Close()
+
+		if s.memory != nil {
+			for _, v := range s.memory {
+				if !yield(v) {
+					return
+				}
+			}
+			return
+		}
+
+		s.merge(yield)
+	}
+}
+
+// merge performs a k-way merge of all runs.
+func (s *SortedSeq[T]) merge(yield func(T) bool) {
+	h := &runHeap[T]{less: s.less}
+	for i, path := range s.runs {
+		f, err := os.Open(path)
+		if err != nil {
+			s.err = err
+			return
+		}
+		defer f.Close()
+
+		r := &runReader[T]{index: i, next: s.codec.NewDecoder(bufio.NewReader(f))}
+		if ok, err := r.advance(); err != nil {
+			s.err = err
+			return
+		} else if ok {
+			h.items = append(h.items, r)
+		}
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		r := h.items[0]
+		if !yield(r.value) {
+			return
+		}
+
+		ok, err := r.advance()
+		if err != nil {
+			s.err = err
+			return
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+}
+
+// Err returns the first error encountered while reading spill files.
+func (s *SortedSeq[T]) Err() error {
+	return s.err
+}
+
+// Close removes any spill files. It is safe to call more than once.
+func (s *SortedSeq[T]) Close() error {
+	s.memory = nil
+	if s.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.dir)
+	s.dir = ""
+	return err
+}
+
+// runReader holds the current head of one spill file.
+type runReader[T any] struct {
+	index int
+	value T
+	next  func() (T, error)
+}
+
+func (r *runReader[T]) advance() (bool, error) {
+	v, err := r.next()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	r.value = v
+	return true, nil
+}
+
+// runHeap orders run readers by their current value, breaking ties by run
+// index to keep the merge stable.
+type runHeap[T any] struct {
+	items []*runReader[T]
+	less  func(a, b T) bool
+}
+
+func (h *runHeap[T]) Len() int { return len(h.items) }
+func (h *runHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.index < b.index
+}
+func (h *runHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *runHeap[T]) Push(x any)    { h.items = append(h.items, x.(*runReader[T])) }
+func (h *runHeap[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+	return item
+}
+
+// SortedSlice collects a sorted sequence into a slice.
+func SortedSlice[T any](s *SortedSeq[T]) ([]T, error) {
+	out := slices.Collect(s.All())
+	return out, s.Err()
+}
@@ -0,0 +1,139 @@
+package sortExt
+
+import (
+	"math/bits"
+)
+
+// SelectKth rearranges data so that data[k] holds the element that would be
+// at index k if data were sorted, every element before it is not greater and
+// every element after it is not smaller. It returns data[k].
+// Uses introselect: quickselect with median-of-three pivots, falling back to
+// heapselect when partitioning degrades, for O(n) average and O(n log n) worst case.
+// It panics if k is out of range.
+func SelectKth[T any](data []T, k int, less func(a, b T) bool) T {
+	if k < 0 || k >= len(data) {
+		panic("sortExt: SelectKth index out of range")
+	}
+
+	lo, hi := 0, len(data)
+	budget := 2 * bits.Len(uint(len(data)))
+	for hi-lo > 16 {
+		if budget == 0 {
+			heapSelect(data[lo:hi], k-lo, less)
+			return data[k]
+		}
+		budget--
+
+		p := partition(data[lo:hi], less) + lo
+		switch {
+		case k < p:
+			hi = p
+		case k > p:
+			lo = p + 1
+		default:
+			return data[k]
+		}
+	}
+
+	insertionSort(data[lo:hi], less)
+	return data[k]
+}
+
+// PartialSort rearranges data so that data[:k] holds the k smallest elements
+// in sorted order. The order of the remaining elements is unspecified.
+func PartialSort[T any](data []T, k int, less func(a, b T) bool) {
+	if k <= 0 {
+		return
+	}
+	if k >= len(data) {
+		SortBy(data, less)
+		return
+	}
+
+	SelectKth(data, k-1, less)
+	SortBy(data[:k-1], less)
+}
+
+// TopK returns the k smallest elements of data according to less, in sorted
+// order, without modifying data.
+func TopK[T any](data []T, k int, less func(a, b T) bool) []T {
+	k = max(0, min(k, len(data)))
+	c := make([]T, len(data))
+	copy(c, data)
+	PartialSort(c, k, less)
+	return c[:k:k]
+}
+
+// partition partitions data around a median-of-three pivot and returns the pivot's final index.
+func partition[T any](data []T, less func(a, b T) bool) int {
+	n := len(data)
+	mid := n / 2
+
+	// Order first, middle and last, then move the median to the end
+	if less(data[mid], data[0]) {
+		data[mid], data[0] = data[0], data[mid]
+	}
+	if less(data[n-1], data[0]) {
+		data[n-1], data[0] = data[0], data[n-1]
+	}
+	if less(data[mid], data[n-1]) {
+		data[mid], data[n-1] = data[n-1], data[mid]
+	}
+	pivot := data[n-1]
+
+	i := 0
+	for j := 0; j < n-1; j++ {
+		if less(data[j], pivot) {
+			data[i], data[j] = data[j], data[i]
+			i++
+		}
+	}
+	data[i], data[n-1] = data[n-1], data[i]
+	return i
+}
+
+// heapSelect places the k-th smallest element at index k by building a
+// max-heap of the first k+1 elements and streaming the rest through it.
+func heapSelect[T any](data []T, k int, less func(a, b T) bool) {
+	h := data[:k+1]
+	for i := len(h)/2 - 1; i >= 0; i-- {
+		siftDown(h, i, less)
+	}
+
+	for i := k + 1; i < len(data); i++ {
+		if less(data[i], h[0]) {
+			h[0], data[i] = data[i], h[0]
+			siftDown(h, 0, less)
+		}
+	}
+
+	// The heap root is the k-th smallest; move it into place
+	h[0], h[k] = h[k], h[0]
+}
+
+// siftDown restores the max-heap property below index i.
+func siftDown[T any](h []T, i int, less func(a, b T) bool) {
+	for {
+		largest := i
+		l, r := 2*i+1, 2*i+2
+		if l < len(h) && less(h[largest], h[l]) {
+			largest = l
+		}
+		if r < len(h) && less(h[largest], h[r]) {
+			largest = r
+		}
+		if largest == i {
+			return
+		}
+		h[i], h[largest] = h[largest], h[i]
+		i = largest
+	}
+}
+
+func insertionSort[T any](data []T, less func(a, b T) bool) {
+	for i := 1; i < len(data); i++ {
+		for j := i; j > 0 && less(data[j], data[j-1]); j-- {
+			data[j], data[j-1] = data[j-1], data[j]
+		}
+	}
+}
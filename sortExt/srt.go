@@ -2,8 +2,12 @@
 package sortExt
 
 import (
+	"runtime"
+	"slices"
 	"sort"
 	"sync"
+
+	"github.com/C0d3-5t3w/myT00L5/stringsExt"
 )
 
 // SortBy sorts a slice using a custom comparator function
@@ -36,15 +40,76 @@ type Ordered interface {
 		~string
 }
 
-// ParallelSort performs a parallel merge sort for large slices
-// It's more efficient than standard sort for large datasets
+// minParallelSize is the slice length below which sorting is done sequentially,
+// since goroutine and merge overhead outweighs the gain for small inputs.
+const minParallelSize = 4096
+
+// mergeBuffers pools the scratch space used by the parallel merge sorts.
+// Buffers of a different element type are simply dropped.
+var mergeBuffers sync.Pool
+
+// ParallelSort performs a parallel merge sort for large slices, sorting
+// halves on separate goroutines. Whether that beats sort.Slice depends on the
+// cores available and the cost of less; BenchmarkSort compares the two.
+// A parallelism of zero or less uses GOMAXPROCS goroutines.
 func ParallelSort[T any](data []T, less func(i, j T) bool, parallelism int) {
+	parallelSort(data, less, parallelism, false)
+}
+
+// ParallelSortStable is like ParallelSort but keeps equal elements in their original order.
+func ParallelSortStable[T any](data []T, less func(i, j T) bool, parallelism int) {
+	parallelSort(data, less, parallelism, true)
+}
+
+func parallelSort[T any](data []T, less func(i, j T) bool, parallelism int, stable bool) {
 	if len(data) < 2 {
 		return
 	}
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	if parallelism == 1 || len(data) < minParallelSize {
+		sortSequential(data, less, stable)
+		return
+	}
+
+	buf := getMergeBuffer[T](len(data))
+	mergeSort(data, buf, less, parallelism, stable)
+	clear(buf)
+	mergeBuffers.Put(&buf)
+}
+
+func getMergeBuffer[T any](n int) []T {
+	if p, ok := mergeBuffers.Get().(*[]T); ok && cap(*p) >= n {
+		return (*p)[:n]
+	}
+	return make([]T, n)
+}
+
+func sortSequential[T any](data []T, less func(i, j T) bool, stable bool) {
+	compare := func(a, b T) int {
+		if less(a, b) {
+			return -1
+		}
+		if less(b, a) {
+			return 1
+		}
+		return 0
+	}
+
+	if stable {
+		slices.SortStableFunc(data, compare)
+	} else {
+		slices.SortFunc(data, compare)
+	}
+}
 
-	if parallelism <= 1 || len(data) < 1000 {
-		SortBy(data, less)
+// mergeSort sorts data using buf (of the same length) as scratch space,
+// splitting work across up to parallelism goroutines.
+func mergeSort[T any](data, buf []T, less func(i, j T) bool, parallelism int, stable bool) {
+	if parallelism <= 1 || len(data) < minParallelSize {
+		sortSequential(data, less, stable)
 		return
 	}
 
@@ -53,37 +118,32 @@ func ParallelSort[T any](data []T, less func(i, j T) bool, parallelism int) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		ParallelSort(data[:mid], less, parallelism/2)
+		mergeSort(data[:mid], buf[:mid], less, parallelism/2, stable)
 	}()
-	ParallelSort(data[mid:], less, parallelism/2)
+	mergeSort(data[mid:], buf[mid:], less, parallelism-parallelism/2, stable)
 	wg.Wait()
 
-	// Merge the sorted halves
-	merge(data, mid, less)
+	merge(data, buf, mid, less)
 }
 
-// merge combines two sorted slices into one
-func merge[T any](data []T, mid int, less func(i, j T) bool) {
-	temp := make([]T, len(data))
-	copy(temp, data)
-
+// merge combines the sorted halves data[:mid] and data[mid:] using buf as
+// scratch space. Ties are taken from the left half, so the merge is stable.
+func merge[T any](data, buf []T, mid int, less func(i, j T) bool) {
 	i, j, k := 0, mid, 0
 	for i < mid && j < len(data) {
-		if less(temp[i], temp[j]) {
-			data[k] = temp[i]
-			i++
-		} else {
-			data[k] = temp[j]
+		if less(data[j], data[i]) {
+			buf[k] = data[j]
 			j++
+		} else {
+			buf[k] = data[i]
+			i++
 		}
 		k++
 	}
 
-	for i < mid {
-		data[k] = temp[i]
-		i++
-		k++
-	}
+	k += copy(buf[k:], data[i:mid])
+	copy(buf[k:], data[j:])
+	copy(data, buf)
 }
 
 // IsSorted checks if a slice is sorted according to a comparator
@@ -132,3 +192,54 @@ func Deduplicate[T comparable](data []T) int {
 
 	return j
 }
+
+// NaturalLess reports whether a sorts before b in natural order, where runs
+// of digits are compared numerically ("item2" < "item10").
+func NaturalLess(a, b string) bool {
+	return stringsExt.NaturalCompare(a, b) < 0
+}
+
+// SortNatural sorts a slice of strings in natural order.
+func SortNatural(data []string) {
+	slices.SortStableFunc(data, stringsExt.NaturalCompare)
+}
+
+// SortIndices returns the permutation that would stably sort data, without
+// modifying it: data[idx[0]] is the smallest element, data[idx[1]] the next, and so on.
+func SortIndices[T any](data []T, less func(a, b T) bool) []int {
+	idx := make([]int, len(data))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return less(data[idx[i]], data[idx[j]])
+	})
+	return idx
+}
+
+// Rank returns the 0-based position each element of data would occupy after
+// a stable sort, so rank[i] is the sorted position of data[i]. Equal elements
+// are ranked by their original order. Rank is the inverse of SortIndices.
+func Rank[T Ordered](data []T) []int {
+	return RankFunc(data, func(a, b T) bool { return a < b })
+}
+
+// RankFunc is like Rank but uses a custom less function.
+func RankFunc[T any](data []T, less func(a, b T) bool) []int {
+	idx := SortIndices(data, less)
+	rank := make([]int, len(idx))
+	for pos, i := range idx {
+		rank[i] = pos
+	}
+	return rank
+}
+
+// Permute returns a new slice with result[i] = data[idx[i]], so a permutation
+// from SortIndices can reorder any number of parallel slices consistently.
+func Permute[T any](data []T, idx []int) []T {
+	out := make([]T, len(idx))
+	for i, j := range idx {
+		out[i] = data[j]
+	}
+	return out
+}
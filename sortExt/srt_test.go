@@ -0,0 +1,74 @@
+package sortExt
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func randomInts(n int) []int {
+	r := rand.New(rand.NewSource(1))
+	data := make([]int, n)
+	for i := range data {
+		data[i] = r.Intn(n)
+	}
+	return data
+}
+
+func TestParallelSortStable(t *testing.T) {
+	type pair struct{ key, pos int }
+	data := make([]pair, 50000)
+	for i, v := range randomInts(len(data)) {
+		data[i] = pair{v % 100, i}
+	}
+	ParallelSortStable(data, func(a, b pair) bool { return a.key < b.key }, 4)
+	for i := 1; i < len(data); i++ {
+		a, b := data[i-1], data[i]
+		if a.key > b.key || a.key == b.key && a.pos > b.pos {
+			t.Fatalf("out of order at %d: %v before %v", i, a, b)
+		}
+	}
+}
+
+func benchmarkSort(b *testing.B, n int, sortFn func([]int)) {
+	src := randomInts(n)
+	data := make([]int, n)
+	b.ResetTimer()
+	for range b.N {
+		b.StopTimer()
+		copy(data, src)
+		b.StartTimer()
+		sortFn(data)
+	}
+}
+
+func less(a, b int) bool { return a < b }
+
+func BenchmarkSort(b *testing.B) {
+	for _, n := range []int{10_000, 1_000_000} {
+		b.Run(sizeName(n)+"/sort.Slice", func(b *testing.B) {
+			benchmarkSort(b, n, func(d []int) { sort.Slice(d, func(i, j int) bool { return d[i] < d[j] }) })
+		})
+		b.Run(sizeName(n)+"/slices.SortFunc", func(b *testing.B) {
+			benchmarkSort(b, n, func(d []int) { slices.SortFunc(d, cmp.Compare[int]) })
+		})
+		b.Run(sizeName(n)+"/ParallelSort", func(b *testing.B) {
+			benchmarkSort(b, n, func(d []int) { ParallelSort(d, less, 0) })
+		})
+		b.Run(sizeName(n)+"/slices.SortStableFunc", func(b *testing.B) {
+			benchmarkSort(b, n, func(d []int) { slices.SortStableFunc(d, cmp.Compare[int]) })
+		})
+		b.Run(sizeName(n)+"/ParallelSortStable", func(b *testing.B) {
+			benchmarkSort(b, n, func(d []int) { ParallelSortStable(d, less, 0) })
+		})
+	}
+}
+
+func sizeName(n int) string {
+	if n >= 1_000_000 {
+		return "1M"
+	}
+	return "10K"
+}
@@ -0,0 +1,171 @@
+package sortExt
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+)
+
+// CycleError is returned by TopoSort when the dependency graph contains a cycle.
+type CycleError[T comparable] struct {
+	Cycle []T // The nodes forming the cycle, with the first node repeated at the end
+}
+
+func (e *CycleError[T]) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, n := range e.Cycle {
+		parts[i] = fmt.Sprint(n)
+	}
+	return "dependency cycle detected: " + strings.Join(parts, " -> ")
+}
+
+// TopoSort orders nodes so that every node comes after the nodes it depends
+// on, where deps[n] lists the dependencies of n. Nodes that only appear in
+// deps are included as well. Ready nodes are emitted in the order they first
+// appear in nodes; nodes found only as keys of deps come after them in
+// unspecified order. Returns a *CycleError if no ordering exists.
+func TopoSort[T comparable](nodes []T, deps map[T][]T) ([]T, error) {
+	return topoSort(nodes, deps, nil)
+}
+
+// TopoSortStable is like TopoSort but always emits the smallest ready node
+// according to less, making the result independent of input order.
+func TopoSortStable[T comparable](nodes []T, deps map[T][]T, less func(a, b T) bool) ([]T, error) {
+	return topoSort(nodes, deps, less)
+}
+
+func topoSort[T comparable](nodes []T, deps map[T][]T, less func(a, b T) bool) ([]T, error) {
+	// Collect all nodes in first-seen order
+	index := make(map[T]int)
+	var all []T
+	add := func(n T) {
+		if _, ok := index[n]; !ok {
+			index[n] = len(all)
+			all = append(all, n)
+		}
+	}
+	for _, n := range nodes {
+		add(n)
+	}
+	for _, n := range nodes {
+		for _, d := range deps[n] {
+			add(d)
+		}
+	}
+	for n, ds := range deps {
+		add(n)
+		for _, d := range ds {
+			add(d)
+		}
+	}
+
+	// Build dependents lists and in-degrees, ignoring duplicate edges
+	indegree := make(map[T]int, len(all))
+	dependents := make(map[T][]T, len(all))
+	for _, n := range all {
+		seen := make(map[T]bool)
+		for _, d := range deps[n] {
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			indegree[n]++
+			dependents[d] = append(dependents[d], n)
+		}
+	}
+
+	if less == nil {
+		// Break ties by first-seen order
+		less = func(a, b T) bool { return index[a] < index[b] }
+	}
+	ready := &topoQueue[T]{less: less}
+	for _, n := range all {
+		if indegree[n] == 0 {
+			ready.items = append(ready.items, n)
+		}
+	}
+	heap.Init(ready)
+
+	order := make([]T, 0, len(all))
+	for ready.Len() > 0 {
+		n := heap.Pop(ready).(T)
+		order = append(order, n)
+		for _, m := range dependents[n] {
+			indegree[m]--
+			if indegree[m] == 0 {
+				heap.Push(ready, m)
+			}
+		}
+	}
+
+	if len(order) < len(all) {
+		return nil, &CycleError[T]{Cycle: findCycle(all, deps, indegree)}
+	}
+	return order, nil
+}
+
+// findCycle returns a cycle among the nodes that could not be ordered.
+func findCycle[T comparable](all []T, deps map[T][]T, indegree map[T]int) []T {
+	const (
+		unvisited = iota
+		inProgress
+		finished
+	)
+	state := make(map[T]int)
+	var stack []T
+
+	var visit func(n T) []T
+	visit = func(n T) []T {
+		state[n] = inProgress
+		stack = append(stack, n)
+		for _, d := range deps[n] {
+			if indegree[d] == 0 {
+				continue
+			}
+			switch state[d] {
+			case inProgress:
+				// Slice the stack from the first occurrence of d
+				for i, s := range stack {
+					if s == d {
+						cycle := append([]T{}, stack[i:]...)
+						// Report in dependency order: each node depends on the next
+						return append(cycle, d)
+					}
+				}
+			case unvisited:
+				if c := visit(d); c != nil {
+					return c
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = finished
+		return nil
+	}
+
+	for _, n := range all {
+		if indegree[n] > 0 && state[n] == unvisited {
+			if c := visit(n); c != nil {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// topoQueue is a min-heap of ready nodes.
+type topoQueue[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (q *topoQueue[T]) Len() int           { return len(q.items) }
+func (q *topoQueue[T]) Less(i, j int) bool { return q.less(q.items[i], q.items[j]) }
+func (q *topoQueue[T]) Swap(i, j int)      { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *topoQueue[T]) Push(x any)         { q.items = append(q.items, x.(T)) }
+func (q *topoQueue[T]) Pop() any {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items = q.items[:n-1]
+	return item
+}
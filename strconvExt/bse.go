@@ -0,0 +1,158 @@
+package strconvExt
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// ErrBaseOverflow is returned by ParseBase when the value does not fit in a uint64.
+var ErrBaseOverflow = errors.New("value out of range")
+
+// Alphabet is an ordered set of digit characters for FormatBase and ParseBase.
+type Alphabet struct {
+	chars       string
+	values      [256]int16 // Digit value for each byte, or -1
+	skipHyphens bool       // Ignore '-' separators when parsing
+}
+
+const base62Chars = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var (
+	// Base36 uses digits and lower-case letters, matching strconv for base 36.
+	// Parsing is case-insensitive.
+	Base36 = BaseN(36)
+	// Base62 uses digits, then lower-case, then upper-case letters.
+	Base62 = BaseN(62)
+	// Base58 is the Bitcoin alphabet, which omits 0, O, I and l to avoid
+	// visually ambiguous IDs.
+	Base58 = MustAlphabet("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+	// Crockford32 is Douglas Crockford's base 32. Parsing is case-insensitive,
+	// reads I and L as 1 and O as 0, and ignores hyphens.
+	Crockford32 = newCrockford32()
+)
+
+// NewAlphabet creates an alphabet from a string of distinct ASCII characters;
+// the base is its length, at least 2.
+func NewAlphabet(chars string) (*Alphabet, error) {
+	if len(chars) < 2 {
+		return nil, fmt.Errorf("alphabet must have at least 2 characters, got %d", len(chars))
+	}
+
+	a := &Alphabet{chars: chars}
+	for i := range a.values {
+		a.values[i] = -1
+	}
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		if c >= 0x80 {
+			return nil, fmt.Errorf("alphabet must be ASCII, found byte %#x", c)
+		}
+		if a.values[c] >= 0 {
+			return nil, fmt.Errorf("alphabet contains %q more than once", c)
+		}
+		a.values[c] = int16(i)
+	}
+	return a, nil
+}
+
+// MustAlphabet is like NewAlphabet but panics on an invalid alphabet.
+// It is intended for package-level variables.
+func MustAlphabet(chars string) *Alphabet {
+	a, err := NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// BaseN returns the alphabet for base 2 to 62 using digits, then lower-case,
+// then upper-case letters. Up to base 36 this matches strconv.FormatUint and
+// parsing accepts either case. It panics if base is out of range.
+func BaseN(base int) *Alphabet {
+	if base < 2 || base > len(base62Chars) {
+		panic(fmt.Sprintf("strconvExt: base %d out of range 2-62", base))
+	}
+	a := MustAlphabet(base62Chars[:base])
+	if base <= 36 {
+		a.alias(strings.ToUpper(a.chars), a.chars)
+	}
+	return a
+}
+
+func newCrockford32() *Alphabet {
+	a := MustAlphabet("0123456789ABCDEFGHJKMNPQRSTVWXYZ")
+	a.alias(strings.ToLower(a.chars), a.chars)
+	a.alias("OoIiLl", "001111")
+	a.skipHyphens = true
+	return a
+}
+
+// alias makes each byte in from decode to the value of the matching byte in to.
+func (a *Alphabet) alias(from, to string) {
+	for i := 0; i < len(from); i++ {
+		if a.values[from[i]] < 0 {
+			a.values[from[i]] = a.values[to[i]]
+		}
+	}
+}
+
+// Base returns the number of digits in the alphabet.
+func (a *Alphabet) Base() int {
+	return len(a.chars)
+}
+
+// String returns the alphabet's digits in order.
+func (a *Alphabet) String() string {
+	return a.chars
+}
+
+// FormatBase formats n using the digits of alphabet.
+func FormatBase(n uint64, alphabet *Alphabet) string {
+	base := uint64(len(alphabet.chars))
+	if n == 0 {
+		return alphabet.chars[:1]
+	}
+
+	var buf [64]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = alphabet.chars[n%base]
+		n /= base
+	}
+	return string(buf[i:])
+}
+
+// ParseBase parses s as a number written in the digits of alphabet.
+func ParseBase(s string, alphabet *Alphabet) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("couldn't parse empty string in base %d", alphabet.Base())
+	}
+
+	base := uint64(len(alphabet.chars))
+	var n uint64
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '-' && alphabet.skipHyphens {
+			continue
+		}
+		digits++
+		d := alphabet.values[s[i]]
+		if d < 0 {
+			return 0, fmt.Errorf("couldn't parse %q in base %d: invalid digit %q", s, base, s[i])
+		}
+
+		hi, lo := bits.Mul64(n, base)
+		lo, carry := bits.Add64(lo, uint64(d), 0)
+		if hi != 0 || carry != 0 {
+			return 0, fmt.Errorf("couldn't parse %q in base %d: %w", s, base, ErrBaseOverflow)
+		}
+		n = lo
+	}
+	if digits == 0 {
+		return 0, fmt.Errorf("couldn't parse %q in base %d: no digits", s, base)
+	}
+	return n, nil
+}
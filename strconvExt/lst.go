@@ -0,0 +1,143 @@
+package strconvExt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListOption configures the list and map parsers.
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	sep       string
+	kvSep     string
+	trim      bool
+	skipEmpty bool
+}
+
+func newListOptions(opts []ListOption) *listOptions {
+	o := &listOptions{sep: ",", kvSep: "=", trim: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Separator sets the delimiter between elements (default ",").
+func Separator(sep string) ListOption {
+	return func(o *listOptions) {
+		o.sep = sep
+	}
+}
+
+// KeyValueSeparator sets the delimiter between a key and its value in
+// ParseStringMap (default "=").
+func KeyValueSeparator(sep string) ListOption {
+	return func(o *listOptions) {
+		o.kvSep = sep
+	}
+}
+
+// NoTrim keeps whitespace around elements, keys and values, which is
+// trimmed by default.
+func NoTrim() ListOption {
+	return func(o *listOptions) {
+		o.trim = false
+	}
+}
+
+// SkipEmpty ignores empty elements, so "1,,2" parses as two elements
+// instead of failing.
+func SkipEmpty() ListOption {
+	return func(o *listOptions) {
+		o.skipEmpty = true
+	}
+}
+
+// fields splits s according to the options. An empty input yields no fields.
+func (o *listOptions) fields(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	parts := strings.Split(s, o.sep)
+	fields := parts[:0]
+	for _, p := range parts {
+		if o.trim {
+			p = strings.TrimSpace(p)
+		}
+		if p == "" && o.skipEmpty {
+			continue
+		}
+		fields = append(fields, p)
+	}
+	return fields
+}
+
+// ParseSlice splits s into elements and converts each with parse. Errors
+// identify the position of the offending element.
+func ParseSlice[T any](s string, parse func(string) (T, error), opts ...ListOption) ([]T, error) {
+	fields := newListOptions(opts).fields(s)
+	result := make([]T, 0, len(fields))
+	for i, f := range fields {
+		v, err := parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse element %d (%q): %v", i, f, err)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// ParseIntSlice parses a delimited list of integers such as "1,2,3".
+func ParseIntSlice(s string, opts ...ListOption) ([]int, error) {
+	return ParseSlice(s, strconv.Atoi, opts...)
+}
+
+// ParseInt64Slice parses a delimited list of 64-bit integers.
+func ParseInt64Slice(s string, opts ...ListOption) ([]int64, error) {
+	return ParseSlice(s, func(f string) (int64, error) {
+		return strconv.ParseInt(f, 10, 64)
+	}, opts...)
+}
+
+// ParseFloatSlice parses a delimited list of floating-point numbers.
+func ParseFloatSlice(s string, opts ...ListOption) ([]float64, error) {
+	return ParseSlice(s, func(f string) (float64, error) {
+		return strconv.ParseFloat(f, 64)
+	}, opts...)
+}
+
+// ParseBoolSlice parses a delimited list of booleans, accepting the extended
+// forms of ParseBoolExtended.
+func ParseBoolSlice(s string, opts ...ListOption) ([]bool, error) {
+	return ParseSlice(s, ParseBoolExtended, opts...)
+}
+
+// ParseStringSlice splits a delimited list into strings.
+func ParseStringSlice(s string, opts ...ListOption) []string {
+	return newListOptions(opts).fields(s)
+}
+
+// ParseStringMap parses key-value pairs such as "a=1,b=2". Every element
+// must contain the key-value separator; later duplicates replace earlier ones.
+func ParseStringMap(s string, opts ...ListOption) (map[string]string, error) {
+	o := newListOptions(opts)
+	fields := o.fields(s)
+	result := make(map[string]string, len(fields))
+	for i, f := range fields {
+		key, value, ok := strings.Cut(f, o.kvSep)
+		if !ok {
+			return nil, fmt.Errorf("couldn't parse element %d (%q): missing %q", i, f, o.kvSep)
+		}
+		if o.trim {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("couldn't parse element %d (%q): empty key", i, f)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package strconvExt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ordinal formats n with its English ordinal suffix: 1st, 2nd, 3rd, 4th, 11th, 22nd.
+func Ordinal(n int) string {
+	s := strconv.Itoa(n)
+	// Take the last two digits from the decimal string, as -n overflows for
+	// the most negative int
+	last := int(s[len(s)-1] - '0')
+	tens := 0
+	if len(s) > 1 && s[len(s)-2] != '-' {
+		tens = int(s[len(s)-2] - '0')
+	}
+	switch {
+	case tens == 1:
+		return s + "th"
+	case last == 1:
+		return s + "st"
+	case last == 2:
+		return s + "nd"
+	case last == 3:
+		return s + "rd"
+	default:
+		return s + "th"
+	}
+}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// ToRoman formats n (1 to 3999) as a Roman numeral.
+func ToRoman(n int) (string, error) {
+	if n < 1 || n > 3999 {
+		return "", fmt.Errorf("couldn't format %d as a Roman numeral: must be between 1 and 3999", n)
+	}
+
+	var sb strings.Builder
+	for _, r := range romanNumerals {
+		for n >= r.value {
+			sb.WriteString(r.symbol)
+			n -= r.value
+		}
+	}
+	return sb.String(), nil
+}
+
+// FromRoman parses a Roman numeral. Input is case-insensitive but must be in
+// canonical form, so "IIII" and "IC" are rejected.
+func FromRoman(s string) (int, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	n, rest := 0, upper
+	for _, r := range romanNumerals {
+		for strings.HasPrefix(rest, r.symbol) {
+			n += r.value
+			rest = rest[len(r.symbol):]
+		}
+	}
+
+	// Re-encoding catches invalid orderings and repetitions
+	if canonical, err := ToRoman(n); rest != "" || err != nil || canonical != upper {
+		return 0, fmt.Errorf("couldn't parse %q as a Roman numeral", s)
+	}
+	return n, nil
+}
+
+var (
+	smallNumberWords = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	tensWords  = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scaleWords = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+)
+
+// NumberToWords spells out n in English using short-scale names, for example
+// "minus one thousand two hundred thirty-four".
+func NumberToWords(n int64) string {
+	if n == 0 {
+		return smallNumberWords[0]
+	}
+
+	var words []string
+	u := uint64(n)
+	if n < 0 {
+		words = append(words, "minus")
+		u = uint64(-n)
+	}
+
+	// Split into groups of three digits, most significant first
+	var groups []int
+	for ; u > 0; u /= 1000 {
+		groups = append(groups, int(u%1000))
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		if groups[i] == 0 {
+			continue
+		}
+		words = append(words, hundredsToWords(groups[i])...)
+		if scaleWords[i] != "" {
+			words = append(words, scaleWords[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// hundredsToWords spells out 1 to 999.
+func hundredsToWords(n int) []string {
+	var words []string
+	if n >= 100 {
+		words = append(words, smallNumberWords[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, smallNumberWords[n])
+	case n%10 == 0:
+		words = append(words, tensWords[n/10])
+	default:
+		words = append(words, tensWords[n/10]+"-"+smallNumberWords[n%10])
+	}
+	return words
+}
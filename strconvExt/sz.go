@@ -0,0 +1,100 @@
+package strconvExt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps lower-case unit suffixes to their multipliers. Bare letters
+// and "xB" forms are decimal (SI); "xi" and "xiB" forms are binary (IEC).
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"e":   1e18,
+	"eb":  1e18,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+	"ei":  1 << 60,
+	"eib": 1 << 60,
+}
+
+// ParseBytes parses a human-readable size such as "512", "10 MB", "1.5GiB"
+// or "2k" into a number of bytes. Units are case-insensitive; SI units
+// (kB, MB, ...) are powers of 1000 and IEC units (KiB, MiB, ...) powers of 1024.
+// Fractional results are rounded down to whole bytes.
+func ParseBytes(s string) (int64, error) {
+	str := strings.TrimSpace(s)
+	i := 0
+	for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.') {
+		i++
+	}
+	num, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("couldn't parse %q as a size: unknown unit %q", s, strings.TrimSpace(str[i:]))
+	}
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %q as a size", s)
+	}
+
+	bytes := value * multiplier
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("couldn't parse %q as a size: value out of range", s)
+	}
+	return int64(bytes), nil
+}
+
+// FormatBytes formats n as a human-readable size with one decimal place,
+// using IEC units (KiB, MiB, ...) when binary is true and SI units (kB, MB, ...)
+// otherwise. The output can be read back with ParseBytes.
+func FormatBytes(n int64, binary bool) string {
+	sign := ""
+	u := uint64(n)
+	if n < 0 {
+		sign = "-"
+		u = uint64(-n)
+	}
+
+	base, prefixes, suffix := uint64(1000), "kMGTPE", "B"
+	if binary {
+		base, prefixes, suffix = 1024, "KMGTPE", "iB"
+	}
+	if u < base {
+		return fmt.Sprintf("%s%d B", sign, u)
+	}
+
+	div, exp := base, 0
+	for v := u / base; v >= base; v /= base {
+		div *= base
+		exp++
+	}
+	v := float64(u) / float64(div)
+	// Move up a unit when rounding would print 1000.0 kB rather than 1.0 MB
+	if math.Round(v*10)/10 >= float64(base) && exp+1 < len(prefixes) {
+		v /= float64(base)
+		exp++
+	}
+	return fmt.Sprintf("%s%.1f %c%s", sign, v, prefixes[exp], suffix)
+}
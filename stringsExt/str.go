@@ -152,3 +152,59 @@ func IsNumeric(s string) bool {
 	}
 	return s != ""
 }
+
+// NaturalCompare compares two strings treating runs of ASCII digits as numbers,
+// so "item2" sorts before "item10". It returns -1, 0 or +1 like strings.Compare.
+// Numbers with equal value but different leading zeros sort shorter first.
+func NaturalCompare(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		ca, cb := a[i], b[j]
+		if isASCIIDigit(ca) && isASCIIDigit(cb) {
+			// Extract both digit runs
+			si, sj := i, j
+			for i < len(a) && isASCIIDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isASCIIDigit(b[j]) {
+				j++
+			}
+			na := strings.TrimLeft(a[si:i], "0")
+			nb := strings.TrimLeft(b[sj:j], "0")
+
+			// More significant digits means a larger number
+			if len(na) != len(nb) {
+				return compareInts(len(na), len(nb))
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			if c := compareInts(i-si, j-sj); c != 0 {
+				return c
+			}
+			continue
+		}
+
+		if ca != cb {
+			return compareInts(int(ca), int(cb))
+		}
+		i++
+		j++
+	}
+
+	return compareInts(len(a)-i, len(b)-j)
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
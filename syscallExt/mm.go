@@ -0,0 +1,111 @@
+package syscallExt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// MmapFlag selects how a file is mapped into memory
+type MmapFlag int
+
+const (
+	MmapReadOnly    MmapFlag = iota // Read-only; writing to the region faults
+	MmapReadWrite                   // Writes go to the file, see Flush
+	MmapCopyOnWrite                 // Writes are private to this process and never reach the file
+)
+
+// ErrUnmapped is returned when using a MappedFile after Unmap
+var ErrUnmapped = errors.New("file is not mapped")
+
+// MappedFile is a file mapped into memory. Its Bytes may be read (and, in the
+// writable modes, modified) directly, which is much faster than seeking and
+// reading for random access to large files.
+type MappedFile struct {
+	data    []byte
+	file    *os.File
+	flags   MmapFlag
+	mapping uintptr // File mapping handle on Windows
+}
+
+// Mmap maps the whole of the file at path into memory. The file's size must
+// not change while it is mapped. An empty file yields an empty region.
+func Mmap(path string, flags MmapFlag) (*MappedFile, error) {
+	mode := os.O_RDONLY
+	switch flags {
+	case MmapReadOnly, MmapCopyOnWrite:
+	case MmapReadWrite:
+		mode = os.O_RDWR
+	default:
+		return nil, fmt.Errorf("invalid mmap flags %d", flags)
+	}
+
+	f, err := os.OpenFile(path, mode, 0)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	size := info.Size()
+	if size != int64(int(size)) {
+		f.Close()
+		return nil, &Error{"mmap", errors.New("file too large to map"), path}
+	}
+
+	m := &MappedFile{file: f, flags: flags}
+	if size == 0 {
+		m.data = []byte{}
+		return m, nil
+	}
+	if err := m.mmap(int(size)); err != nil {
+		f.Close()
+		return nil, &Error{"mmap", err, path}
+	}
+	return m, nil
+}
+
+// Bytes returns the mapped region. It must not be used after Unmap.
+func (m *MappedFile) Bytes() []byte {
+	return m.data
+}
+
+// Len returns the size of the mapped region
+func (m *MappedFile) Len() int {
+	return len(m.data)
+}
+
+// Flush writes changes made through a read-write mapping back to the file and
+// waits for them to reach the disk. It does nothing for the other modes.
+func (m *MappedFile) Flush() error {
+	if m.data == nil {
+		return ErrUnmapped
+	}
+	if m.flags != MmapReadWrite || len(m.data) == 0 {
+		return nil
+	}
+	if err := m.flush(); err != nil {
+		return &Error{"msync", err, m.file.Name()}
+	}
+	return nil
+}
+
+// Unmap releases the mapping and closes the file. Changes in a read-write
+// mapping are written back, but not necessarily synced; call Flush first if
+// that matters. Calling Unmap more than once is harmless.
+func (m *MappedFile) Unmap() error {
+	if m.data == nil {
+		return nil
+	}
+
+	var err error
+	if len(m.data) > 0 {
+		if uerr := m.munmap(); uerr != nil {
+			err = &Error{"munmap", uerr, m.file.Name()}
+		}
+	}
+	m.data = nil
+	return errors.Join(err, m.file.Close())
+}
@@ -0,0 +1,10 @@
+//go:build darwin || netbsd || openbsd
+
+package syscallExt
+
+// These platforms have a unified buffer cache, so syncing the file also writes
+// out pages dirtied through a shared mapping. The syscall package offers no
+// msync wrapper here and raw system calls are discouraged.
+func (m *MappedFile) flush() error {
+	return m.file.Sync()
+}
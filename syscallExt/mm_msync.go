@@ -0,0 +1,16 @@
+//go:build linux || freebsd || dragonfly
+
+package syscallExt
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func (m *MappedFile) flush() error {
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&m.data[0])), uintptr(len(m.data)), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
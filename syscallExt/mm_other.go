@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package syscallExt
+
+import "errors"
+
+func (m *MappedFile) mmap(size int) error {
+	return errors.ErrUnsupported
+}
+
+func (m *MappedFile) flush() error {
+	return errors.ErrUnsupported
+}
+
+func (m *MappedFile) munmap() error {
+	return errors.ErrUnsupported
+}
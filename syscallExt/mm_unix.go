@@ -0,0 +1,27 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package syscallExt
+
+import "syscall"
+
+func (m *MappedFile) mmap(size int) error {
+	prot, flags := syscall.PROT_READ, syscall.MAP_SHARED
+	switch m.flags {
+	case MmapReadWrite:
+		prot |= syscall.PROT_WRITE
+	case MmapCopyOnWrite:
+		prot |= syscall.PROT_WRITE
+		flags = syscall.MAP_PRIVATE
+	}
+
+	data, err := syscall.Mmap(int(m.file.Fd()), 0, size, prot, flags)
+	if err != nil {
+		return err
+	}
+	m.data = data
+	return nil
+}
+
+func (m *MappedFile) munmap() error {
+	return syscall.Munmap(m.data)
+}
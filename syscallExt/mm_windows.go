@@ -0,0 +1,52 @@
+//go:build windows
+
+package syscallExt
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func (m *MappedFile) mmap(size int) error {
+	prot, access := uint32(syscall.PAGE_READONLY), uint32(syscall.FILE_MAP_READ)
+	switch m.flags {
+	case MmapReadWrite:
+		prot, access = syscall.PAGE_READWRITE, syscall.FILE_MAP_WRITE
+	case MmapCopyOnWrite:
+		prot, access = syscall.PAGE_WRITECOPY, syscall.FILE_MAP_COPY
+	}
+
+	size64 := uint64(size)
+	h, err := syscall.CreateFileMapping(syscall.Handle(m.file.Fd()), nil, prot, uint32(size64>>32), uint32(size64), nil)
+	if err != nil {
+		return err
+	}
+	addr, err := syscall.MapViewOfFile(h, access, 0, 0, uintptr(size))
+	if err != nil {
+		syscall.CloseHandle(h)
+		return err
+	}
+
+	// The view lives outside the Go heap, so the collector never moves or
+	// frees it and the address stays valid until munmap. Read it as an
+	// unsafe.Pointer in place rather than converting the uintptr back.
+	ptr := *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+	m.data = unsafe.Slice((*byte)(ptr), size)
+	m.mapping = uintptr(h)
+	return nil
+}
+
+func (m *MappedFile) flush() error {
+	if err := syscall.FlushViewOfFile(uintptr(unsafe.Pointer(&m.data[0])), uintptr(len(m.data))); err != nil {
+		return err
+	}
+	return syscall.FlushFileBuffers(syscall.Handle(m.file.Fd()))
+}
+
+func (m *MappedFile) munmap() error {
+	err := syscall.UnmapViewOfFile(uintptr(unsafe.Pointer(&m.data[0])))
+	if cerr := syscall.CloseHandle(syscall.Handle(m.mapping)); err == nil {
+		err = cerr
+	}
+	return err
+}
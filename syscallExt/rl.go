@@ -0,0 +1,71 @@
+package syscallExt
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+)
+
+// Resource identifies a per-process resource limit
+type Resource int
+
+const (
+	RlimitCPU    Resource = iota // CPU time in seconds
+	RlimitFSize                  // Largest file the process may create, in bytes
+	RlimitData                   // Size of the data segment, in bytes
+	RlimitStack                  // Size of the main thread's stack, in bytes
+	RlimitCore                   // Largest core dump, in bytes
+	RlimitNoFile                 // Number of open file descriptors
+	RlimitAS                     // Size of the address space, in bytes (not on OpenBSD)
+)
+
+// RlimInfinity is the value of an unlimited resource
+const RlimInfinity = ^uint64(0)
+
+// Rlimit holds the soft (Cur) and hard (Max) limits of a resource
+type Rlimit struct {
+	Cur uint64
+	Max uint64
+}
+
+// darwinOpenMax is OPEN_MAX on macOS, which setrlimit rejects exceeding for
+// RLIMIT_NOFILE even when the hard limit is unlimited.
+const darwinOpenMax = 10240
+
+// GetMaxOpenFiles returns the soft limit on open file descriptors. Platforms
+// without such a limit report RlimInfinity.
+func GetMaxOpenFiles() (uint64, error) {
+	lim, err := GetRlimit(RlimitNoFile)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return RlimInfinity, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return lim.Cur, nil
+}
+
+// SetMaxOpenFiles sets the soft limit on open file descriptors to n, capped at
+// the hard limit, and returns the limit now in effect. Servers typically call
+// it at startup with a large n to raise the limit as far as allowed. It is a
+// no-op on platforms without such a limit.
+func SetMaxOpenFiles(n uint64) (uint64, error) {
+	lim, err := GetRlimit(RlimitNoFile)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return RlimInfinity, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	lim.Cur = min(n, lim.Max)
+	err = SetRlimit(RlimitNoFile, lim)
+	if err != nil && runtime.GOOS == "darwin" && lim.Cur > darwinOpenMax && errors.Is(err, syscall.EINVAL) {
+		lim.Cur = darwinOpenMax
+		err = SetRlimit(RlimitNoFile, lim)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return lim.Cur, nil
+}
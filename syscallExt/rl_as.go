@@ -0,0 +1,9 @@
+//go:build linux || darwin || freebsd || netbsd || dragonfly
+
+package syscallExt
+
+import "syscall"
+
+func init() {
+	rlimitResources[RlimitAS] = syscall.RLIMIT_AS
+}
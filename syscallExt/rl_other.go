@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package syscallExt
+
+import "errors"
+
+// GetRlimit returns the current soft and hard limits of resource
+func GetRlimit(resource Resource) (Rlimit, error) {
+	return Rlimit{}, &Error{"getrlimit", errors.ErrUnsupported, ""}
+}
+
+// SetRlimit sets the soft and hard limits of resource
+func SetRlimit(resource Resource, lim Rlimit) error {
+	return &Error{"setrlimit", errors.ErrUnsupported, ""}
+}
@@ -0,0 +1,66 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package syscallExt
+
+import "syscall"
+
+var rlimitResources = map[Resource]int{
+	RlimitCPU:    syscall.RLIMIT_CPU,
+	RlimitFSize:  syscall.RLIMIT_FSIZE,
+	RlimitData:   syscall.RLIMIT_DATA,
+	RlimitStack:  syscall.RLIMIT_STACK,
+	RlimitCore:   syscall.RLIMIT_CORE,
+	RlimitNoFile: syscall.RLIMIT_NOFILE,
+}
+
+// GetRlimit returns the current soft and hard limits of resource
+func GetRlimit(resource Resource) (Rlimit, error) {
+	res, ok := rlimitResources[resource]
+	if !ok {
+		return Rlimit{}, &Error{"getrlimit", syscall.EINVAL, ""}
+	}
+
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(res, &rl); err != nil {
+		return Rlimit{}, &Error{"getrlimit", err, ""}
+	}
+	return Rlimit{Cur: fromRlim(rl.Cur), Max: fromRlim(rl.Max)}, nil
+}
+
+// SetRlimit sets the soft and hard limits of resource. Raising the hard
+// limit usually requires elevated privileges.
+func SetRlimit(resource Resource, lim Rlimit) error {
+	res, ok := rlimitResources[resource]
+	if !ok {
+		return &Error{"setrlimit", syscall.EINVAL, ""}
+	}
+
+	var rl syscall.Rlimit
+	toRlim(&rl.Cur, lim.Cur)
+	toRlim(&rl.Max, lim.Max)
+	if err := syscall.Setrlimit(res, &rl); err != nil {
+		return &Error{"setrlimit", err, ""}
+	}
+	return nil
+}
+
+// rlimInfinity is RLIM_INFINITY as a variable, since the constant is -1 on
+// some platforms and cannot be converted to uint64 directly.
+var rlimInfinity int64 = syscall.RLIM_INFINITY
+
+// fromRlim and toRlim convert between RlimInfinity and the platform's
+// Rlimit fields, which are signed on FreeBSD and DragonFly.
+func fromRlim[T int64 | uint64](v T) uint64 {
+	if v == T(rlimInfinity) {
+		return RlimInfinity
+	}
+	return uint64(v)
+}
+
+func toRlim[T int64 | uint64](dst *T, v uint64) {
+	if v == RlimInfinity {
+		*dst = T(rlimInfinity)
+		return
+	}
+	*dst = T(v)
+}
@@ -35,45 +35,6 @@ func GetPPID() int {
 	return syscall.Getppid()
 }
 
-// SetPriority sets the process priority
-func SetPriority(pid, priority int) error {
-	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, priority)
-}
-
-// GetPriority gets the process priority
-func GetPriority(pid int) (int, error) {
-	return syscall.Getpriority(syscall.PRIO_PROCESS, pid)
-}
-
-// CreateLockFile creates a lock file and returns its file descriptor
-func CreateLockFile(path string) (int, error) {
-	fd, err := syscall.Open(path, syscall.O_CREAT|syscall.O_RDWR, 0666)
-	if err != nil {
-		return -1, &Error{"open", err, path}
-	}
-
-	err = syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB)
-	if err != nil {
-		syscall.Close(fd)
-		return -1, &Error{"flock", err, path}
-	}
-
-	return fd, nil
-}
-
-// ReleaseLockFile releases a lock file by file descriptor
-func ReleaseLockFile(fd int, path string) error {
-	if err := syscall.Flock(fd, syscall.LOCK_UN); err != nil {
-		return &Error{"unlock", err, path}
-	}
-
-	if err := syscall.Close(fd); err != nil {
-		return &Error{"close", err, path}
-	}
-
-	return nil
-}
-
 // GetSystemInfo returns basic system information
 func GetSystemInfo() (string, error) {
 	// Using runtime package as a cross-platform alternative to syscall.Uname
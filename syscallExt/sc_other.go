@@ -0,0 +1,25 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package syscallExt
+
+import "errors"
+
+// SetPriority sets the process priority
+func SetPriority(pid, priority int) error {
+	return &Error{"setpriority", errors.ErrUnsupported, ""}
+}
+
+// GetPriority gets the process priority
+func GetPriority(pid int) (int, error) {
+	return 0, &Error{"getpriority", errors.ErrUnsupported, ""}
+}
+
+// CreateLockFile creates a lock file and returns its file descriptor
+func CreateLockFile(path string) (int, error) {
+	return -1, &Error{"flock", errors.ErrUnsupported, path}
+}
+
+// ReleaseLockFile releases a lock file by file descriptor
+func ReleaseLockFile(fd int, path string) error {
+	return &Error{"unlock", errors.ErrUnsupported, path}
+}
@@ -0,0 +1,133 @@
+//go:build windows
+
+package syscallExt
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx       = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx     = kernel32.NewProc("UnlockFileEx")
+	procSetPriorityClass = kernel32.NewProc("SetPriorityClass")
+	procGetPriorityClass = kernel32.NewProc("GetPriorityClass")
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+
+	processSetInformation          = 0x0200
+	processQueryLimitedInformation = 0x1000
+
+	idlePriorityClass        = 0x40
+	belowNormalPriorityClass = 0x4000
+	normalPriorityClass      = 0x20
+	aboveNormalPriorityClass = 0x8000
+	highPriorityClass        = 0x80
+)
+
+// SetPriority sets the process priority. The Unix nice value (-20 to 19) is
+// mapped to the closest Windows priority class; realtime is never used.
+func SetPriority(pid, priority int) error {
+	h, err := syscall.OpenProcess(processSetInformation, false, uint32(pid))
+	if err != nil {
+		return &Error{"OpenProcess", err, ""}
+	}
+	defer syscall.CloseHandle(h)
+
+	r, _, err := procSetPriorityClass.Call(uintptr(h), uintptr(niceToPriorityClass(priority)))
+	if r == 0 {
+		return &Error{"SetPriorityClass", err, ""}
+	}
+	return nil
+}
+
+// GetPriority gets the process priority as the nice value corresponding to
+// its Windows priority class.
+func GetPriority(pid int) (int, error) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return 0, &Error{"OpenProcess", err, ""}
+	}
+	defer syscall.CloseHandle(h)
+
+	r, _, err := procGetPriorityClass.Call(uintptr(h))
+	if r == 0 {
+		return 0, &Error{"GetPriorityClass", err, ""}
+	}
+	return priorityClassToNice(uint32(r)), nil
+}
+
+func niceToPriorityClass(nice int) uint32 {
+	switch {
+	case nice <= -15:
+		return highPriorityClass
+	case nice < 0:
+		return aboveNormalPriorityClass
+	case nice == 0:
+		return normalPriorityClass
+	case nice < 15:
+		return belowNormalPriorityClass
+	default:
+		return idlePriorityClass
+	}
+}
+
+func priorityClassToNice(class uint32) int {
+	switch class {
+	case idlePriorityClass:
+		return 19
+	case belowNormalPriorityClass:
+		return 10
+	case aboveNormalPriorityClass:
+		return -10
+	case highPriorityClass:
+		return -20
+	default:
+		return 0
+	}
+}
+
+// CreateLockFile creates a lock file and returns its file handle
+func CreateLockFile(path string) (int, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return -1, &Error{"open", err, path}
+	}
+	h, err := syscall.CreateFile(p,
+		syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE,
+		nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return -1, &Error{"open", err, path}
+	}
+
+	var ol syscall.Overlapped
+	r, _, err := procLockFileEx.Call(uintptr(h), lockfileExclusiveLock|lockfileFailImmediately,
+		0, 1, 0, uintptr(unsafe.Pointer(&ol)))
+	if r == 0 {
+		syscall.CloseHandle(h)
+		return -1, &Error{"LockFileEx", err, path}
+	}
+
+	return int(h), nil
+}
+
+// ReleaseLockFile releases a lock file by file handle
+func ReleaseLockFile(fd int, path string) error {
+	h := syscall.Handle(fd)
+	var ol syscall.Overlapped
+	r, _, err := procUnlockFileEx.Call(uintptr(h), 0, 1, 0, uintptr(unsafe.Pointer(&ol)))
+	if r == 0 {
+		return &Error{"unlock", err, path}
+	}
+
+	if err := syscall.CloseHandle(h); err != nil {
+		return &Error{"close", err, path}
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package syscallExt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Signals routes OS signals to handlers and coordinates graceful shutdown.
+//
+//	sigs := syscallExt.NewSignals(context.Background())
+//	sigs.Handle(syscall.SIGHUP, func(os.Signal) { reloadConfig() })
+//	sigs.DumpStacksOn(syscall.SIGUSR1, os.Stderr)
+//	sigs.OnShutdown("http", server.Shutdown)
+//	sigs.Start()
+//	go server.ListenAndServe()
+//	err := sigs.Wait()
+//
+// The first shutdown signal (SIGINT or SIGTERM by default) cancels Context and
+// runs the shutdown hooks; a second one exits the process immediately.
+type Signals struct {
+	mu              sync.Mutex
+	handlers        map[os.Signal][]func(os.Signal)
+	hooks           []shutdownHook
+	shutdownSignals []os.Signal
+	timeout         time.Duration
+	forceExit       bool
+	exit            func(code int) // os.Exit, replaceable for tests
+
+	ctx         context.Context
+	cancel      context.CancelCauseFunc
+	ch          chan os.Signal
+	started     bool
+	shutdown    sync.Once
+	done        chan struct{}
+	shutdownErr error
+}
+
+// SignalError is the cause of a Signals context canceled by a shutdown
+// signal, as returned by context.Cause.
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("received signal %v", e.Signal)
+}
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewSignals creates a signal manager whose Context is derived from parent.
+func NewSignals(parent context.Context) *Signals {
+	ctx, cancel := context.WithCancelCause(parent)
+	return &Signals{
+		handlers:        make(map[os.Signal][]func(os.Signal)),
+		shutdownSignals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+		timeout:         30 * time.Second,
+		forceExit:       true,
+		exit:            os.Exit,
+		ctx:             ctx,
+		cancel:          cancel,
+		ch:              make(chan os.Signal, 4),
+		done:            make(chan struct{}),
+	}
+}
+
+// Context returns a context that is cancelled when shutdown begins. If a
+// signal started the shutdown, context.Cause returns a *SignalError.
+func (s *Signals) Context() context.Context {
+	return s.ctx
+}
+
+// Handle registers fn to run each time sig is received. Handlers run one at a
+// time on the manager's goroutine, in registration order.
+func (s *Signals) Handle(sig os.Signal, fn func(os.Signal)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[sig] = append(s.handlers[sig], fn)
+	if s.started {
+		signal.Notify(s.ch, sig)
+	}
+}
+
+// DumpStacksOn writes the stacks of all goroutines to w whenever sig is received.
+func (s *Signals) DumpStacksOn(sig os.Signal, w io.Writer) {
+	s.Handle(sig, func(os.Signal) {
+		buf := make([]byte, 1<<20)
+		for {
+			n := runtime.Stack(buf, true)
+			if n < len(buf) {
+				buf = buf[:n]
+				break
+			}
+			buf = make([]byte, 2*len(buf))
+		}
+		fmt.Fprintf(w, "=== goroutine dump at %s ===\n%s\n", time.Now().Format(time.RFC3339), buf)
+	})
+}
+
+// ShutdownOn replaces the signals that trigger shutdown. Call before Start.
+func (s *Signals) ShutdownOn(sigs ...os.Signal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdownSignals = sigs
+}
+
+// ShutdownTimeout sets how long shutdown hooks may run in total (default 30s).
+func (s *Signals) ShutdownTimeout(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeout = d
+}
+
+// ForceExitOnSecondSignal controls whether a shutdown signal received while
+// shutdown is already in progress exits the process with status 1 (default true).
+func (s *Signals) ForceExitOnSecondSignal(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forceExit = enabled
+}
+
+// OnShutdown registers a hook to run during shutdown. Hooks run sequentially
+// in reverse order of registration, like deferred calls, so resources are
+// released in the opposite order to which they were set up.
+func (s *Signals) OnShutdown(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{name, fn})
+}
+
+// Start begins listening for signals.
+func (s *Signals) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	sigs := append([]os.Signal{}, s.shutdownSignals...)
+	for sig := range s.handlers {
+		sigs = append(sigs, sig)
+	}
+	signal.Notify(s.ch, sigs...)
+	go s.loop()
+}
+
+func (s *Signals) loop() {
+	shuttingDown := false
+	for {
+		select {
+		case sig := <-s.ch:
+			if s.isShutdownSignal(sig) {
+				if shuttingDown {
+					s.mu.Lock()
+					force := s.forceExit
+					s.mu.Unlock()
+					if force {
+						fmt.Fprintf(os.Stderr, "received %v during shutdown, exiting immediately\n", sig)
+						s.exit(1)
+					}
+					continue
+				}
+				shuttingDown = true
+				go s.shutdownWith(&SignalError{Signal: sig})
+				continue
+			}
+
+			s.mu.Lock()
+			handlers := append([]func(os.Signal){}, s.handlers[sig]...)
+			s.mu.Unlock()
+			for _, h := range handlers {
+				h(sig)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Signals) isShutdownSignal(sig os.Signal) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ss := range s.shutdownSignals {
+		if ss == sig {
+			return true
+		}
+	}
+	return false
+}
+
+// Shutdown cancels Context and runs the shutdown hooks, as if a shutdown
+// signal had been received. Only the first call has any effect; all calls
+// return the combined errors of the hooks.
+func (s *Signals) Shutdown() error {
+	return s.shutdownWith(context.Canceled)
+}
+
+func (s *Signals) shutdownWith(cause error) error {
+	s.shutdown.Do(func() {
+		s.cancel(cause)
+
+		s.mu.Lock()
+		hooks := append([]shutdownHook{}, s.hooks...)
+		timeout := s.timeout
+		s.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var errs []error
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if err := hooks[i].fn(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("shutdown hook %s: %w", hooks[i].name, err))
+			}
+		}
+		s.shutdownErr = errors.Join(errs...)
+
+		signal.Stop(s.ch)
+		close(s.done)
+	})
+	<-s.done
+	return s.shutdownErr
+}
+
+// Wait blocks until shutdown has completed and returns the hooks' errors.
+// Shutdown starts on a signal, a call to Shutdown, or cancellation of the
+// parent context.
+func (s *Signals) Wait() error {
+	select {
+	case <-s.done:
+	case <-s.ctx.Done():
+		s.Shutdown()
+	}
+	return s.shutdownErr
+}
@@ -0,0 +1,112 @@
+package testingExt
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/C0d3-5t3w/myT00L5/logExt"
+)
+
+// TestLogger is a logExt.Logger for tests. It logs everything, from DEBUG
+// up, through t.Logf, so messages only show for failing or verbose tests,
+// and records each entry for assertions. Install it as the default logger,
+// or use CaptureLogs, to capture package-level and Named logging from the
+// code under test.
+type TestLogger struct {
+	*logExt.Logger
+	t       testing.TB
+	mu      sync.Mutex
+	entries []logExt.Entry
+	done    bool
+}
+
+// NewTestLogger returns a TestLogger that logs to t.
+func NewTestLogger(t testing.TB) *TestLogger {
+	tl := &TestLogger{t: t}
+	tl.Logger = logExt.NewEntryLogger(tl.record)
+	// t.Logf panics once the test has finished, so stop forwarding then.
+	t.Cleanup(func() {
+		tl.mu.Lock()
+		defer tl.mu.Unlock()
+		tl.done = true
+	})
+	return tl
+}
+
+func (tl *TestLogger) record(e logExt.Entry) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.entries = append(tl.entries, e)
+	if !tl.done {
+		tl.t.Logf("%s", e)
+	}
+}
+
+// Install makes tl the default logger, so package-level functions and Named
+// loggers log to it, and returns a function that restores the previous
+// default. Tests that install a logger must not run in parallel.
+func (tl *TestLogger) Install() (restore func()) {
+	previous := logExt.GetDefaultLogger()
+	logExt.SetDefaultLogger(tl.Logger)
+	return func() {
+		logExt.SetDefaultLogger(previous)
+	}
+}
+
+// Entries returns the entries logged so far.
+func (tl *TestLogger) Entries() []logExt.Entry {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	return append([]logExt.Entry(nil), tl.entries...)
+}
+
+// Reset forgets the entries logged so far.
+func (tl *TestLogger) Reset() {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.entries = nil
+}
+
+// Logged reports whether an entry at level contains substr.
+func (tl *TestLogger) Logged(level int, substr string) bool {
+	for _, e := range tl.Entries() {
+		if e.Level == level && strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertLogged fails the test unless an entry at level contains substr.
+func (tl *TestLogger) AssertLogged(level int, substr string) bool {
+	tl.t.Helper()
+	if tl.Logged(level, substr) {
+		return true
+	}
+	tl.t.Errorf("expected a %s entry containing %q, got:\n%s", logExt.LevelName(level), substr, tl.dump())
+	return false
+}
+
+// AssertNotLogged fails the test if an entry at level contains substr.
+func (tl *TestLogger) AssertNotLogged(level int, substr string) bool {
+	tl.t.Helper()
+	if !tl.Logged(level, substr) {
+		return true
+	}
+	tl.t.Errorf("expected no %s entry containing %q, got:\n%s", logExt.LevelName(level), substr, tl.dump())
+	return false
+}
+
+// dump lists the recorded entries, one per line
+func (tl *TestLogger) dump() string {
+	entries := tl.Entries()
+	if len(entries) == 0 {
+		return "  (no entries)"
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = "  " + e.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -9,6 +9,8 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/C0d3-5t3w/myT00L5/runtimeExt"
 )
 
 // Assert fails the test if the condition is false.
@@ -153,6 +155,25 @@ func Contains(t *testing.T, str, substr string, msgAndArgs ...interface{}) {
 	}
 }
 
+// NoGoroutineLeaks fails the test if goroutines started during it are still
+// running when it ends. Goroutines whose top function starts with one of the
+// ignore prefixes are not reported.
+func NoGoroutineLeaks(t *testing.T, ignore ...string) {
+	t.Helper()
+	runtimeExt.VerifyNoLeaks(t, &runtimeExt.LeakOptions{IgnoreTopFunctions: ignore})
+}
+
+// CaptureLogs routes logExt's default and named loggers to t for the rest of
+// the test, so the code under test doesn't write to stderr, and returns the
+// logger for assertions on what was logged. The previous default logger is
+// restored when the test ends. Tests using it must not run in parallel.
+func CaptureLogs(t *testing.T) *TestLogger {
+	t.Helper()
+	tl := NewTestLogger(t)
+	t.Cleanup(tl.Install())
+	return tl
+}
+
 // helper function to check if a value is nil
 func isNil(value interface{}) bool {
 	if value == nil {
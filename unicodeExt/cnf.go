@@ -0,0 +1,155 @@
+package unicodeExt
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps characters to their prototypes, a subset of the UTS #39
+// confusables.txt data covering the homoglyphs most used in spoofing: Latin
+// lookalikes from Cyrillic, Greek, Armenian and Cherokee, digits and
+// punctuation. Compatibility variants such as full-width and mathematical
+// letters are handled by ToSkeleton through NFKD instead.
+var confusables = map[rune]string{
+	// Latin, digits and punctuation
+	'0': "O", '1': "l", 'I': "l", '|': "l", 'm': "rn",
+	'ı': "i", 'ɩ': "i", 'ɑ': "a", 'ɡ': "g", 'ǀ': "l", 'ȷ': "j",
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '−': "-", '˗': "-",
+	'‘': "'", '’': "'", 'ʼ': "'", '′': "'", '‚': ",", '٫': ",",
+	'⁄': "/", '∕': "/", '∖': "\\", 'ǃ': "!", ';': ";", '։': ":", '∶': ":",
+
+	// Cyrillic
+	'а': "a", 'в': "B", 'е': "e", 'о': "o", 'р': "p", 'с': "c", 'у': "y",
+	'х': "x", 'і': "i", 'ј': "j", 'ѕ': "s", 'һ': "h", 'ԁ': "d", 'ԛ': "q",
+	'ԝ': "w", 'ӏ': "l", 'ь': "b", 'ү': "y", 'ҽ': "e",
+	'А': "A", 'В': "B", 'Е': "E", 'К': "K", 'М': "M", 'Н': "H", 'О': "O",
+	'Р': "P", 'С': "C", 'Т': "T", 'Х': "X", 'У': "Y", 'Ѕ': "S", 'І': "l",
+	'Ј': "J", 'Ԍ': "G", 'Ү': "Y", 'Ԛ': "Q", 'Ԝ': "W", 'Ӏ': "l", 'З': "3",
+
+	// Greek
+	'Α': "A", 'Β': "B", 'Ε': "E", 'Ζ': "Z", 'Η': "H", 'Ι': "l", 'Κ': "K",
+	'Μ': "M", 'Ν': "N", 'Ο': "O", 'Ρ': "P", 'Τ': "T", 'Υ': "Y", 'Χ': "X",
+	'α': "a", 'ι': "i", 'ν': "v", 'ο': "o", 'ρ': "p", 'υ': "u", 'ϲ': "c",
+	'ϳ': "j", 'Ϲ': "C", 'Ϳ': "J",
+
+	// Armenian
+	'օ': "o", 'ս': "u", 'հ': "h", 'զ': "q", 'ց': "g", 'Տ': "S", 'Օ': "O",
+	'Ս': "U", 'Ւ': "l", 'ո': "n",
+
+	// Cherokee
+	'Ꭺ': "A", 'Ᏼ': "B", 'Ꮯ': "C", 'Ꭼ': "E", 'Ꮋ': "H", 'Ꭻ': "J", 'Ꮶ': "K",
+	'Ꮇ': "M", 'Ꮲ': "P", 'Ꮪ': "S", 'Ꭲ': "T", 'Ꮃ': "W", 'Ꮓ': "Z", 'Ꮮ': "L",
+	'Ꮐ': "G", 'Ꭱ': "R", 'Ꮩ': "V",
+}
+
+// ToSkeleton returns the UTS #39 skeleton of s: the string with every
+// character replaced by a prototype it could be mistaken for, so that two
+// strings that look alike, such as "paypal" and "рауpal" with Cyrillic
+// letters, have the same skeleton. Skeletons are for comparison only and
+// are not meant to be displayed. The mapping data is the subset described
+// at confusables, plus compatibility decompositions.
+func ToSkeleton(s string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if proto, ok := confusables[r]; ok {
+			sb.WriteString(proto)
+			continue
+		}
+		if d := norm.NFKD.String(string(r)); d != string(r) {
+			// Map each part of the decomposition too, as in "ｌ" → "l" → "l"
+			for _, dr := range d {
+				if proto, ok := confusables[dr]; ok {
+					sb.WriteString(proto)
+				} else {
+					sb.WriteRune(dr)
+				}
+			}
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return norm.NFD.String(sb.String())
+}
+
+// IsConfusable reports whether a and b could be mistaken for each other,
+// that is, whether they have the same skeleton. Identical strings are
+// trivially confusable.
+func IsConfusable(a, b string) bool {
+	return ToSkeleton(a) == ToSkeleton(b)
+}
+
+// protoScripts maps each prototype to the scripts that have a character
+// mapping to it, built from confusables and the ASCII letters and digits.
+var (
+	protoScripts     map[string]map[string]bool
+	protoScriptsOnce sync.Once
+)
+
+func buildProtoScripts() {
+	protoScripts = make(map[string]map[string]bool)
+	add := func(r rune, proto string) {
+		if protoScripts[proto] == nil {
+			protoScripts[proto] = make(map[string]bool)
+		}
+		protoScripts[proto][ScriptOf(r)] = true
+	}
+	for r := rune('0'); r <= 'z'; r++ {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		if proto, ok := confusables[r]; ok {
+			add(r, proto)
+		} else {
+			add(r, string(r))
+		}
+	}
+	for r, proto := range confusables {
+		add(r, proto)
+	}
+}
+
+// ContainsMixedScriptConfusables reports whether s contains a word that
+// mixes scripts in a way typical of spoofing: its letters come from more
+// than one script and a letter outside the word's dominant script looks like
+// a letter of that script, as with a Cyrillic "а" in an otherwise Latin
+// "pаypal". Words are checked separately, so text that merely contains words
+// in different scripts, or legitimate mixtures such as kana with Han, is not
+// reported.
+func ContainsMixedScriptConfusables(s string) bool {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if wordHasMixedScriptConfusable(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// wordHasMixedScriptConfusable implements ContainsMixedScriptConfusables for a single word.
+func wordHasMixedScriptConfusable(word string) bool {
+	dominant := DominantScript(word)
+	if isNeutralScript(dominant) {
+		return false
+	}
+
+	protoScriptsOnce.Do(buildProtoScripts)
+	for _, r := range word {
+		script := ScriptOf(r)
+		if isNeutralScript(script) || script == dominant {
+			continue
+		}
+		proto, ok := confusables[r]
+		if !ok {
+			proto = string(r)
+		}
+		if protoScripts[proto][dominant] {
+			return true
+		}
+	}
+	return false
+}
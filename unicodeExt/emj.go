@@ -0,0 +1,167 @@
+package unicodeExt
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	zeroWidthJoiner   = '\u200D'
+	variationEmoji    = '\uFE0F' // VS16, requests emoji presentation
+	variationText     = '\uFE0E' // VS15, requests text presentation
+	combiningKeycap   = '\u20E3'
+	blackFlag         = '\U0001F3F4'
+	tagCancel         = '\U000E007F'
+	emojiModifierLow  = '\U0001F3FB'
+	emojiModifierHigh = '\U0001F3FF'
+)
+
+// IsEmojiPresentation reports whether r is displayed as an emoji by default,
+// without a following U+FE0F.
+func IsEmojiPresentation(r rune) bool {
+	return unicode.Is(EmojiPresentation, r)
+}
+
+// IsEmojiModifier reports whether r is one of the five skin tone modifiers.
+func IsEmojiModifier(r rune) bool {
+	return r >= emojiModifierLow && r <= emojiModifierHigh
+}
+
+// IsExtendedPictographic reports whether r has the Extended_Pictographic
+// property, which also covers code points reserved for future emoji.
+func IsExtendedPictographic(r rune) bool {
+	return unicode.Is(ExtendedPictographic, r)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// emojiElement returns the length of the emoji element at the start of s, or
+// 0 if there is none. An element is a flag, keycap, tag sequence, modifier
+// sequence or single emoji with optional U+FE0F (UTS #51, ED-15 to ED-17).
+// Inside a ZWJ sequence (inZWJ) text-default emoji need no U+FE0F.
+func emojiElement(s string, inZWJ bool) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 {
+		return 0
+	}
+	next, m := utf8.DecodeRuneInString(s[n:])
+
+	switch {
+	case isRegionalIndicator(r):
+		if isRegionalIndicator(next) {
+			return n + m
+		}
+		return 0
+	case r < 0x80:
+		// Keycap: [0-9#*] FE0F? 20E3
+		if !unicode.Is(EmojiTable, r) {
+			return 0
+		}
+		i := n
+		if next == variationEmoji {
+			i += m
+			next, m = utf8.DecodeRuneInString(s[i:])
+		}
+		if next == combiningKeycap {
+			return i + m
+		}
+		return 0
+	case r == blackFlag && next >= 0xE0020 && next < tagCancel:
+		// Tag sequence, as in the flags of England or Scotland
+		i := n
+		for {
+			t, k := utf8.DecodeRuneInString(s[i:])
+			switch {
+			case t >= 0xE0020 && t < tagCancel:
+				i += k
+				continue
+			case t == tagCancel:
+				return i + k
+			}
+			return 0
+		}
+	case !unicode.Is(EmojiTable, r) || IsEmojiModifier(r):
+		return 0
+	case IsEmojiModifier(next) && unicode.Is(EmojiModifierBase, r):
+		return n + m
+	case next == variationEmoji:
+		return n + m
+	case next == variationText:
+		return 0
+	case inZWJ || IsEmojiPresentation(r):
+		return n
+	}
+	return 0
+}
+
+// emojiSequence returns the length of the emoji sequence, possibly joined by
+// ZWJ, at the start of s, or 0 if s does not start with an emoji.
+func emojiSequence(s string) int {
+	n := emojiElement(s, false)
+	if n == 0 {
+		// A text-default emoji still starts a ZWJ sequence, as in "❤‍🔥"
+		if n = emojiElement(s, true); n == 0 {
+			return 0
+		}
+		r, z := utf8.DecodeRuneInString(s[n:])
+		if r != zeroWidthJoiner || emojiElement(s[n+z:], true) == 0 {
+			return 0
+		}
+	}
+	for {
+		r, z := utf8.DecodeRuneInString(s[n:])
+		if r != zeroWidthJoiner {
+			return n
+		}
+		m := emojiElement(s[n+z:], true)
+		if m == 0 {
+			return n
+		}
+		n += z + m
+	}
+}
+
+// IsEmojiSequence reports whether s is exactly one emoji: a single emoji
+// with emoji presentation, a keycap such as "1️⃣", a flag, a skin tone
+// modifier sequence such as "👍🏽", a tag sequence, or a ZWJ sequence such as
+// "👩‍💻". Characters that default to text presentation, such as "❤", count
+// only when followed by U+FE0F.
+func IsEmojiSequence(s string) bool {
+	return s != "" && emojiSequence(s) == len(s)
+}
+
+// ExtractEmoji returns the emoji sequences in s in order of appearance.
+func ExtractEmoji(s string) []string {
+	var found []string
+	for i := 0; i < len(s); {
+		if n := emojiSequence(s[i:]); n > 0 {
+			found = append(found, s[i:i+n])
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return found
+}
+
+// StripEmoji removes the emoji sequences from s, along with any stray
+// joiners, variation selectors and skin tone modifiers they leave behind.
+func StripEmoji(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if n := emojiSequence(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r != zeroWidthJoiner && r != variationEmoji && !IsEmojiModifier(r) {
+			sb.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return sb.String()
+}
@@ -0,0 +1,174 @@
+package unicodeExt
+
+import (
+	"iter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// graphemeBreak is a Grapheme_Cluster_Break property value (UAX #29).
+type graphemeBreak uint8
+
+const (
+	gbOther graphemeBreak = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+// graphemeBreakOf returns the Grapheme_Cluster_Break property of r, derived
+// from the general category tables plus the exceptions in tbl.go.
+func graphemeBreakOf(r rune) graphemeBreak {
+	switch {
+	case r < 0x7F:
+		// Fast path for ASCII
+		switch {
+		case r == '\r':
+			return gbCR
+		case r == '\n':
+			return gbLF
+		case r < 0x20:
+			return gbControl
+		}
+		return gbOther
+	case r == 0x200D:
+		return gbZWJ
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gbRegionalIndicator
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		// Emoji skin tone modifiers
+		return gbExtend
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gbT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case r == 0x0E33, r == 0x0EB3:
+		return gbSpacingMark
+	case unicode.Is(graphemePrepend, r):
+		return gbPrepend
+	case unicode.In(r, unicode.Mn, unicode.Me, otherGraphemeExtend):
+		return gbExtend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	case unicode.Is(unicode.Mc, r) && !unicode.Is(spacingMarkExceptions, r):
+		return gbSpacingMark
+	}
+	return gbOther
+}
+
+// nextGrapheme returns the length in bytes of the extended grapheme cluster
+// at the start of s.
+func nextGrapheme(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	prev := graphemeBreakOf(r)
+	riCount := 0
+	if prev == gbRegionalIndicator {
+		riCount = 1
+	}
+	// GB11 state: the cluster so far is ExtPict Extend*, optionally then ZWJ
+	pictographic := unicode.Is(ExtendedPictographic, r)
+
+	i := size
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		cur := graphemeBreakOf(r)
+		curPict := unicode.Is(ExtendedPictographic, r)
+
+		if graphemeBoundary(prev, cur, riCount, pictographic, curPict) {
+			break
+		}
+
+		switch {
+		case cur == gbRegionalIndicator:
+			riCount++
+		case curPict:
+			pictographic = true
+		case cur != gbExtend && cur != gbZWJ:
+			pictographic = false
+		}
+		if prev == gbZWJ && !curPict {
+			pictographic = false
+		}
+		prev = cur
+		i += size
+	}
+	return i
+}
+
+// graphemeBoundary applies rules GB3 to GB999 of UAX #29 between two runes.
+func graphemeBoundary(prev, cur graphemeBreak, riCount int, pictographic, curPict bool) bool {
+	switch {
+	case prev == gbCR && cur == gbLF: // GB3
+		return false
+	case prev == gbControl || prev == gbCR || prev == gbLF: // GB4
+		return true
+	case cur == gbControl || cur == gbCR || cur == gbLF: // GB5
+		return true
+	case prev == gbL && (cur == gbL || cur == gbV || cur == gbLV || cur == gbLVT): // GB6
+		return false
+	case (prev == gbLV || prev == gbV) && (cur == gbV || cur == gbT): // GB7
+		return false
+	case (prev == gbLVT || prev == gbT) && cur == gbT: // GB8
+		return false
+	case cur == gbExtend || cur == gbZWJ: // GB9
+		return false
+	case cur == gbSpacingMark: // GB9a
+		return false
+	case prev == gbPrepend: // GB9b
+		return false
+	case prev == gbZWJ && curPict && pictographic: // GB11
+		return false
+	case prev == gbRegionalIndicator && cur == gbRegionalIndicator: // GB12, GB13
+		return riCount%2 == 0
+	}
+	return true // GB999
+}
+
+// Graphemes yields the extended grapheme clusters of s (UAX #29), the units
+// a user perceives as single characters: "e" plus a combining accent, a
+// flag made of two regional indicators, or an emoji ZWJ sequence such as a
+// family are each yielded whole. The Unicode 15.1 rule for Indic conjuncts
+// (GB9c) is not applied.
+func Graphemes(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s != "" {
+			n := nextGrapheme(s)
+			if !yield(s[:n]) {
+				return
+			}
+			s = s[n:]
+		}
+	}
+}
+
+// GraphemeCount returns the number of extended grapheme clusters in s, which
+// is what a user would count as characters.
+func GraphemeCount(s string) int {
+	count := 0
+	for s != "" {
+		s = s[nextGrapheme(s):]
+		count++
+	}
+	return count
+}
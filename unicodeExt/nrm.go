@@ -0,0 +1,61 @@
+package unicodeExt
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeNFC returns s in Normalization Form C (canonical composition),
+// the form most text on the web and in file names uses.
+func NormalizeNFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NormalizeNFD returns s in Normalization Form D (canonical decomposition),
+// where accented letters are split into a base letter and combining marks.
+func NormalizeNFD(s string) string {
+	return norm.NFD.String(s)
+}
+
+// NormalizeNFKC returns s in Normalization Form KC (compatibility
+// composition), which also folds variants such as "ﬁ" to "fi" and
+// full-width "Ａ" to "A". It suits identifiers and search keys.
+func NormalizeNFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// NormalizeNFKD returns s in Normalization Form KD (compatibility decomposition).
+func NormalizeNFKD(s string) string {
+	return norm.NFKD.String(s)
+}
+
+// EqualsNormalized reports whether a and b are canonically equivalent, so
+// "é" written as one code point equals "e" followed by a combining acute.
+func EqualsNormalized(a, b string) bool {
+	return norm.NFC.String(a) == norm.NFC.String(b)
+}
+
+// StripAccents removes combining marks after canonical decomposition, so
+// "Crème Brûlée" becomes "Creme Brulee". Letters that are distinct rather
+// than accented, such as "ø" or "ł", are left alone.
+func StripAccents(s string) string {
+	return norm.NFC.String(strings.Map(dropMark, norm.NFD.String(s)))
+}
+
+// FoldAndStripAccents returns a key for loose matching of user input: it
+// applies compatibility decomposition, removes combining marks and full
+// case folding, so "Straße", "STRASSE" and "strasse" all give "strasse".
+func FoldAndStripAccents(s string) string {
+	stripped := strings.Map(dropMark, norm.NFKD.String(s))
+	return norm.NFC.String(cases.Fold().String(stripped))
+}
+
+func dropMark(r rune) rune {
+	if unicode.Is(unicode.Mn, r) {
+		return -1
+	}
+	return r
+}
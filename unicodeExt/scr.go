@@ -0,0 +1,128 @@
+package unicodeExt
+
+import (
+	"cmp"
+	"sort"
+	"sync"
+	"unicode"
+)
+
+// scriptRange is a run of code points belonging to one script.
+type scriptRange struct {
+	lo, hi rune
+	script string
+}
+
+var (
+	scriptIndexOnce sync.Once
+	scriptIndex     []scriptRange // Sorted, non-overlapping
+)
+
+// buildScriptIndex flattens unicode.Scripts into one sorted slice so ScriptOf
+// is a binary search instead of a scan of every script table.
+func buildScriptIndex() {
+	for name, table := range unicode.Scripts {
+		add := func(lo, hi, stride rune) {
+			if stride == 1 {
+				scriptIndex = append(scriptIndex, scriptRange{lo, hi, name})
+				return
+			}
+			for r := lo; r <= hi; r += stride {
+				scriptIndex = append(scriptIndex, scriptRange{r, r, name})
+			}
+		}
+		for _, r := range table.R16 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+		for _, r := range table.R32 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+	}
+	sort.Slice(scriptIndex, func(i, j int) bool {
+		return scriptIndex[i].lo < scriptIndex[j].lo
+	})
+}
+
+// ScriptOf returns the name of the Unicode script of r as used by
+// unicode.Scripts, such as "Latin", "Devanagari" or "Han". Punctuation,
+// digits and symbols shared between scripts are "Common", combining marks
+// that take the script of their base are "Inherited", and unassigned code
+// points are "Unknown".
+func ScriptOf(r rune) string {
+	scriptIndexOnce.Do(buildScriptIndex)
+	i := sort.Search(len(scriptIndex), func(i int) bool {
+		return scriptIndex[i].hi >= r
+	})
+	if i < len(scriptIndex) && scriptIndex[i].lo <= r {
+		return scriptIndex[i].script
+	}
+	return "Unknown"
+}
+
+// isNeutralScript reports whether a script name belongs to no particular
+// writing system
+func isNeutralScript(script string) bool {
+	return script == "Common" || script == "Inherited" || script == "Unknown"
+}
+
+// DominantScript returns the script with the most runes in s, ignoring
+// Common, Inherited and Unknown runes; ties go to the script seen first.
+// It returns "Common" if s has no runes of a specific script and "" if s is
+// empty.
+func DominantScript(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	var order []string
+	for _, r := range s {
+		script := ScriptOf(r)
+		if isNeutralScript(script) {
+			continue
+		}
+		if counts[script] == 0 {
+			order = append(order, script)
+		}
+		counts[script]++
+	}
+
+	best := "Common"
+	for _, script := range order {
+		if counts[script] > counts[best] {
+			best = script
+		}
+	}
+	return best
+}
+
+// ScriptRun is a maximal run of text in one script.
+type ScriptRun struct {
+	Script string
+	Text   string
+}
+
+// SplitByScript splits s into runs of the same script, for example to pick a
+// font or tokenizer per run. Common and Inherited runes, such as spaces,
+// punctuation and combining marks, join the run before them, or the first
+// run if they lead the string; a string of only such runes is one run with
+// script "Common".
+func SplitByScript(s string) []ScriptRun {
+	var runs []ScriptRun
+	start, current := 0, ""
+	for i, r := range s {
+		script := ScriptOf(r)
+		if isNeutralScript(script) || script == current {
+			continue
+		}
+		if current != "" {
+			runs = append(runs, ScriptRun{current, s[start:i]})
+			start = i
+		}
+		current = script
+	}
+	if s != "" {
+		runs = append(runs, ScriptRun{cmp.Or(current, "Common"), s[start:]})
+	}
+	return runs
+}
@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"strings"
 	"unicode"
-	"unicode/utf8"
 )
 
 // UnicodeData represents a parsed entry from the Unicode Character Database.
@@ -29,17 +28,11 @@ type UnicodeData struct {
 	SimpleTitleCase rune
 }
 
-// IsEmoji reports whether the rune is an emoji character.
+// IsEmoji reports whether the rune has the Unicode Emoji property. The ASCII
+// digits, '#' and '*' are excluded, since they are emoji only when followed by
+// U+20E3 as a keycap; use IsEmojiSequence to check whole sequences.
 func IsEmoji(r rune) bool {
-	return unicode.In(r, &unicode.RangeTable{
-		R32: []unicode.Range32{
-			{0x1F600, 0x1F64F, 1}, // Emoticons
-			{0x1F300, 0x1F5FF, 1}, // Misc Symbols and Pictographs
-			{0x1F680, 0x1F6FF, 1}, // Transport and Map
-			{0x1F900, 0x1F9FF, 1}, // Supplemental Symbols and Pictographs
-		},
-		LatinOffset: 0,
-	})
+	return r >= 0x80 && unicode.Is(EmojiTable, r)
 }
 
 // IsPictographic reports whether the rune is a pictographic character.
@@ -203,63 +196,33 @@ func IsHangul(r rune) bool {
 		(r >= 0x1100 && r <= 0x11FF) // Hangul Jamo
 }
 
-// CountUniqueScripts counts how many different Unicode scripts are used in the string.
+// CountUniqueScripts counts how many different Unicode scripts are used in
+// the string, not counting Common, Inherited and unassigned code points.
 func CountUniqueScripts(s string) int {
 	scripts := make(map[string]bool)
-
 	for _, r := range s {
-		switch {
-		case unicode.Is(unicode.Latin, r):
-			scripts["Latin"] = true
-		case unicode.Is(unicode.Cyrillic, r):
-			scripts["Cyrillic"] = true
-		case unicode.Is(unicode.Greek, r):
-			scripts["Greek"] = true
-		case unicode.Is(unicode.Han, r):
-			scripts["Han"] = true
-		case IsHangul(r):
-			scripts["Hangul"] = true
-		case IsArabic(r):
-			scripts["Arabic"] = true
-		case IsHebrew(r):
-			scripts["Hebrew"] = true
-		case IsThai(r):
-			scripts["Thai"] = true
+		if script := ScriptOf(r); !isNeutralScript(script) {
+			scripts[script] = true
 		}
 	}
-
 	return len(scripts)
 }
 
-// Truncate truncates a string to the given max length, making sure not to break
-// a grapheme cluster (character + combining marks).
+// Truncate truncates a string to at most maxLength bytes, cutting only on
+// extended grapheme cluster boundaries so that combining marks, emoji ZWJ
+// sequences and flags are never split.
 func Truncate(s string, maxLength int) string {
 	if len(s) <= maxLength {
 		return s
 	}
 
-	// Truncate to maxLength bytes, but ensure we don't break a UTF-8 sequence
-	truncated := s[:maxLength]
-	for len(truncated) > 0 && !utf8.RuneStart(truncated[len(truncated)-1]) {
-		truncated = truncated[:len(truncated)-1]
-	}
-
-	// Check if we need to trim a base character to avoid orphaned combining marks
-	lastCharPos := 0
-	for i, r := range truncated {
-		if !unicode.Is(unicode.Mn, r) { // Mn = nonspacing marks
-			lastCharPos = i
+	end := 0
+	for end < len(s) {
+		n := nextGrapheme(s[end:])
+		if end+n > maxLength {
+			break
 		}
+		end += n
 	}
-
-	nextIndex := len(truncated)
-	if nextIndex < len(s) {
-		r, _ := utf8.DecodeRuneInString(s[nextIndex:])
-		if unicode.Is(unicode.Mn, r) {
-			// Trim to the last non-combining character
-			return truncated[:lastCharPos]
-		}
-	}
-
-	return truncated
+	return s[:end]
 }
@@ -0,0 +1,90 @@
+package unicodeExt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RuneWidth returns the number of terminal columns r occupies: 2 for wide
+// and full-width characters (UAX #11) and emoji shown as pictures, 0 for
+// combining marks, format characters, controls and Hangul medial vowels and
+// final consonants, and 1 otherwise. Characters of ambiguous width, such as
+// Greek and Cyrillic letters, count as 1, as in non-East Asian locales.
+func RuneWidth(r rune) int {
+	switch {
+	case r >= 0x20 && r < 0x7F:
+		return 1
+	case r < 0x20 || r >= 0x7F && r < 0xA0:
+		return 0
+	case r == 0xAD:
+		return 1 // Soft hyphen, shown by most terminals
+	case r >= 0x1160 && r <= 0x11FF, r >= 0xD7B0 && r <= 0xD7FF:
+		return 0 // Conjoining jamo that attach to a preceding syllable
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Zl, unicode.Zp):
+		return 0
+	case unicode.Is(eastAsianWide, r):
+		return 2
+	}
+	return 1
+}
+
+// graphemeWidth returns the width of one extended grapheme cluster: 2 for an
+// emoji sequence, otherwise the widest rune it contains.
+func graphemeWidth(g string) int {
+	if IsEmojiSequence(g) {
+		return 2
+	}
+	width := 0
+	for _, r := range g {
+		width = max(width, RuneWidth(r))
+	}
+	return width
+}
+
+// StringWidth returns the number of terminal columns s occupies, measuring
+// each grapheme cluster so that accented letters, flags and emoji ZWJ
+// sequences count as the single characters they are displayed as.
+func StringWidth(s string) int {
+	width := 0
+	for g := range Graphemes(s) {
+		width += graphemeWidth(g)
+	}
+	return width
+}
+
+// PadToWidth appends spaces to s until it is width columns wide, so that
+// columns containing CJK or emoji line up. Strings already at least that
+// wide are returned unchanged.
+func PadToWidth(s string, width int) string {
+	if w := StringWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// TruncateToWidth shortens s to at most width columns, cutting between
+// grapheme clusters and ending with tail (such as "…") when anything was
+// removed. The tail counts towards the width. Strings that fit are
+// returned unchanged.
+func TruncateToWidth(s string, width int, tail string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+
+	limit := width - StringWidth(tail)
+	if limit < 0 {
+		return ""
+	}
+	var sb strings.Builder
+	used := 0
+	for g := range Graphemes(s) {
+		w := graphemeWidth(g)
+		if used+w > limit {
+			break
+		}
+		sb.WriteString(g)
+		used += w
+	}
+	sb.WriteString(tail)
+	return sb.String()
+}